@@ -0,0 +1,269 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/util"
+)
+
+type (
+	// An allow-list of the fields clients may sort by and select, e.g. from the sort=-created_at,name and fields=id,name
+	// query parameters of a list endpoint. Every field is mapped to a column so clients can never reference columns
+	// that are not on the list. See NewAllowList and AllowListFromStruct.
+	AllowList struct {
+		columns map[string]exp.IdentifierExpression
+	}
+
+	// The reason a field of a sort or fields spec was rejected
+	FieldErrorReason string
+
+	// A field of a sort or fields spec that was rejected, see AllowList.ParseSort and AllowList.ParseFields
+	FieldError struct {
+		// The field as given by the client, including the sort direction and nulls ordering
+		Field string
+		// The index of the field in the spec, starting at 0
+		Index int
+		// Why the field was rejected
+		Reason FieldErrorReason
+	}
+
+	// The fields of a spec that were rejected, every rejected field is reported so clients can fix them at once
+	FieldErrors []FieldError
+)
+
+const (
+	// The field is not on the allow-list
+	FieldNotAllowed FieldErrorReason = "is not allowed"
+	// The spec contains an empty field (e.g. sort=name,,id)
+	FieldEmpty FieldErrorReason = "is empty"
+	// The field appears more than once in the spec
+	FieldDuplicate FieldErrorReason = "is duplicated"
+	// The nulls ordering of a sort field is not nulls_first or nulls_last
+	FieldInvalidNulls FieldErrorReason = "has an invalid nulls ordering, expected nulls_first or nulls_last"
+	// The field of a fields spec has a sort direction or nulls ordering
+	FieldInvalidModifier FieldErrorReason = "can not be sorted"
+)
+
+// Creates an allow-list of the given fields, every field is mapped to the column with the same name. Fields can be
+// qualified with the table name (e.g. "user.name").
+//
+//	allowed := builder.NewAllowList("id", "name", "created_at")
+//	orders, err := allowed.ParseSort(r.URL.Query().Get("sort"))
+//	if err != nil {
+//		return err
+//	}
+//	ds := db.From("user").Order(orders...)
+func NewAllowList(fields ...string) *AllowList {
+	al := &AllowList{columns: make(map[string]exp.IdentifierExpression, len(fields))}
+	for _, f := range fields {
+		al.columns[f] = exp.ParseIdentifier(f)
+	}
+	return al
+}
+
+// Creates an allow-list of the columns of a struct, the fields are the column names of the db tags. Fields that are
+// ignored with db:"-" can not be used.
+//
+//	type User struct {
+//		ID        int64     `db:"id"`
+//		Name      string    `db:"name"`
+//		Password  string    `db:"-"`
+//	}
+//	allowed, err := builder.AllowListFromStruct(new(User))
+func AllowListFromStruct(i any) (*AllowList, error) {
+	return allowListFromStruct(i, nil)
+}
+
+// Creates an allow-list of the columns of a struct mapped with the Options of the dialect wrapper. See
+// AllowListFromStruct
+func (dw DialectWrapper) AllowListFromStruct(i any) (*AllowList, error) {
+	return allowListFromStruct(i, dw.settings.columnMapOptions())
+}
+
+// Creates an allow-list of the columns of a struct mapped with the Options of the Database, the fields are the
+// columns the Database selects and scans. See AllowListFromStruct
+func (d *Database) AllowListFromStruct(i any) (*AllowList, error) {
+	return allowListFromStruct(i, d.settings.columnMapOptions())
+}
+
+// Creates an allow-list of the columns of a struct mapped with the Options of the transaction. See
+// AllowListFromStruct
+func (td *TxDatabase) AllowListFromStruct(i any) (*AllowList, error) {
+	return allowListFromStruct(i, td.settings.columnMapOptions())
+}
+
+func allowListFromStruct(i any, opts *exp.ColumnMapOptions) (*AllowList, error) {
+	cm, err := util.GetColumnMapWithOptions(i, opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewAllowList(cm.Cols()...), nil
+}
+
+// Returns a copy of the allow-list with a field that is mapped to a different column. Use this to expose columns
+// with a different name than in the database or qualified columns of a join.
+//
+//	allowed := builder.NewAllowList("id").Alias("created", "user.created_at")
+func (al *AllowList) Alias(field, column string) *AllowList {
+	columns := make(map[string]exp.IdentifierExpression, len(al.columns)+1)
+	for f, col := range al.columns {
+		columns[f] = col
+	}
+	columns[field] = exp.ParseIdentifier(column)
+	return &AllowList{columns: columns}
+}
+
+// Returns the sorted fields of the allow-list, e.g. to document the accepted values of an endpoint
+func (al *AllowList) Fields() []string {
+	fields := make([]string, 0, len(al.columns))
+	for f := range al.columns {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Parses a comma separated sort spec into ordered expressions that can be passed to Order. A field prefixed with - is
+// sorted in descending order, an optional + prefix sorts in ascending order. The nulls ordering can be set with the
+// :nulls_first and :nulls_last suffixes. An empty spec returns no expressions, the returned error is FieldErrors.
+//
+//	orders, err := allowed.ParseSort("-created_at:nulls_last,name")
+//	// ORDER BY "created_at" DESC NULLS LAST, "name" ASC
+func (al *AllowList) ParseSort(spec string) ([]exp.OrderedExpression, error) {
+	var orders []exp.OrderedExpression
+	errs := al.parse(spec, func(field string) (string, FieldErrorReason) {
+		name, desc, nulls, reason := parseSortField(field)
+		if reason != "" {
+			return name, reason
+		}
+		col, ok := al.columns[name]
+		if !ok {
+			return name, FieldNotAllowed
+		}
+		order := col.Asc()
+		if desc {
+			order = col.Desc()
+		}
+		switch nulls {
+		case exp.NullsFirstSortType:
+			order = order.NullsFirst()
+		case exp.NullsLastSortType:
+			order = order.NullsLast()
+		}
+		orders = append(orders, order)
+		return name, ""
+	})
+	if errs != nil {
+		return nil, errs
+	}
+	return orders, nil
+}
+
+// Parses a comma separated fields spec into a column list that can be passed to Select. Qualified columns are
+// aliased with the name of the field like the columns of a struct. An empty spec returns nil so all columns are
+// selected, the returned error is FieldErrors.
+//
+//	cols, err := allowed.ParseFields("id,name")
+//	ds := db.From("user").Select(cols)
+func (al *AllowList) ParseFields(spec string) (exp.ColumnListExpression, error) {
+	var cols []any
+	errs := al.parse(spec, func(field string) (string, FieldErrorReason) {
+		col, ok := al.columns[field]
+		if !ok {
+			if name, _, _, _ := parseSortField(field); name != field && al.hasField(name) {
+				return field, FieldInvalidModifier
+			}
+			return field, FieldNotAllowed
+		}
+		if col.IsQualified() {
+			cols = append(cols, col.As(exp.NewIdentifierExpression("", "", field)))
+		} else {
+			cols = append(cols, col)
+		}
+		return field, ""
+	})
+	if errs != nil {
+		return nil, errs
+	}
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	return exp.NewColumnListExpression(cols...), nil
+}
+
+func (al *AllowList) hasField(field string) bool {
+	_, ok := al.columns[field]
+	return ok
+}
+
+// splits the spec and calls fn with every field, fn returns the name used to detect duplicates and the reason the
+// field was rejected if any
+func (al *AllowList) parse(spec string, fn func(field string) (string, FieldErrorReason)) error {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	var errs FieldErrors
+	seen := make(map[string]bool)
+	for i, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			errs = append(errs, FieldError{Field: field, Index: i, Reason: FieldEmpty})
+			continue
+		}
+		name, reason := fn(field)
+		if reason == "" && seen[name] {
+			reason = FieldDuplicate
+		}
+		if reason != "" {
+			errs = append(errs, FieldError{Field: field, Index: i, Reason: reason})
+			continue
+		}
+		seen[name] = true
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// splits a sort field into its name, direction and nulls ordering
+func parseSortField(field string) (name string, desc bool, nulls exp.NullSortType, reason FieldErrorReason) {
+	name = field
+	if idx := strings.LastIndexByte(name, ':'); idx >= 0 {
+		switch strings.ToLower(name[idx+1:]) {
+		case "nulls_first":
+			nulls = exp.NullsFirstSortType
+		case "nulls_last":
+			nulls = exp.NullsLastSortType
+		default:
+			return name[:idx], false, nulls, FieldInvalidNulls
+		}
+		name = name[:idx]
+	}
+	switch {
+	case strings.HasPrefix(name, "-"):
+		desc, name = true, name[1:]
+	case strings.HasPrefix(name, "+"):
+		name = name[1:]
+	}
+	return name, desc, nulls, ""
+}
+
+func (fe FieldError) message() string {
+	return fmt.Sprintf("field %q at index %d %s", fe.Field, fe.Index, fe.Reason)
+}
+
+func (fe FieldError) Error() string {
+	return "builder: " + fe.message()
+}
+
+func (fes FieldErrors) Error() string {
+	messages := make([]string, 0, len(fes))
+	for _, fe := range fes {
+		messages = append(messages, fe.message())
+	}
+	return "builder: " + strings.Join(messages, ", ")
+}
@@ -0,0 +1,115 @@
+package builder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Tooooommy/builder/v9"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type allowListSuite struct {
+	suite.Suite
+}
+
+func (als *allowListSuite) TestParseSort() {
+	allowed := builder.NewAllowList("id", "name", "created_at").Alias("owner", "user.name")
+
+	orders, err := allowed.ParseSort(" -created_at:nulls_last, name,+id:NULLS_FIRST,owner")
+	als.NoError(err)
+	sql, _, err := builder.From("item").Order(orders...).ToSQL()
+	als.NoError(err)
+	als.Equal(
+		`SELECT * FROM "item" ORDER BY "created_at" DESC NULLS LAST, "name" ASC, "id" ASC NULLS FIRST, "user"."name" ASC`,
+		sql,
+	)
+
+	orders, err = allowed.ParseSort("")
+	als.NoError(err)
+	als.Empty(orders)
+}
+
+func (als *allowListSuite) TestParseSort_errors() {
+	allowed := builder.NewAllowList("id", "name")
+
+	orders, err := allowed.ParseSort("name,password,,-name,id:nulls_middle")
+	als.Nil(orders)
+	als.EqualError(err, `builder: field "password" at index 1 is not allowed, field "" at index 2 is empty, `+
+		`field "-name" at index 3 is duplicated, `+
+		`field "id:nulls_middle" at index 4 has an invalid nulls ordering, expected nulls_first or nulls_last`)
+
+	var fieldErrs builder.FieldErrors
+	als.Require().ErrorAs(err, &fieldErrs)
+	als.Equal(builder.FieldError{Field: "password", Index: 1, Reason: builder.FieldNotAllowed}, fieldErrs[0])
+	als.Equal(builder.FieldDuplicate, fieldErrs[2].Reason)
+
+	_, err = allowed.ParseSort(`name"; DROP TABLE item; --`)
+	als.EqualError(err, `builder: field "name\"; DROP TABLE item; --" at index 0 is not allowed`)
+}
+
+func (als *allowListSuite) TestParseFields() {
+	allowed := builder.NewAllowList("id", "name").Alias("owner", "user.name")
+
+	cols, err := allowed.ParseFields("id, owner")
+	als.NoError(err)
+	sql, _, err := builder.From("item").Select(cols).ToSQL()
+	als.NoError(err)
+	als.Equal(`SELECT "id", "user"."name" AS "owner" FROM "item"`, sql)
+
+	cols, err = allowed.ParseFields("")
+	als.NoError(err)
+	als.Nil(cols)
+
+	_, err = allowed.ParseFields("-name,secret,id,id")
+	als.EqualError(err, `builder: field "-name" at index 0 can not be sorted, field "secret" at index 1 is not allowed, `+
+		`field "id" at index 3 is duplicated`)
+}
+
+func (als *allowListSuite) TestAllowListFromStruct() {
+	type Address struct {
+		City string `db:"city"`
+	}
+	type User struct {
+		ID       int64  `db:"id"`
+		Name     string `db:"name"`
+		Password string `db:"-"`
+		Address  Address
+	}
+	allowed, err := builder.AllowListFromStruct(new(User))
+	als.NoError(err)
+	als.Equal([]string{"address.city", "id", "name"}, allowed.Fields())
+
+	_, err = allowed.ParseSort("password")
+	als.EqualError(err, `builder: field "password" at index 0 is not allowed`)
+
+	_, err = builder.AllowListFromStruct("user")
+	als.EqualError(err, "builder: cannot scan into this type: string")
+}
+
+func (als *allowListSuite) TestAllowListFromStruct_options() {
+	type User struct {
+		ID       int64 `db:"id"`
+		Name     string
+		Password string `db:"-"`
+	}
+	mDB, _, err := sqlmock.New()
+	als.NoError(err)
+	db := builder.New("default", sqlx.NewSqlConnFromDB(mDB), builder.Options{ColumnRenameFunction: strings.ToUpper})
+	allowed, err := db.AllowListFromStruct(new(User))
+	als.NoError(err)
+	als.Equal([]string{"NAME", "id"}, allowed.Fields())
+	// the allowed fields are the columns the database selects
+	sql, _, err := db.From("user").Select(new(User)).ToSQL()
+	als.NoError(err)
+	als.Equal(`SELECT "NAME", "id" FROM "user"`, sql)
+
+	allowed, err = builder.Dialect("default", builder.Options{IgnoreUntaggedFields: true}).AllowListFromStruct(new(User))
+	als.NoError(err)
+	als.Equal([]string{"id"}, allowed.Fields())
+}
+
+func TestAllowListSuite(t *testing.T) {
+	suite.Run(t, new(allowListSuite))
+}
@@ -12,75 +12,96 @@ Please see https://github.com/Tooooommy/builder for an introduction to builder.
 package builder
 
 import (
+	"reflect"
 	"time"
 
+	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/util"
 	"github.com/Tooooommy/builder/v9/sqlgen"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 type DialectWrapper struct {
-	dialect string
+	dialect  string
+	settings *settings
 }
 
-// Creates a new DialectWrapper to create builder.Datasets or builder.Databases with the specified dialect.
-func Dialect(dialect string) DialectWrapper {
-	return DialectWrapper{dialect: dialect}
+// Creates a new DialectWrapper to create builder.Datasets or builder.Databases with the specified dialect. The
+// datasets and databases use the first Options, or the package level settings if no Options are passed.
+func Dialect(dialect string, opts ...Options) DialectWrapper {
+	return DialectWrapper{dialect: dialect, settings: newSettings(opts)}
 }
 
 // Create a new dataset for creating SELECT sql statements
 func (dw DialectWrapper) From(table ...any) *SelectDataset {
-	return From(table...).WithDialect(dw.dialect)
+	return newDataset(dw.dialect, nil, dw.settings).From(table...)
 }
 
 // Create a new dataset for creating SELECT sql statements
 func (dw DialectWrapper) Select(cols ...any) *SelectDataset {
-	return newDataset(dw.dialect, nil).Select(cols...)
+	return newDataset(dw.dialect, nil, dw.settings).Select(cols...)
 }
 
 // Create a new dataset for creating UPDATE sql statements
 func (dw DialectWrapper) Update(table any) *UpdateDataset {
-	return Update(table).WithDialect(dw.dialect)
+	return newUpdateDataset(dw.dialect, nil, dw.settings).Table(table)
 }
 
 // Create a new dataset for creating INSERT sql statements
 func (dw DialectWrapper) Insert(table any) *InsertDataset {
-	return Insert(table).WithDialect(dw.dialect)
+	return newInsertDataset(dw.dialect, nil, dw.settings).Into(table)
 }
 
 // Create a new dataset for creating DELETE sql statements
 func (dw DialectWrapper) Delete(table any) *DeleteDataset {
-	return Delete(table).WithDialect(dw.dialect)
+	return newDeleteDataset(dw.dialect, nil, dw.settings).From(table)
 }
 
 // Create a new dataset for creating TRUNCATE sql statements
 func (dw DialectWrapper) Truncate(table ...any) *TruncateDataset {
-	return Truncate(table...).WithDialect(dw.dialect)
+	return newTruncateDataset(dw.dialect, nil, dw.settings).Table(table...)
 }
 
 func (dw DialectWrapper) DB(db sqlx.SqlConn) *Database {
-	return newDatabase(dw.dialect, db)
+	return newDatabase(dw.dialect, db, dw.settings)
 }
 
-func New(dialect string, db sqlx.SqlConn) *Database {
-	return newDatabase(dialect, db)
+// Creates a new Database, the Database, its transactions and datasets use the first Options, or the package level
+// settings if no Options are passed.
+//
+//	db := builder.New("postgres", conn, builder.Options{Prepared: true, TimeLocation: time.Local})
+func New(dialect string, db sqlx.SqlConn, opts ...Options) *Database {
+	return newDatabase(dialect, db, newSettings(opts))
 }
 
 // Set the behavior when encountering struct fields that do not have a db tag.
 // By default this is false; if set to true any field without a db tag will not
-// be targeted by Select or Scan operations.
+// be targeted by Select or Scan operations. Databases created with Options use Options.IgnoreUntaggedFields instead.
 func SetIgnoreUntaggedFields(ignore bool) {
 	util.SetIgnoreUntaggedFields(ignore)
 }
 
 // Set the column rename function. This is used for struct fields that do not have a db tag to specify the column name
-// By default all struct fields that do not have a db tag will be converted lowercase
+// By default all struct fields that do not have a db tag will be converted lowercase. Databases created with Options
+// use Options.ColumnRenameFunction when it is set.
 func SetColumnRenameFunction(renameFunc func(string) string) {
 	util.SetColumnRenameFunction(renameFunc)
 }
 
 // Set the location to use when interpolating time.Time instances. See https://golang.org/pkg/time/#LoadLocation
-// NOTE: This has no effect when using prepared statements.
+// NOTE: This has no effect when using prepared statements. Databases created with Options use Options.TimeLocation when
+// it is set.
 func SetTimeLocation(loc *time.Location) {
 	sqlgen.SetTimeLocation(loc)
 }
+
+// Registers a converter for struct fields with a builder tag option of name, e.g. `builder:"encrypted"`. The json, list
+// and text converters are registered by default. See exp.Converter
+func RegisterConverter(name string, c Converter) {
+	exp.RegisterConverter(name, c)
+}
+
+// Registers a converter for struct fields of type t. See exp.Converter
+func RegisterTypeConverter(t reflect.Type, c Converter) {
+	exp.RegisterTypeConverter(t, c)
+}
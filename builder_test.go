@@ -1,6 +1,7 @@
 package builder_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -25,6 +26,13 @@ func (dws *dialectWrapperSuite) TearDownSuite() {
 	builder.DeregisterDialect("test")
 }
 
+func (dws *dialectWrapperSuite) TestDialects() {
+	dialects := builder.Dialects()
+	dws.Contains(dialects, "default")
+	dws.Contains(dialects, "test")
+	dws.IsIncreasing(dialects)
+}
+
 func (dws *dialectWrapperSuite) TestFrom() {
 	dw := builder.Dialect("test")
 	dws.Equal(builder.From("table").WithDialect("test"), dw.From("table"))
@@ -58,6 +66,27 @@ func (dws *dialectWrapperSuite) TestDB() {
 	dws.Equal(builder.New("test", conn), dw.DB(conn))
 }
 
+func (dws *dialectWrapperSuite) TestOptions() {
+	dw := builder.Dialect("test", builder.Options{Prepared: true, ColumnRenameFunction: strings.ToUpper})
+	item := struct {
+		Name string
+	}{Name: "Test"}
+
+	query, args, err := dw.From("items").Select(item).Where(builder.C("id").Eq(1)).ToSQL()
+	dws.NoError(err)
+	dws.Equal(`SELECT "NAME" FROM "items" WHERE ("id" = ?)`, query)
+	dws.Equal([]any{int64(1)}, args)
+
+	query, args, err = dw.Insert("items").Rows(item).ToSQL()
+	dws.NoError(err)
+	dws.Equal(`INSERT INTO "items" ("NAME") VALUES (?)`, query)
+	dws.Equal([]any{"Test"}, args)
+
+	query, _, err = builder.Dialect("test").From("items").Select(item).ToSQL()
+	dws.NoError(err)
+	dws.Equal(`SELECT "name" FROM "items"`, query)
+}
+
 func TestDialectWrapper(t *testing.T) {
 	suite.Run(t, new(dialectWrapperSuite))
 }
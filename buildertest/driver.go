@@ -0,0 +1,189 @@
+package buildertest
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+)
+
+type (
+	// database/sql connector that opens connections recording into a Recorder
+	connector struct {
+		recorder *Recorder
+	}
+
+	// database/sql connection that records every statement into a Recorder, the connection tracks the transaction it
+	// is in so statements can be attributed to it
+	conn struct {
+		recorder *Recorder
+		// the ID of the open transaction, 0 when outside a transaction
+		tx int
+	}
+
+	// prepared statement, statements are recorded when executed rather than when prepared
+	stmt struct {
+		conn  *conn
+		query string
+	}
+
+	// transaction of a conn
+	tx struct {
+		conn *conn
+	}
+
+	// rows returned by a scripted Response
+	rows struct {
+		columns []string
+		values  [][]driver.Value
+		next    int
+	}
+
+	// result returned by a scripted Response
+	result struct {
+		lastInsertID int64
+		rowsAffected int64
+	}
+)
+
+var (
+	_ driver.Connector         = connector{}
+	_ driver.ExecerContext     = (*conn)(nil)
+	_ driver.QueryerContext    = (*conn)(nil)
+	_ driver.ConnBeginTx       = (*conn)(nil)
+	_ driver.NamedValueChecker = (*conn)(nil)
+	_ driver.StmtExecContext   = (*stmt)(nil)
+	_ driver.StmtQueryContext  = (*stmt)(nil)
+)
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{recorder: c.recorder}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return recorderDriver{}
+}
+
+// the connector is always used to open connections, the driver is only returned to satisfy driver.Connector
+type recorderDriver struct{}
+
+func (recorderDriver) Open(string) (driver.Conn, error) {
+	return nil, errOpenNotSupported
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.tx = c.recorder.begin()
+	return &tx{conn: c}, nil
+}
+
+// Accepts every argument as is so the recorded arguments are the arguments passed by the caller
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res := c.recorder.record(query, values(args), c.tx)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return result{lastInsertID: res.lastInsertID, rowsAffected: res.rowsAffected}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res := c.recorder.record(query, values(args), c.tx)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &rows{columns: res.columns, values: res.rows}, nil
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func (t *tx) Commit() error {
+	t.conn.recorder.end(t.conn.tx, true)
+	t.conn.tx = 0
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.conn.recorder.end(t.conn.tx, false)
+	t.conn.tx = 0
+	return nil
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func values(args []driver.NamedValue) []any {
+	if len(args) == 0 {
+		return nil
+	}
+	vals := make([]any, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+	return vals
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
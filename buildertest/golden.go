@@ -0,0 +1,197 @@
+package buildertest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+)
+
+// Golden compares the SQL of datasets, rendered with every registered dialect, to golden files.
+//
+// Each golden file contains a section per dialect for the interpolated SQL and a section for the prepared SQL and
+// arguments
+//
+//	-- postgres --
+//	SELECT * FROM "user" WHERE ("id" = 1)
+//	-- postgres prepared --
+//	SELECT * FROM "user" WHERE ("id" = $1)
+//	args: [1]
+//
+// Only the dialects registered when the test runs are rendered, import the dialects you support in your tests.
+type Golden struct {
+	// The directory of the golden files (DEFAULT="testdata")
+	Dir string
+	// Set to true to write the golden files instead of comparing them, the -buildertest.update flag also updates the
+	// golden files
+	Update bool
+}
+
+var updateGolden = flag.Bool("buildertest.update", false, "update the golden files of buildertest.AssertGolden")
+
+// Compares the SQL of the dataset to the golden file testdata/<name>.golden. Run the tests with the
+// -buildertest.update flag to create or update the golden files.
+//
+//	func TestUserQuery(t *testing.T) {
+//		buildertest.AssertGolden(t, "user_query", builder.From("user").Where(builder.C("id").Eq(1)))
+//	}
+func AssertGolden(t TestingT, name string, ds SQLer) bool {
+	t.Helper()
+	return Golden{}.Assert(t, name, ds)
+}
+
+// Compares the SQL of the dataset to the golden file <Dir>/<name>.golden, reporting the sections that differ.
+// Returns true if the SQL matches the golden file.
+func (g Golden) Assert(t TestingT, name string, ds SQLer) bool {
+	t.Helper()
+	actual, err := RenderDialects(ds)
+	if err != nil {
+		t.Errorf("unable to render the dataset: %v", err)
+		return false
+	}
+	dir := g.Dir
+	if dir == "" {
+		dir = "testdata"
+	}
+	path := filepath.Join(dir, name+".golden")
+	if g.Update || *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("unable to create the golden file directory: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil { // #nosec
+			t.Errorf("unable to write the golden file: %v", err)
+			return false
+		}
+		return true
+	}
+	expected, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		t.Errorf("unable to read the golden file, run the tests with -buildertest.update to create it: %v", err)
+		return false
+	}
+	if diff := diffSections(string(expected), actual); diff != "" {
+		t.Errorf("SQL does not match %s, run the tests with -buildertest.update to update it\n%s", path, diff)
+		return false
+	}
+	return true
+}
+
+// Renders the interpolated and prepared SQL of the dataset with every registered dialect in the format of the golden
+// files. Errors generating the SQL are rendered in place of the SQL so unsupported features are also captured.
+func RenderDialects(ds SQLer) (string, error) {
+	var b strings.Builder
+	for _, dialect := range builder.Dialects() {
+		for _, prepared := range []bool{false, true} {
+			rendered, err := withDialect(ds, dialect, prepared)
+			if err != nil {
+				return "", err
+			}
+			header := dialect
+			if prepared {
+				header += " prepared"
+			}
+			fmt.Fprintf(&b, "-- %s --\n", header)
+			query, args, err := rendered.ToSQL()
+			if err != nil {
+				fmt.Fprintf(&b, "error: %v\n", err)
+				continue
+			}
+			b.WriteString(query)
+			b.WriteByte('\n')
+			if prepared {
+				fmt.Fprintf(&b, "args: %s\n", formatArgs(args))
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func withDialect(ds SQLer, dialect string, prepared bool) (SQLer, error) {
+	switch d := ds.(type) {
+	case *builder.SelectDataset:
+		return d.WithDialect(dialect).Prepared(prepared), nil
+	case *builder.InsertDataset:
+		return d.WithDialect(dialect).Prepared(prepared), nil
+	case *builder.UpdateDataset:
+		return d.WithDialect(dialect).Prepared(prepared), nil
+	case *builder.DeleteDataset:
+		return d.WithDialect(dialect).Prepared(prepared), nil
+	case *builder.TruncateDataset:
+		return d.WithDialect(dialect).Prepared(prepared), nil
+	}
+	return nil, errors.New("unsupported dataset type %T", ds)
+}
+
+func formatArgs(args []any) string {
+	formatted := make([]string, 0, len(args))
+	for _, arg := range args {
+		formatted = append(formatted, fmt.Sprintf("%#v", arg))
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+// returns the sections of a golden file by header in the order they appear
+func parseSections(golden string) (headers []string, sections map[string]string) {
+	sections = make(map[string]string)
+	header := ""
+	for _, line := range strings.SplitAfter(golden, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(trimmed, "-- ") && strings.HasSuffix(trimmed, " --") && len(trimmed) > 6 {
+			header = trimmed[3 : len(trimmed)-3]
+			headers = append(headers, header)
+			sections[header] = ""
+			continue
+		}
+		if header != "" {
+			sections[header] += line
+		}
+	}
+	return headers, sections
+}
+
+// returns a readable diff of the sections that differ between the expected and actual golden files, empty if they
+// are equal
+func diffSections(expected, actual string) string {
+	expectedHeaders, expectedSections := parseSections(expected)
+	actualHeaders, actualSections := parseSections(actual)
+	headers := actualHeaders
+	for _, h := range expectedHeaders {
+		if _, ok := actualSections[h]; !ok {
+			headers = append(headers, h)
+		}
+	}
+	var b strings.Builder
+	for _, h := range headers {
+		e, inExpected := expectedSections[h]
+		a, inActual := actualSections[h]
+		if inExpected && inActual && e == a {
+			continue
+		}
+		fmt.Fprintf(&b, "-- %s --\n", h)
+		switch {
+		case !inExpected:
+			b.WriteString("\t(missing from the golden file)\n")
+		case !inActual:
+			b.WriteString("\t(dialect is not registered)\n")
+		}
+		writeLines(&b, "-\t", e)
+		writeLines(&b, "+\t", a)
+	}
+	return b.String()
+}
+
+func writeLines(b *strings.Builder, prefix, section string) {
+	if section == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(section, "\n"), "\n") {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
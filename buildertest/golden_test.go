@@ -0,0 +1,88 @@
+package buildertest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/buildertest"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	goldenSuite struct {
+		suite.Suite
+	}
+
+	rawSQL string
+)
+
+func (r rawSQL) ToSQL() (string, []any, error) {
+	return string(r), nil, nil
+}
+
+func (gs *goldenSuite) TestAssertGolden() {
+	t := new(fakeT)
+	gs.True(buildertest.AssertGolden(t, "select_items", builder.
+		From("items").
+		Where(builder.C("name").Eq("Bob"), builder.C("id").In(1, 2)).
+		Order(builder.C("id").Desc()).
+		Limit(10)))
+	gs.True(buildertest.AssertGolden(t, "delete_items", builder.
+		Delete("items").
+		Where(builder.C("id").Eq(1)).
+		Returning("id")))
+	gs.Empty(t.errors)
+}
+
+func (gs *goldenSuite) TestAssert_update() {
+	g := buildertest.Golden{Dir: gs.T().TempDir(), Update: true}
+	t := new(fakeT)
+	gs.True(g.Assert(t, "nested/update_items", builder.Update("items").Set(builder.Record{"name": "Bob"})))
+	gs.Empty(t.errors)
+
+	golden, err := os.ReadFile(filepath.Join(g.Dir, "nested", "update_items.golden"))
+	gs.Require().NoError(err)
+	rendered, err := buildertest.RenderDialects(builder.Update("items").Set(builder.Record{"name": "Bob"}))
+	gs.Require().NoError(err)
+	gs.Equal(rendered, string(golden))
+	gs.Contains(rendered, "-- postgres --\nUPDATE \"items\" SET \"name\"='Bob'\n"+
+		"-- postgres prepared --\nUPDATE \"items\" SET \"name\"=$1\nargs: [\"Bob\"]\n")
+}
+
+func (gs *goldenSuite) TestAssert_mismatch() {
+	g := buildertest.Golden{Dir: gs.T().TempDir()}
+	path := filepath.Join(g.Dir, "truncate_items.golden")
+	gs.Require().NoError(os.WriteFile(path, []byte(
+		"-- postgres --\nTRUNCATE \"users\"\n-- oracle --\nTRUNCATE users\n",
+	), 0o600))
+
+	t := new(fakeT)
+	gs.False(g.Assert(t, "truncate_items", builder.Truncate("items").Identity("RESTART")))
+	gs.Require().Len(t.errors, 1)
+	gs.Contains(t.errors[0], "SQL does not match "+path+", run the tests with -buildertest.update to update it\n")
+	gs.Contains(t.errors[0], "-- postgres --\n-\tTRUNCATE \"users\"\n+\tTRUNCATE \"items\" RESTART IDENTITY\n")
+	gs.Contains(t.errors[0], "-- postgres prepared --\n\t(missing from the golden file)\n+\tTRUNCATE \"items\" RESTART IDENTITY\n+\targs: []\n")
+	gs.Contains(t.errors[0], "-- oracle --\n\t(dialect is not registered)\n-\tTRUNCATE users\n")
+}
+
+func (gs *goldenSuite) TestAssert_missingFile() {
+	t := new(fakeT)
+	gs.False(buildertest.Golden{Dir: gs.T().TempDir()}.Assert(t, "missing", builder.From("items")))
+	gs.Require().Len(t.errors, 1)
+	gs.Contains(t.errors[0], "unable to read the golden file, run the tests with -buildertest.update to create it")
+}
+
+func (gs *goldenSuite) TestRenderDialects_errors() {
+	rendered, err := buildertest.RenderDialects(builder.Delete("items").Returning("id"))
+	gs.NoError(err)
+	gs.Contains(rendered, "-- mysql --\nerror: builder: dialect does not support RETURNING clause [dialect=mysql]\n")
+
+	_, err = buildertest.RenderDialects(rawSQL("SELECT 1"))
+	gs.EqualError(err, "builder: unsupported dataset type buildertest_test.rawSQL")
+}
+
+func TestGoldenSuite(t *testing.T) {
+	suite.Run(t, new(goldenSuite))
+}
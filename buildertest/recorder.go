@@ -0,0 +1,352 @@
+// Package buildertest provides an in-memory sqlx.SqlConn that records the statements executed through a
+// builder.Database, so code using builder can be unit tested without a database or brittle sqlmock expectations.
+//
+//	rec := buildertest.New("postgres")
+//	rec.OnTable("user").Rows(User{ID: 1, Name: "Bob"})
+//	rec.On(`^UPDATE`).RowsAffected(1)
+//
+//	svc := NewUserService(rec.DB())
+//	...
+//	rec.AssertExecuted(t, rec.DB().Update("user").Set(builder.Record{"name": "Sally"}).Where(builder.C("id").Eq(1)))
+package buildertest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type (
+	// A statement executed through a Recorder
+	Statement struct {
+		// The dialect of the Recorder
+		Dialect string
+		// The executed SQL
+		SQL string
+		// The arguments of a prepared statement
+		Args []any
+		// The ID of the transaction the statement was executed in, 0 when executed outside a transaction.
+		// See Recorder#Transactions
+		Tx int
+	}
+
+	// A transaction started through a Recorder
+	Transaction struct {
+		// The ID of the transaction, starting at 1
+		ID int
+		// The statements executed in the transaction
+		Statements []Statement
+		// Set to true when the transaction was committed
+		Committed bool
+		// Set to true when the transaction was rolled back
+		RolledBack bool
+	}
+
+	// The scripted response of the statements matching a pattern or table. See Recorder#On and Recorder#OnTable
+	Response struct {
+		recorder     *Recorder
+		match        func(query string) bool
+		columns      []string
+		rows         [][]driver.Value
+		rowsAffected int64
+		lastInsertID int64
+		err          error
+		once         bool
+		used         bool
+	}
+
+	// Datasets that can be asserted with Recorder#Executed e.g. *builder.SelectDataset
+	SQLer interface {
+		ToSQL() (string, []any, error)
+	}
+
+	// The subset of testing.TB used by Recorder#AssertExecuted
+	TestingT interface {
+		Helper()
+		Errorf(format string, args ...any)
+	}
+
+	// An in-memory sqlx.SqlConn that records every statement executed through it and returns scripted responses.
+	// Statements that do not match a response return no rows and affect no rows.
+	Recorder struct {
+		mu           sync.Mutex
+		dialect      string
+		conn         sqlx.SqlConn
+		db           *builder.Database
+		responses    []*Response
+		statements   []Statement
+		transactions []*Transaction
+	}
+)
+
+var errOpenNotSupported = errors.New("the buildertest driver can only be used through a Recorder")
+
+// Creates a Recorder for the dialect
+func New(dialect string) *Recorder {
+	r := &Recorder{dialect: dialect}
+	// every error is acceptable so scripted errors never open the circuit breaker of the connection
+	r.conn = sqlx.NewSqlConnFromDB(
+		sql.OpenDB(connector{recorder: r}),
+		sqlx.WithAcceptable(func(error) bool { return true }),
+	)
+	r.db = builder.New(dialect, r.conn)
+	return r
+}
+
+// Returns the dialect of the Recorder
+func (r *Recorder) Dialect() string {
+	return r.dialect
+}
+
+// Returns the recording sqlx.SqlConn, use it to create your own builder.Database
+func (r *Recorder) Conn() sqlx.SqlConn {
+	return r.conn
+}
+
+// Returns a builder.Database that executes its statements through the Recorder
+func (r *Recorder) DB() *builder.Database {
+	return r.db
+}
+
+// Scripts the response of the statements matching the regular expression. Responses are matched in the order they
+// were added. Panics if the pattern is not a valid regular expression.
+//
+//	rec.On(`^SELECT .* FROM "user"`).Rows(builder.Record{"id": 1, "name": "Bob"})
+func (r *Recorder) On(pattern string) *Response {
+	return r.addResponse(regexp.MustCompile(pattern).MatchString)
+}
+
+// Scripts the response of the statements that select from, insert into, update or delete from the table, regardless
+// of how the dialect quotes it.
+//
+//	rec.OnTable("user").Error(sql.ErrConnDone)
+func (r *Recorder) OnTable(table string) *Response {
+	re := regexp.MustCompile(
+		"(?i)\\b(?:FROM|INTO|UPDATE|JOIN|TABLE)\\s+(?:ONLY\\s+)?[`\"\\[]?" + regexp.QuoteMeta(table) + "(?:[`\"\\]\\s,;()]|$)",
+	)
+	return r.addResponse(re.MatchString)
+}
+
+// Returns the statements executed through the Recorder in the order they were executed
+func (r *Recorder) Statements() []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Statement(nil), r.statements...)
+}
+
+// Returns the transactions started through the Recorder in the order they were started
+func (r *Recorder) Transactions() []Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txs := make([]Transaction, 0, len(r.transactions))
+	for _, tx := range r.transactions {
+		t := *tx
+		t.Statements = append([]Statement(nil), tx.Statements...)
+		txs = append(txs, t)
+	}
+	return txs
+}
+
+// Clears the recorded statements and transactions and the scripted responses
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = nil
+	r.statements = nil
+	r.transactions = nil
+}
+
+// Returns true if the SQL and arguments of the dataset were executed through the Recorder
+func (r *Recorder) Executed(ds SQLer) bool {
+	query, args, err := ds.ToSQL()
+	if err != nil {
+		return false
+	}
+	for _, s := range r.Statements() {
+		if s.SQL == query && equalArgs(s.Args, args) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports an error for each dataset that was not executed through the Recorder. Returns true if all datasets were
+// executed.
+func (r *Recorder) AssertExecuted(t TestingT, datasets ...SQLer) bool {
+	t.Helper()
+	ok := true
+	for _, ds := range datasets {
+		if r.Executed(ds) {
+			continue
+		}
+		ok = false
+		query, args, err := ds.ToSQL()
+		if err != nil {
+			t.Errorf("unable to generate the SQL of the dataset: %v", err)
+			continue
+		}
+		t.Errorf("statement was not executed\n\tSQL:  %s\n\tArgs: %v\nexecuted statements:\n%s", query, args, r.dump())
+	}
+	return ok
+}
+
+// Sets the rows returned by the statements. Rows can be structs or pointers to structs, using the same db tags as
+// the structs they are scanned into, or builder.Record. The columns are the sorted union of the columns of all rows.
+func (res *Response) Rows(rows ...any) *Response {
+	res.recorder.mu.Lock()
+	defer res.recorder.mu.Unlock()
+	res.columns, res.rows, res.err = driverRows(rows)
+	return res
+}
+
+// Sets the number of rows affected by the statements
+func (res *Response) RowsAffected(n int64) *Response {
+	res.recorder.mu.Lock()
+	defer res.recorder.mu.Unlock()
+	res.rowsAffected = n
+	return res
+}
+
+// Sets the id returned by sql.Result#LastInsertId
+func (res *Response) LastInsertID(id int64) *Response {
+	res.recorder.mu.Lock()
+	defer res.recorder.mu.Unlock()
+	res.lastInsertID = id
+	return res
+}
+
+// Sets the error returned by the statements
+func (res *Response) Error(err error) *Response {
+	res.recorder.mu.Lock()
+	defer res.recorder.mu.Unlock()
+	res.err = err
+	return res
+}
+
+// Only uses the response for the first matching statement
+func (res *Response) Once() *Response {
+	res.recorder.mu.Lock()
+	defer res.recorder.mu.Unlock()
+	res.once = true
+	return res
+}
+
+func (r *Recorder) addResponse(match func(query string) bool) *Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := &Response{recorder: r, match: match}
+	r.responses = append(r.responses, res)
+	return res
+}
+
+// records the statement and returns a copy of the response of the first response matching it
+func (r *Recorder) record(query string, args []any, tx int) Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := Statement{Dialect: r.dialect, SQL: query, Args: args, Tx: tx}
+	r.statements = append(r.statements, s)
+	if tx > 0 {
+		r.transactions[tx-1].Statements = append(r.transactions[tx-1].Statements, s)
+	}
+	for _, res := range r.responses {
+		if (res.once && res.used) || !res.match(query) {
+			continue
+		}
+		res.used = true
+		return *res
+	}
+	return Response{}
+}
+
+// starts a transaction and returns its ID
+func (r *Recorder) begin() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := len(r.transactions) + 1
+	r.transactions = append(r.transactions, &Transaction{ID: id})
+	return id
+}
+
+func (r *Recorder) end(tx int, committed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tx > 0 {
+		r.transactions[tx-1].Committed = committed
+		r.transactions[tx-1].RolledBack = !committed
+	}
+}
+
+// returns the executed statements, one per line
+func (r *Recorder) dump() string {
+	var b strings.Builder
+	for _, s := range r.Statements() {
+		fmt.Fprintf(&b, "\t%s", s.SQL)
+		if len(s.Args) > 0 {
+			fmt.Fprintf(&b, " %v", s.Args)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// converts the rows of a Response to the columns and values returned by the driver
+func driverRows(rows []any) (columns []string, values [][]driver.Value, err error) {
+	records := make([]exp.Record, 0, len(rows))
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		record, err := toRecord(row)
+		if err != nil {
+			return nil, nil, err
+		}
+		for col := range record {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+		records = append(records, record)
+	}
+	sort.Strings(columns)
+	values = make([][]driver.Value, 0, len(records))
+	for _, record := range records {
+		vals := make([]driver.Value, len(columns))
+		for i, col := range columns {
+			if vals[i], err = driver.DefaultParameterConverter.ConvertValue(record[col]); err != nil {
+				return nil, nil, errors.New("unable to convert the value of column %s: %v", col, err)
+			}
+		}
+		values = append(values, vals)
+	}
+	return columns, values, nil
+}
+
+func toRecord(row any) (exp.Record, error) {
+	switch r := row.(type) {
+	case exp.Record:
+		return r, nil
+	case map[string]any:
+		return r, nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New("unsupported row type %T, rows must be structs or records", row)
+	}
+	return exp.NewRecordFromStruct(v.Interface(), false, false)
+}
+
+func equalArgs(a, b []any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
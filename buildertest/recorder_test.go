@@ -0,0 +1,182 @@
+package buildertest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/buildertest"
+	_ "github.com/Tooooommy/builder/v9/dialect/mysql"
+	_ "github.com/Tooooommy/builder/v9/dialect/postgres"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type (
+	recorderItem struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	recorderSuite struct {
+		suite.Suite
+	}
+
+	fakeT struct {
+		errors []string
+	}
+)
+
+func (ft *fakeT) Helper() {}
+
+func (ft *fakeT) Errorf(format string, args ...any) {
+	ft.errors = append(ft.errors, fmt.Sprintf(format, args...))
+}
+
+func (rs *recorderSuite) TestRows() {
+	rec := buildertest.New("postgres")
+	rec.On(`^SELECT \* FROM "items"`).Rows(
+		recorderItem{ID: 1, Name: "Bob"},
+		&recorderItem{ID: 2, Name: "Sally"},
+	)
+	rec.OnTable("users").Rows(builder.Record{"id": 3, "name": "Jim"})
+
+	var items []recorderItem
+	rs.NoError(rec.DB().From("items").QueryRows(&items))
+	rs.Equal([]recorderItem{{ID: 1, Name: "Bob"}, {ID: 2, Name: "Sally"}}, items)
+
+	var item recorderItem
+	rs.NoError(rec.DB().From("users").Where(builder.C("id").Eq(3)).QueryRow(&item))
+	rs.Equal(recorderItem{ID: 3, Name: "Jim"}, item)
+
+	// statements that do not match a response return no rows
+	rs.ErrorIs(rec.DB().From("accounts").QueryRow(&item), sqlx.ErrNotFound)
+
+	rs.Equal([]buildertest.Statement{
+		{Dialect: "postgres", SQL: `SELECT * FROM "items"`},
+		{Dialect: "postgres", SQL: `SELECT * FROM "users" WHERE ("id" = 3) LIMIT 1`},
+		{Dialect: "postgres", SQL: `SELECT * FROM "accounts" LIMIT 1`},
+	}, rec.Statements())
+}
+
+func (rs *recorderSuite) TestExec() {
+	rec := buildertest.New("mysql")
+	rec.OnTable("items").RowsAffected(2).LastInsertID(10)
+	rec.OnTable("users").Error(errors.New("mock error"))
+
+	res, err := rec.DB().Insert("items").Rows(recorderItem{Name: "Bob"}).Exec()
+	rs.NoError(err)
+	id, err := res.LastInsertId()
+	rs.NoError(err)
+	rs.Equal(int64(10), id)
+	rowsAffected, err := res.RowsAffected()
+	rs.NoError(err)
+	rs.Equal(int64(2), rowsAffected)
+
+	_, err = rec.DB().Delete("users").AllowFullTable().Exec()
+	rs.EqualError(err, "builder: mock error")
+
+	rs.Equal([]buildertest.Statement{
+		{Dialect: "mysql", SQL: "INSERT INTO `items` (`id`, `name`) VALUES (0, 'Bob')"},
+		{Dialect: "mysql", SQL: "DELETE `users` FROM `users`"},
+	}, rec.Statements())
+}
+
+func (rs *recorderSuite) TestOnce() {
+	rec := buildertest.New("postgres")
+	rec.On(`^UPDATE`).RowsAffected(1).Once()
+	rec.On(`^UPDATE`).Error(errors.New("mock error"))
+
+	ds := rec.DB().Update("items").Set(builder.Record{"name": "Bob"}).AllowFullTable()
+	_, err := ds.Exec()
+	rs.NoError(err)
+	_, err = ds.Exec()
+	rs.EqualError(err, "builder: mock error")
+}
+
+func (rs *recorderSuite) TestPrepared() {
+	rec := buildertest.New("postgres")
+
+	ds := rec.DB().From("items").Where(builder.C("id").Eq(1), builder.C("name").Eq("Bob")).Prepared(true)
+	var items []recorderItem
+	rs.NoError(ds.QueryRows(&items))
+
+	rs.Equal([]buildertest.Statement{
+		{Dialect: "postgres", SQL: `SELECT * FROM "items" WHERE (("id" = $1) AND ("name" = $2))`, Args: []any{int64(1), "Bob"}},
+	}, rec.Statements())
+	rs.True(rec.Executed(ds))
+	rs.False(rec.Executed(ds.Prepared(false)))
+}
+
+func (rs *recorderSuite) TestTransact() {
+	rec := buildertest.New("postgres")
+	db := rec.DB()
+
+	rs.NoError(db.Transact(func(td *builder.TxDatabase) error {
+		_, err := td.Update("items").Set(builder.Record{"name": "Bob"}).AllowFullTable().Exec()
+		return err
+	}))
+	_, err := db.Delete("items").AllowFullTable().Exec()
+	rs.NoError(err)
+	rs.EqualError(db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		if _, err := td.Delete("users").AllowFullTable().ExecCtx(ctx); err != nil {
+			return err
+		}
+		return errors.New("mock error")
+	}), "builder: mock error")
+
+	rs.Equal([]buildertest.Transaction{
+		{
+			ID:         1,
+			Statements: []buildertest.Statement{{Dialect: "postgres", SQL: `UPDATE "items" SET "name"='Bob'`, Tx: 1}},
+			Committed:  true,
+		},
+		{
+			ID:         2,
+			Statements: []buildertest.Statement{{Dialect: "postgres", SQL: `DELETE FROM "users"`, Tx: 2}},
+			RolledBack: true,
+		},
+	}, rec.Transactions())
+	rs.Len(rec.Statements(), 3)
+	rs.Equal(0, rec.Statements()[1].Tx)
+}
+
+func (rs *recorderSuite) TestAssertExecuted() {
+	rec := buildertest.New("postgres")
+	_, err := rec.DB().Delete("items").Where(builder.C("id").Eq(1)).Exec()
+	rs.NoError(err)
+
+	t := new(fakeT)
+	rs.True(rec.AssertExecuted(t, builder.Dialect("postgres").Delete("items").Where(builder.C("id").Eq(1))))
+	rs.Empty(t.errors)
+
+	rs.False(rec.AssertExecuted(t, builder.Dialect("postgres").Delete("items").Where(builder.C("id").Eq(2))))
+	rs.Equal([]string{
+		"statement was not executed\n" +
+			"\tSQL:  DELETE FROM \"items\" WHERE (\"id\" = 2)\n" +
+			"\tArgs: []\n" +
+			"executed statements:\n" +
+			"\tDELETE FROM \"items\" WHERE (\"id\" = 1)\n",
+	}, t.errors)
+
+	rec.Reset()
+	rs.Empty(rec.Statements())
+	rs.Empty(rec.Transactions())
+}
+
+func (rs *recorderSuite) TestRows_unsupportedType() {
+	rec := buildertest.New("postgres")
+	rec.OnTable("items").Rows(1)
+
+	var items []recorderItem
+	rs.EqualError(
+		rec.DB().From("items").QueryRows(&items),
+		"builder: unsupported row type int, rows must be structs or records",
+	)
+}
+
+func TestRecorderSuite(t *testing.T) {
+	suite.Run(t, new(recorderSuite))
+}
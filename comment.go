@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Extracts sqlcommenter tags (e.g. route, application or traceparent) from the context a statement is executed
+	// with. See Database#Commenter
+	CommentExtractor func(ctx context.Context) map[string]string
+
+	commentTagsContextKey struct{}
+)
+
+// Returns a copy of ctx that carries the sqlcommenter tags, the tags are merged with the tags already stored in ctx.
+// The tags are added to statements by the ContextCommentTags extractor.
+//
+//	ctx = builder.WithCommentTags(ctx, map[string]string{"route": "/users/:id"})
+func WithCommentTags(ctx context.Context, tags map[string]string) context.Context {
+	parent := ContextCommentTags(ctx)
+	merged := make(map[string]string, len(parent)+len(tags))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, commentTagsContextKey{}, merged)
+}
+
+// A CommentExtractor that returns the tags added to ctx with WithCommentTags
+func ContextCommentTags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(commentTagsContextKey{}).(map[string]string)
+	return tags
+}
+
+// Returns a CommentExtractor that always returns tags, e.g. the name of the application
+//
+//	db.Commenter(builder.StaticCommentTags(map[string]string{"application": "user-api"}))
+func StaticCommentTags(tags map[string]string) CommentExtractor {
+	return func(context.Context) map[string]string {
+		return tags
+	}
+}
+
+// A CommentExtractor that returns the W3C traceparent of the span stored in ctx
+//
+//	traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'
+func TraceparentCommentTag(ctx context.Context) map[string]string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return map[string]string{
+		"traceparent": "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + sc.TraceFlags().String(),
+	}
+}
+
+// Returns the tags of all extractors, tags of later extractors take precedence
+func extractCommentTags(ctx context.Context, extractors []CommentExtractor) map[string]string {
+	var tags map[string]string
+	for _, extractor := range extractors {
+		for k, v := range extractor(ctx) {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[k] = v
+		}
+	}
+	return tags
+}
+
+// Appends the tags to query as a comment in the sqlcommenter format (https://google.github.io/sqlcommenter/spec/).
+// Keys and values are URL encoded so a tag can never end the comment. Statements that already end with a comment are
+// not changed.
+//
+//	SELECT * FROM "user" /*application='user-api',route='%2Fusers%2F%3Aid'*/
+func appendSQLComment(query string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return query
+	}
+	trimmed := strings.TrimRight(query, " \t\r\n")
+	terminator := ""
+	if strings.HasSuffix(trimmed, ";") {
+		trimmed, terminator = strings.TrimRight(trimmed[:len(trimmed)-1], " \t\r\n"), ";"
+	}
+	if strings.HasSuffix(trimmed, "*/") {
+		return query
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(trimmed)
+	b.WriteString(" /*")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(commentEscape(k))
+		b.WriteString("='")
+		b.WriteString(commentEscape(tags[k]))
+		b.WriteByte('\'')
+	}
+	b.WriteString("*/")
+	b.WriteString(terminator)
+	return b.String()
+}
+
+// Escapes the keys and values of the tags, url.QueryEscape encodes the quotes and comment delimiters as well so this is
+// the only escaping applied (spaces are encoded as %20 as in sqlcommenter)
+func commentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,135 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Tooooommy/builder/v9"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type commentSuite struct {
+	suite.Suite
+}
+
+func (cs *commentSuite) newDB() (*builder.Database, sqlmock.Sqlmock) {
+	mDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	cs.Require().NoError(err)
+	return builder.New("mock", sqlx.NewSqlConnFromDB(mDB)), mock
+}
+
+func (cs *commentSuite) TestCommenter() {
+	db, mock := cs.newDB()
+	db.Commenter(
+		builder.StaticCommentTags(map[string]string{"application": "user-api", "route": "default"}),
+		builder.ContextCommentTags,
+	)
+	ctx := builder.WithCommentTags(context.Background(), map[string]string{"route": "/users/:id"})
+
+	mock.ExpectQuery(`SELECT * FROM "items" /*application='user-api',route='%2Fusers%2F%3Aid'*/`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).AddRow("111 Test Addr", "Test1"))
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test2' /*application='user-api',route='default'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items" /*application='user-api',route='%2Fusers%2F%3Aid'*/;`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var items []testItem
+	cs.NoError(db.From("items").QueryRowsCtx(ctx, &items))
+	cs.Len(items, 1)
+
+	_, err := db.Update("items").Set(builder.Record{"name": "Test2"}).AllowFullTable().Exec()
+	cs.NoError(err)
+
+	_, err = db.ExecCtx(ctx, `DELETE FROM "items";`)
+	cs.NoError(err)
+	cs.NoError(mock.ExpectationsWereMet())
+}
+
+func (cs *commentSuite) TestCommenter_escaping() {
+	db, mock := cs.newDB()
+	db.Commenter(builder.ContextCommentTags)
+	ctx := builder.WithCommentTags(context.Background(), map[string]string{
+		"route":     "*/ DROP TABLE items; /*",
+		"user name": "O'Neil",
+	})
+
+	mock.ExpectExec(`DELETE FROM "items" /*route='%2A%2F%20DROP%20TABLE%20items%3B%20%2F%2A',user%20name='O%27Neil'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := db.Delete("items").AllowFullTable().ExecCtx(ctx)
+	cs.NoError(err)
+	cs.NoError(mock.ExpectationsWereMet())
+}
+
+func (cs *commentSuite) TestCommenter_withoutTags() {
+	db, mock := cs.newDB()
+
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items" /*route='a'*/`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := builder.WithCommentTags(context.Background(), map[string]string{"route": "a"})
+	_, err := db.Delete("items").AllowFullTable().ExecCtx(ctx)
+	cs.NoError(err)
+
+	db.Commenter(builder.ContextCommentTags)
+	// statements that already end with a comment are not changed
+	_, err = db.ExecCtx(ctx, `DELETE FROM "items" /*route='a'*/`)
+	cs.NoError(err)
+
+	_, err = db.Delete("items").AllowFullTable().ExecCtx(context.Background())
+	cs.NoError(err)
+	cs.NoError(mock.ExpectationsWereMet())
+}
+
+func (cs *commentSuite) TestCommenter_transaction() {
+	db, mock := cs.newDB()
+	db.Commenter(builder.StaticCommentTags(map[string]string{"application": "user-api"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test2' /*application='user-api'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items" /*application='user-api'*/`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		if _, err := td.Update("items").Set(builder.Record{"name": "Test2"}).AllowFullTable().ExecCtx(ctx); err != nil {
+			return err
+		}
+		_, err := db.Delete("items").AllowFullTable().ExecCtx(ctx)
+		return err
+	})
+	cs.NoError(err)
+	cs.NoError(mock.ExpectationsWereMet())
+}
+
+func (cs *commentSuite) TestTraceparentCommentTag() {
+	cs.Nil(builder.TraceparentCommentTag(context.Background()))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	cs.Equal(
+		map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		builder.TraceparentCommentTag(ctx),
+	)
+}
+
+func (cs *commentSuite) TestWithCommentTags() {
+	ctx := builder.WithCommentTags(context.Background(), map[string]string{"route": "a", "application": "b"})
+	child := builder.WithCommentTags(ctx, map[string]string{"route": "c"})
+	cs.Equal(map[string]string{"route": "a", "application": "b"}, builder.ContextCommentTags(ctx))
+	cs.Equal(map[string]string{"route": "c", "application": "b"}, builder.ContextCommentTags(child))
+	cs.Nil(builder.ContextCommentTags(context.Background()))
+}
+
+func TestCommentSuite(t *testing.T) {
+	suite.Run(t, new(commentSuite))
+}
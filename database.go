@@ -3,36 +3,60 @@ package builder
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/sb"
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var ErrExecutorNotFoundError = errors.New(
 	"unable to execute query did you use builder.Database#From to create the dataset",
 )
 
+var (
+	ErrFullTableNotAllowed = errors.New(
+		"refusing to execute an UPDATE or DELETE without a WHERE or LIMIT clause, use AllowFullTable to allow it",
+	)
+	ErrTruncateNotAllowed = errors.New("refusing to execute a TRUNCATE, use AllowFullTable to allow it")
+)
+
 // This struct is the wrapper for a Db. The struct delegates most calls to either an Exec instance or to the Db
 // passed into the constructor.
 type Database struct {
 	logger  logx.Logger
 	dialect string
 	// nolint: stylecheck // keep for backwards compatibility
-	conn sqlx.SqlConn
+	conn       sqlx.SqlConn
+	commenters []CommentExtractor
+	tracer     trace.Tracer
+	tracing    TracingOptions
+	metrics    *Metrics
+	// the Options of the database, nil to use the package level settings
+	settings *settings
+	// set to true to execute UPDATE, DELETE and TRUNCATE statements that affect every row of a table
+	allowFullTable bool
 }
 
 // This is the common entry point into builder.
 //
 // dialect: This is the adapter dialect, you should see your database adapter for the string to use. Built in adapters
 // can be found at https://github.com/Tooooommy/builder/tree/master/adapters
-func newDatabase(dialect string, conn sqlx.SqlConn) *Database {
-
-	return &Database{
-		logger:  logx.WithCallerSkip(-1),
-		dialect: dialect,
-		conn:    conn,
+func newDatabase(dialect string, conn sqlx.SqlConn, s *settings) *Database {
+	d := &Database{
+		logger:   logx.WithCallerSkip(-1),
+		dialect:  dialect,
+		settings: s,
 	}
+	d.conn = &databaseConn{databaseSession: d.wrapSession(conn), conn: conn}
+	return d
 }
 
 // returns this databases dialect
@@ -50,27 +74,27 @@ func (d *Database) Dialect() string {
 //
 // from...: Sources for you dataset, could be table names (strings), a builder.Literal or another builder.Dataset
 func (d *Database) From(from ...any) *SelectDataset {
-	return newDataset(d.dialect, d.conn).From(from...)
+	return newDataset(d.dialect, d.conn, d.settings).From(from...)
 }
 
 func (d *Database) Select(cols ...any) *SelectDataset {
-	return newDataset(d.dialect, d.conn).Select(cols...)
+	return newDataset(d.dialect, d.conn, d.settings).Select(cols...)
 }
 
 func (d *Database) Update(table any) *UpdateDataset {
-	return newUpdateDataset(d.dialect, d.conn).Table(table)
+	return newUpdateDataset(d.dialect, d.conn, d.settings).Table(table)
 }
 
 func (d *Database) Insert(table any) *InsertDataset {
-	return newInsertDataset(d.dialect, d.conn).Into(table)
+	return newInsertDataset(d.dialect, d.conn, d.settings).Into(table)
 }
 
 func (d *Database) Delete(table any) *DeleteDataset {
-	return newDeleteDataset(d.dialect, d.conn).From(table)
+	return newDeleteDataset(d.dialect, d.conn, d.settings).From(table)
 }
 
 func (d *Database) Truncate(table ...any) *TruncateDataset {
-	return newTruncateDataset(d.dialect, d.conn).Table(table...)
+	return newTruncateDataset(d.dialect, d.conn, d.settings).Table(table...)
 }
 
 // Sets the logger for to use when logging queries
@@ -78,6 +102,30 @@ func (d *Database) Logger(logger logx.Logger) {
 	d.logger = logger
 }
 
+// Sets whether UPDATE and DELETE statements without a WHERE or LIMIT clause and TRUNCATE statements can be executed
+// through this Database and its transactions. They are refused with ErrFullTableNotAllowed and ErrTruncateNotAllowed
+// by default, use AllowFullTable on a dataset to allow a single statement.
+//
+//	db.Update("user").Set(record).Exec()                  // ErrFullTableNotAllowed
+//	db.Update("user").Set(record).AllowFullTable().Exec() // UPDATE "user" SET ...
+func (d *Database) AllowFullTable(allow bool) {
+	d.allowFullTable = allow
+}
+
+// Sets the extractors used to tag every statement executed through this Database, including the statements of its
+// datasets and transactions, with a trailing comment in the sqlcommenter format. Tags of later extractors take
+// precedence. Calling Commenter without extractors disables commenting.
+//
+//	db.Commenter(
+//	    builder.StaticCommentTags(map[string]string{"application": "user-api"}),
+//	    builder.ContextCommentTags,
+//	    builder.TraceparentCommentTag,
+//	)
+//	// SELECT * FROM "user" /*application='user-api',route='%2Fusers',traceparent='00-...-01'*/
+func (d *Database) Commenter(extractors ...CommentExtractor) {
+	d.commenters = extractors
+}
+
 // Logs a given operation with the specified sql and arguments
 func (d *Database) Trace(ctx context.Context, op, sqlString string, args ...any) {
 	if d.logger != nil {
@@ -100,7 +148,7 @@ func (d *Database) Exec(query string, args ...any) (sql.Result, error) {
 
 func (d *Database) ExecCtx(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	d.Trace(ctx, "ExecCtx", query, args...)
-	return d.conn.ExecCtx(ctx, query, args...)
+	return sessionFromContext(ctx, d.conn).ExecCtx(ctx, query, args...)
 }
 
 func (d *Database) Prepare(query string) (sqlx.StmtSession, error) {
@@ -110,7 +158,7 @@ func (d *Database) Prepare(query string) (sqlx.StmtSession, error) {
 
 func (d *Database) PrepareCtx(ctx context.Context, query string) (sqlx.StmtSession, error) {
 	d.Trace(ctx, "Prepare", query)
-	return d.conn.PrepareCtx(ctx, query)
+	return sessionFromContext(ctx, d.conn).PrepareCtx(ctx, query)
 }
 
 func (d *Database) QueryRow(v any, query string, args ...any) error {
@@ -120,7 +168,7 @@ func (d *Database) QueryRow(v any, query string, args ...any) error {
 
 func (d *Database) QueryRowCtx(ctx context.Context, v any, query string, args ...any) error {
 	d.Trace(ctx, "QueryRowCtx", query, args...)
-	return d.conn.QueryRowCtx(ctx, v, query, args...)
+	return sessionFromContext(ctx, d.conn).QueryRowCtx(ctx, v, query, args...)
 }
 
 func (d *Database) QueryRowPartial(v any, query string, args ...any) error {
@@ -130,7 +178,7 @@ func (d *Database) QueryRowPartial(v any, query string, args ...any) error {
 
 func (d *Database) QueryRowPartialCtx(ctx context.Context, v any, query string, args ...any) error {
 	d.Trace(ctx, "QueryRowPartialCtx", query, args...)
-	return d.conn.QueryRowPartialCtx(ctx, v, query, args...)
+	return sessionFromContext(ctx, d.conn).QueryRowPartialCtx(ctx, v, query, args...)
 }
 
 func (d *Database) QueryRows(v any, query string, args ...any) error {
@@ -140,7 +188,7 @@ func (d *Database) QueryRows(v any, query string, args ...any) error {
 
 func (d *Database) QueryRowsCtx(ctx context.Context, v any, query string, args ...any) error {
 	d.Trace(ctx, "QueryRowsCtx", query, args...)
-	return d.conn.QueryRowsCtx(ctx, v, query, args...)
+	return sessionFromContext(ctx, d.conn).QueryRowsCtx(ctx, v, query, args...)
 }
 
 func (d *Database) QueryRowsPartial(v any, query string, args ...any) error {
@@ -150,24 +198,432 @@ func (d *Database) QueryRowsPartial(v any, query string, args ...any) error {
 
 func (d *Database) QueryRowsPartialCtx(ctx context.Context, v any, query string, args ...any) error {
 	d.Trace(ctx, "QueryRowsPartialCtx", query, args...)
-	return d.conn.QueryRowsPartialCtx(ctx, v, query, args...)
+	return sessionFromContext(ctx, d.conn).QueryRowsPartialCtx(ctx, v, query, args...)
 }
 
 // Transact starts a new transaction and executes it in function method
-func (d *Database) Transact(fn func(td *TxDatabase) error) (err error) {
-	d.Trace(context.Background(), "Transact", "")
-	return d.conn.Transact(func(s sqlx.Session) error {
-		td := NewTx(d.dialect, s)
+//
+// opts: Optional isolation level, read only and deferrable settings for the transaction, only the first
+// TransactionOptions are used. See Database#TransactCtx
+func (d *Database) Transact(fn func(td *TxDatabase) error, opts ...TransactionOptions) (err error) {
+	return d.TransactCtx(context.Background(), func(_ context.Context, td *TxDatabase) error {
 		return fn(td)
-	})
+	}, opts...)
 }
 
-func (d *Database) TransactCtx(ctx context.Context, fn func(ctx context.Context, td *TxDatabase) error) (err error) {
+// TransactCtx starts a new transaction and executes it in function method
+//
+// When TransactionOptions are passed and the underlying *sql.DB is available they are mapped onto sql.TxOptions,
+// otherwise a SET TRANSACTION statement is executed at the start of the transaction.
+//
+// The transaction is stored in the context passed to fn, datasets created with Database#From, Insert, Update, Delete
+// and Truncate that are executed with that context (e.g. QueryRowsCtx, ExecCtx) use the transaction instead of the
+// connection pool. See TxFromContext.
+//
+//	err := db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+//	    _, err := td.Update("account").Set(record).Where(builder.C("id").Eq(id)).ExecCtx(ctx)
+//	    return err
+//	}, builder.TransactionOptions{Isolation: sql.LevelSerializable})
+func (d *Database) TransactCtx(
+	ctx context.Context,
+	fn func(ctx context.Context, td *TxDatabase) error,
+	opts ...TransactionOptions,
+) (err error) {
 	d.Trace(ctx, "Transact", "")
+	if d.tracer != nil {
+		var span trace.Span
+		ctx, span = d.startTransactionSpan(ctx)
+		defer func() { endSpan(span, err) }()
+	}
+	if d.metrics != nil {
+		done := d.metrics.startTransaction(d.dialect)
+		defer func() { done(err) }()
+	}
+	state := newTxState()
+	defer func() {
+		// callbacks are executed once the outcome of the transaction is known
+		if err != nil {
+			runTxCallbacks(ctx, d.logger, "OnRollback", state.takeOnRollback(0))
+		} else {
+			runTxCallbacks(ctx, d.logger, "OnCommit", state.takeOnCommit(0))
+		}
+	}()
+	txFn := func(ctx context.Context, s sqlx.Session) error {
+		td := NewTx(d.dialect, d.wrapSession(s))
+		td.settings = d.settings
+		td.state = state
+		return fn(context.WithValue(ctx, txContextKey{}, &txContext{conn: d.conn, td: td}), td)
+	}
+	if len(opts) == 0 || opts[0].IsEmpty() {
+		return d.conn.TransactCtx(ctx, txFn)
+	}
+	if raw, rawErr := d.conn.RawDB(); rawErr == nil && raw != nil {
+		return d.transactWithOptions(ctx, raw, opts[0], txFn)
+	}
+
+	query, err := d.setTransactionSQL(opts[0])
+	if err != nil {
+		return err
+	}
 	return d.conn.TransactCtx(ctx, func(ctx context.Context, s sqlx.Session) error {
-		td := NewTx(d.dialect, s)
-		return fn(ctx, td)
+		d.Trace(ctx, "SetTransaction", query)
+		if _, err := s.ExecCtx(ctx, query); err != nil {
+			return err
+		}
+		return txFn(ctx, s)
+	})
+}
+
+// starts the transaction with sql.TxOptions, options that cannot be expressed through sql.TxOptions (e.g. DEFERRABLE)
+// are set with a SET TRANSACTION statement. Commits and rolls back the same way sqlx.SqlConn#TransactCtx does.
+func (d *Database) transactWithOptions(
+	ctx context.Context,
+	db *sql.DB,
+	opts TransactionOptions,
+	fn func(ctx context.Context, s sqlx.Session) error,
+) (err error) {
+	var query string
+	if opts.Deferrable {
+		if query, err = d.setTransactionSQL(TransactionOptions{Deferrable: true}); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if e := tx.Rollback(); e != nil {
+				err = fmt.Errorf("recover from %#v, rollback failed: %w", p, e)
+			} else {
+				err = fmt.Errorf("recover from %#v", p)
+			}
+		} else if err != nil {
+			if e := tx.Rollback(); e != nil {
+				err = fmt.Errorf("transaction failed: %s, rollback failed: %w", err, e)
+			}
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	s := sqlx.NewSessionFromTx(tx)
+	if query != "" {
+		d.Trace(ctx, "SetTransaction", query)
+		if _, err = s.ExecCtx(ctx, query); err != nil {
+			return err
+		}
+	}
+	return fn(ctx, s)
+}
+
+func (d *Database) setTransactionSQL(opts TransactionOptions) (string, error) {
+	b := sb.NewSQLBuilder(false)
+	GetDialect(d.dialect).ToSetTransactionSQL(b, opts)
+	query, _, err := b.ToSQL()
+	return query, err
+}
+
+type (
+	txContextKey struct{}
+	// the transaction started by Database#TransactCtx and the connection it was started on
+	txContext struct {
+		conn sqlx.SqlConn
+		td   *TxDatabase
+	}
+)
+
+// Returns the TxDatabase of the transaction started by Database#Transact/TransactCtx that ctx was passed through.
+//
+//	func (r *repo) UpdateUser(ctx context.Context, user User) error {
+//	    if td, ok := builder.TxFromContext(ctx); ok {
+//	        td.OnCommit(func(ctx context.Context) { r.cache.Delete(ctx, user.ID) })
+//	    }
+//	    _, err := r.db.Update("user").Set(user).Where(builder.C("id").Eq(user.ID)).ExecCtx(ctx)
+//	    return err
+//	}
+func TxFromContext(ctx context.Context) (*TxDatabase, bool) {
+	if tc, ok := ctx.Value(txContextKey{}).(*txContext); ok {
+		return tc.td, true
+	}
+	return nil, false
+}
+
+// Returns the session of the transaction stored in ctx if it was started on the same connection as executor, otherwise
+// executor is returned. This allows datasets created with Database#From etc. to join a transaction started with
+// Database#TransactCtx by passing its context.
+func sessionFromContext(ctx context.Context, executor sqlx.Session) sqlx.Session {
+	tc, ok := ctx.Value(txContextKey{}).(*txContext)
+	if !ok || executor == nil {
+		return executor
+	}
+	// guard against comparing connections that are not comparable (e.g. structs containing a slice)
+	if t := reflect.TypeOf(executor); t != reflect.TypeOf(tc.conn) || !t.Comparable() || executor != tc.conn {
+		return executor
+	}
+	return tc.td.session
+}
+
+// Returns the session a dataset executes its statements with. See sessionFromContext.
+//
+// The statement is passed to the session of the Database through the context to name and label spans and metrics.
+// If timeout is greater than zero every statement is executed with a context that times out after timeout, inside of
+// transactions the timeout is also enforced by the database for dialects that support it
+// (e.g. SET LOCAL statement_timeout).
+func datasetSession(
+	ctx context.Context, dialect SQLDialect, executor sqlx.Session, stmt statement, timeout time.Duration,
+) sqlx.Session {
+	s := sessionFromContext(ctx, executor)
+	if s == nil {
+		return s
+	}
+	return &statementSession{Session: s, dialect: dialect, stmt: stmt, timeout: timeout}
+}
+
+// A session that executes the statements of a dataset, applying the timeout of the dataset
+type statementSession struct {
+	sqlx.Session
+	dialect SQLDialect
+	stmt    statement
+	timeout time.Duration
+}
+
+func (ss *statementSession) ExecCtx(ctx context.Context, query string, args ...any) (res sql.Result, err error) {
+	err = ss.run(ctx, func(ctx context.Context) error {
+		res, err = ss.Session.ExecCtx(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (ss *statementSession) QueryRowCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ss.run(ctx, func(ctx context.Context) error {
+		return ss.Session.QueryRowCtx(ctx, v, query, args...)
+	})
+}
+
+func (ss *statementSession) QueryRowPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ss.run(ctx, func(ctx context.Context) error {
+		return ss.Session.QueryRowPartialCtx(ctx, v, query, args...)
+	})
+}
+
+func (ss *statementSession) QueryRowsCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ss.run(ctx, func(ctx context.Context) error {
+		return ss.Session.QueryRowsCtx(ctx, v, query, args...)
+	})
+}
+
+func (ss *statementSession) QueryRowsPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ss.run(ctx, func(ctx context.Context) error {
+		return ss.Session.QueryRowsPartialCtx(ctx, v, query, args...)
+	})
+}
+
+func (ss *statementSession) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ss.timeout <= 0 {
+		return fn(contextWithStatement(ctx, ss.stmt))
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, ss.timeout)
+	defer cancel()
+
+	// a statement timeout can only be scoped to a transaction, statements executed on the pool rely on the context
+	if _, ok := ss.Session.(sqlx.SqlConn); !ok {
+		query, reset, sqlErr := ss.statementTimeoutSQL()
+		if sqlErr != nil {
+			return sqlErr
+		}
+		if query != "" {
+			if _, err = ss.Session.ExecCtx(timeoutCtx, query); err != nil {
+				return err
+			}
+			defer func() {
+				// resetting fails as well if the statement failed and aborted the transaction (e.g. postgres)
+				if _, e := ss.Session.ExecCtx(ctx, reset); e != nil && err == nil {
+					err = e
+				}
+			}()
+		}
+	}
+	return fn(contextWithStatement(timeoutCtx, ss.stmt))
+}
+
+func (ss *statementSession) statementTimeoutSQL() (query, reset string, err error) {
+	b := sb.NewSQLBuilder(false)
+	ss.dialect.ToStatementTimeoutSQL(b, ss.timeout)
+	if query, _, err = b.ToSQL(); err != nil || query == "" {
+		return "", "", err
+	}
+	b = sb.NewSQLBuilder(false)
+	ss.dialect.ToResetStatementTimeoutSQL(b)
+	reset, _, err = b.ToSQL()
+	return query, reset, err
+}
+
+// returns true if the executor belongs to a Database that refuses statements affecting every row of a table. Sessions
+// that were not created by a Database (e.g. NewTx) allow them.
+func refusesFullTable(executor sqlx.Session) bool {
+	switch s := executor.(type) {
+	case *databaseConn:
+		return !s.db.allowFullTable
+	case *databaseSession:
+		return !s.db.allowFullTable
+	}
+	return false
+}
+
+// returns true if a statement with the WHERE and LIMIT clauses affects every row of the table
+func isFullTable(where exp.ExpressionList, hasLimit bool) bool {
+	return !hasLimit && (where == nil || where.IsEmpty())
+}
+
+// returns true if the dialect renders the LIMIT of a statement, dialects that do not support it silently drop it.
+// Custom dialects are assumed not to support it.
+func rendersLimit(dialect SQLDialect, supported func(opts *SQLDialectOptions) bool) bool {
+	d, ok := dialect.(interface{ options() *SQLDialectOptions })
+	return ok && supported(d.options())
+}
+
+// Returns a session that applies the settings of this Database (e.g. Commenter) to every statement executed with it
+func (d *Database) wrapSession(s sqlx.Session) *databaseSession {
+	return &databaseSession{Session: s, db: d}
+}
+
+// A session that applies the settings of a Database to every statement before executing it
+type databaseSession struct {
+	sqlx.Session
+	db *Database
+}
+
+func (ds *databaseSession) query(ctx context.Context, query string) string {
+	if len(ds.db.commenters) == 0 {
+		return query
+	}
+	return appendSQLComment(query, extractCommentTags(ctx, ds.db.commenters))
+}
+
+// executes the statement with the settings of the Database, fn is called with the commented query and a context that
+// carries the span of the statement
+func (ds *databaseSession) run(
+	ctx context.Context,
+	query string,
+	fn func(ctx context.Context, query string) (sql.Result, error),
+) (err error) {
+	query = ds.query(ctx, query)
+	if ds.db.metrics != nil {
+		done := ds.db.metrics.startStatement(ds.db.dialect, statementFromContext(ctx, query))
+		defer func() { done(err) }()
+	}
+	if ds.db.tracer == nil {
+		_, err = fn(ctx, query)
+		return err
+	}
+	ctx, span := ds.db.startStatementSpan(ctx, query)
+	res, err := fn(ctx, query)
+	endStatementSpan(span, res, err)
+	return err
+}
+
+func (ds *databaseSession) Exec(query string, args ...any) (sql.Result, error) {
+	return ds.ExecCtx(context.Background(), query, args...)
+}
+
+func (ds *databaseSession) ExecCtx(ctx context.Context, query string, args ...any) (res sql.Result, err error) {
+	err = ds.run(ctx, query, func(ctx context.Context, query string) (sql.Result, error) {
+		res, err = ds.Session.ExecCtx(ctx, query, args...)
+		return res, err
 	})
+	return res, err
+}
+
+func (ds *databaseSession) Prepare(query string) (sqlx.StmtSession, error) {
+	return ds.PrepareCtx(context.Background(), query)
+}
+
+func (ds *databaseSession) PrepareCtx(ctx context.Context, query string) (sqlx.StmtSession, error) {
+	return ds.Session.PrepareCtx(ctx, ds.query(ctx, query))
+}
+
+func (ds *databaseSession) QueryRow(v any, query string, args ...any) error {
+	return ds.QueryRowCtx(context.Background(), v, query, args...)
+}
+
+// Returns the function scanning rows into v when sqlx.Session can not scan v, see recordsScanner and structScanner
+func (ds *databaseSession) rowsScanner(v any, single, strict bool) func(rows *sql.Rows) error {
+	if scan := recordsScanner(ds.db.settings.dialect(ds.db.dialect), v, single); scan != nil {
+		return scan
+	}
+	return structScanner(v, single, strict, ds.db.settings.columnMapOptions())
+}
+
+func (ds *databaseSession) QueryRowCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ds.run(ctx, query, func(ctx context.Context, query string) (sql.Result, error) {
+		if scan := ds.rowsScanner(v, true, true); scan != nil {
+			return nil, queryRows(ctx, ds.Session, scan, query, args...)
+		}
+		return nil, ds.Session.QueryRowCtx(ctx, v, query, args...)
+	})
+}
+
+func (ds *databaseSession) QueryRowPartial(v any, query string, args ...any) error {
+	return ds.QueryRowPartialCtx(context.Background(), v, query, args...)
+}
+
+func (ds *databaseSession) QueryRowPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ds.run(ctx, query, func(ctx context.Context, query string) (sql.Result, error) {
+		if scan := ds.rowsScanner(v, true, false); scan != nil {
+			return nil, queryRows(ctx, ds.Session, scan, query, args...)
+		}
+		return nil, ds.Session.QueryRowPartialCtx(ctx, v, query, args...)
+	})
+}
+
+func (ds *databaseSession) QueryRows(v any, query string, args ...any) error {
+	return ds.QueryRowsCtx(context.Background(), v, query, args...)
+}
+
+func (ds *databaseSession) QueryRowsCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ds.run(ctx, query, func(ctx context.Context, query string) (sql.Result, error) {
+		if scan := ds.rowsScanner(v, false, true); scan != nil {
+			return nil, queryRows(ctx, ds.Session, scan, query, args...)
+		}
+		return nil, ds.Session.QueryRowsCtx(ctx, v, query, args...)
+	})
+}
+
+func (ds *databaseSession) QueryRowsPartial(v any, query string, args ...any) error {
+	return ds.QueryRowsPartialCtx(context.Background(), v, query, args...)
+}
+
+func (ds *databaseSession) QueryRowsPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return ds.run(ctx, query, func(ctx context.Context, query string) (sql.Result, error) {
+		if scan := ds.rowsScanner(v, false, false); scan != nil {
+			return nil, queryRows(ctx, ds.Session, scan, query, args...)
+		}
+		return nil, ds.Session.QueryRowsPartialCtx(ctx, v, query, args...)
+	})
+}
+
+// The connection of a Database, statements executed on the connection are executed with a databaseSession. The
+// transaction functions are passed the unwrapped sessions of the underlying connection.
+type databaseConn struct {
+	*databaseSession
+	conn sqlx.SqlConn
+}
+
+func (dc *databaseConn) RawDB() (*sql.DB, error) {
+	return dc.conn.RawDB()
+}
+
+func (dc *databaseConn) Transact(fn func(sqlx.Session) error) error {
+	return dc.conn.Transact(fn)
+}
+
+func (dc *databaseConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return dc.conn.TransactCtx(ctx, fn)
 }
 
 // A wrapper around a sql.Tx and works the same way as Database
@@ -175,11 +631,28 @@ type TxDatabase struct {
 	logger  logx.Logger
 	dialect string
 	session sqlx.Session
+	// the Options of the Database that started the transaction, nil to use the package level settings
+	settings *settings
+	// shared by all savepoints of a transaction
+	state *txState
+}
+
+// The state of a transaction that is shared between a TxDatabase and its savepoints
+type txState struct {
+	// used to generate unique savepoint names
+	savepointSeq uint64
+	mu           sync.Mutex
+	onCommit     []func(ctx context.Context)
+	onRollback   []func(ctx context.Context)
+}
+
+func newTxState() *txState {
+	return &txState{}
 }
 
 // Creates a new TxDatabase
 func NewTx(dialect string, session sqlx.Session) *TxDatabase {
-	return &TxDatabase{dialect: dialect, session: session}
+	return &TxDatabase{dialect: dialect, session: session, state: newTxState()}
 }
 
 // returns this databases dialect
@@ -189,27 +662,27 @@ func (td *TxDatabase) Dialect() string {
 
 // Creates a new Dataset for querying a Database.
 func (td *TxDatabase) From(cols ...any) *SelectDataset {
-	return newDataset(td.dialect, td.session).From(cols...)
+	return newDataset(td.dialect, td.session, td.settings).From(cols...)
 }
 
 func (td *TxDatabase) Select(cols ...any) *SelectDataset {
-	return newDataset(td.dialect, td.session).Select(cols...)
+	return newDataset(td.dialect, td.session, td.settings).Select(cols...)
 }
 
 func (td *TxDatabase) Update(table any) *UpdateDataset {
-	return newUpdateDataset(td.dialect, td.session).Table(table)
+	return newUpdateDataset(td.dialect, td.session, td.settings).Table(table)
 }
 
 func (td *TxDatabase) Insert(table any) *InsertDataset {
-	return newInsertDataset(td.dialect, td.session).Into(table)
+	return newInsertDataset(td.dialect, td.session, td.settings).Into(table)
 }
 
 func (td *TxDatabase) Delete(table any) *DeleteDataset {
-	return newDeleteDataset(td.dialect, td.session).From(table)
+	return newDeleteDataset(td.dialect, td.session, td.settings).From(table)
 }
 
 func (td *TxDatabase) Truncate(table ...any) *TruncateDataset {
-	return newTruncateDataset(td.dialect, td.session).Table(table...)
+	return newTruncateDataset(td.dialect, td.session, td.settings).Table(table...)
 }
 
 // Sets the logger
@@ -308,3 +781,152 @@ func (td *TxDatabase) QueryRowsPartialCtx(ctx context.Context, v any, query stri
 	td.Trace(ctx, "QueryRowsPartialCtx", query, args...)
 	return td.session.QueryRowsPartialCtx(ctx, v, query, args...)
 }
+
+// Savepoint starts a nested unit of work inside of the transaction. A SAVEPOINT with a generated name is created before
+// fn is called.
+//
+// If fn returns nil the savepoint is released, if fn returns an error the transaction is rolled back to the savepoint
+// and the error is returned while the outer transaction remains usable. If fn panics the transaction is rolled back to
+// the savepoint and the panic is propagated so the outer transaction is rolled back as well. When the transaction is
+// rolled back to the savepoint the OnCommit callbacks added by fn are discarded and its OnRollback callbacks are called.
+//
+//	err := db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+//	    if _, err := td.Insert("user").Rows(user).ExecCtx(ctx); err != nil {
+//	        return err
+//	    }
+//	    // a failure to insert the audit record will not roll back the user insert
+//	    _ = td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+//	        _, err := td.Insert("audit").Rows(audit).ExecCtx(ctx)
+//	        return err
+//	    })
+//	    return nil
+//	})
+func (td *TxDatabase) Savepoint(ctx context.Context, fn func(ctx context.Context, td *TxDatabase) error) (err error) {
+	d := GetDialect(td.dialect)
+	name := td.nextSavepointName()
+	if err = td.execSavepointSQL(ctx, "Savepoint", name, d.ToSavepointSQL); err != nil {
+		return err
+	}
+	commitMark, rollbackMark := td.state.marks()
+
+	defer func() {
+		if p := recover(); p != nil {
+			if e := td.execSavepointSQL(ctx, "RollbackToSavepoint", name, d.ToRollbackToSavepointSQL); e != nil {
+				panic(fmt.Errorf("recover from %#v, rollback to savepoint failed: %w", p, e))
+			}
+			td.rolledBackToSavepoint(ctx, commitMark, rollbackMark)
+			panic(p)
+		} else if err != nil {
+			if e := td.execSavepointSQL(ctx, "RollbackToSavepoint", name, d.ToRollbackToSavepointSQL); e != nil {
+				err = fmt.Errorf("savepoint failed: %s, rollback to savepoint failed: %w", err, e)
+			} else {
+				td.rolledBackToSavepoint(ctx, commitMark, rollbackMark)
+			}
+		} else {
+			err = td.execSavepointSQL(ctx, "ReleaseSavepoint", name, d.ToReleaseSavepointSQL)
+		}
+	}()
+
+	return fn(ctx, td)
+}
+
+// OnCommit queues fn to be called by Database#Transact/TransactCtx after the transaction has been committed. Callbacks
+// are called in the order they were added, a panicking callback is logged and does not prevent the remaining
+// callbacks from being called.
+//
+// Callbacks added inside of a savepoint that is rolled back are discarded.
+//
+//	err := db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+//	    if _, err := td.Update("user").Set(record).Where(builder.C("id").Eq(id)).ExecCtx(ctx); err != nil {
+//	        return err
+//	    }
+//	    td.OnCommit(func(ctx context.Context) { cache.Delete(ctx, userKey(id)) })
+//	    return nil
+//	})
+func (td *TxDatabase) OnCommit(fn func(ctx context.Context)) {
+	td.state.mu.Lock()
+	defer td.state.mu.Unlock()
+	td.state.onCommit = append(td.state.onCommit, fn)
+}
+
+// OnRollback queues fn to be called after the work it was added for has been rolled back. Callbacks added outside of a
+// savepoint are called by Database#Transact/TransactCtx after the transaction has been rolled back or failed to commit.
+// Callbacks are called in the order they were added, a panicking callback is logged and does not prevent the remaining
+// callbacks from being called.
+//
+// Callbacks added inside of a savepoint are called by Savepoint as soon as the transaction is rolled back to the
+// savepoint, while the outer transaction is still open and may still be committed. They are not called again when the
+// outer transaction is rolled back.
+func (td *TxDatabase) OnRollback(fn func(ctx context.Context)) {
+	td.state.mu.Lock()
+	defer td.state.mu.Unlock()
+	td.state.onRollback = append(td.state.onRollback, fn)
+}
+
+// discards the OnCommit callbacks and calls the OnRollback callbacks added since the savepoint was created
+func (td *TxDatabase) rolledBackToSavepoint(ctx context.Context, commitMark, rollbackMark int) {
+	td.state.takeOnCommit(commitMark)
+	runTxCallbacks(ctx, td.logger, "OnRollback", td.state.takeOnRollback(rollbackMark))
+}
+
+func (td *TxDatabase) nextSavepointName() string {
+	return fmt.Sprintf("builder_sp_%d", atomic.AddUint64(&td.state.savepointSeq, 1))
+}
+
+// returns the number of queued OnCommit and OnRollback callbacks
+func (ts *txState) marks() (commitMark, rollbackMark int) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return len(ts.onCommit), len(ts.onRollback)
+}
+
+// removes and returns the OnCommit callbacks queued after mark
+func (ts *txState) takeOnCommit(mark int) []func(ctx context.Context) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	fns := ts.onCommit[mark:]
+	ts.onCommit = ts.onCommit[:mark:mark]
+	return fns
+}
+
+// removes and returns the OnRollback callbacks queued after mark
+func (ts *txState) takeOnRollback(mark int) []func(ctx context.Context) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	fns := ts.onRollback[mark:]
+	ts.onRollback = ts.onRollback[:mark:mark]
+	return fns
+}
+
+// calls fns in order, a panicking callback is logged and does not prevent the remaining callbacks from being called
+func runTxCallbacks(ctx context.Context, logger logx.Logger, op string, fns []func(ctx context.Context)) {
+	for _, fn := range fns {
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					if logger == nil {
+						logger = logx.WithContext(ctx)
+					}
+					logger.WithContext(ctx).Errorf("[builder - transaction] %s callback panic: %+v", op, p)
+				}
+			}()
+			fn(ctx)
+		}()
+	}
+}
+
+func (td *TxDatabase) execSavepointSQL(ctx context.Context, op, name string, gen func(sb.SQLBuilder, string)) error {
+	b := sb.NewSQLBuilder(false)
+	gen(b, name)
+	query, _, err := b.ToSQL()
+	if err != nil {
+		return err
+	}
+	if query == "" {
+		// the dialect does not have an equivalent statement (e.g. RELEASE SAVEPOINT on SQL Server)
+		return nil
+	}
+	td.Trace(ctx, op, query)
+	_, err = td.session.ExecCtx(ctx, query)
+	return err
+}
@@ -1,9 +1,13 @@
 package builder_test
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Tooooommy/builder/v9"
@@ -277,6 +281,188 @@ func (ds *databaseSuite) TestTransact() {
 	}
 }
 
+// a connection that does not expose the underlying *sql.DB
+type noRawDBConn struct {
+	sqlx.SqlConn
+}
+
+func (c noRawDBConn) RawDB() (*sql.DB, error) {
+	return nil, errors.New("raw db not available")
+}
+
+func (ds *databaseSuite) TestTransactCtx_withOptions() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	db := builder.New("mock", conn)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		_, err := td.Update("items").Set(builder.Record{"name": "Test"}).AllowFullTable().ExecCtx(ctx)
+		return err
+	}, builder.TransactionOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	ds.NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET TRANSACTION DEFERRABLE`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		return nil
+	}, builder.TransactionOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true})
+	ds.NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback().WillReturnError(errors.New("transaction rollback error"))
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		return errors.New("something wrong")
+	}, builder.TransactionOptions{ReadOnly: true})
+	ds.EqualError(err, TransactFailed())
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		panic("transaction panic")
+	}, builder.TransactionOptions{ReadOnly: true})
+	ds.EqualError(err, `recover from "transaction panic"`)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+	err = db.Transact(func(td *builder.TxDatabase) error {
+		return nil
+	}, builder.TransactionOptions{Isolation: sql.LevelReadCommitted})
+	ds.EqualError(err, "builder: commit error")
+
+	ds.NoError(mock.ExpectationsWereMet())
+}
+
+func (ds *databaseSuite) TestTransactCtx_withOptionsNotSupported() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+
+	opts := builder.DefaultDialectOptions()
+	opts.SetTransactionFragment = []byte("")
+	opts.DeferrableFragment = []byte("")
+	builder.RegisterDialect("no-set-transaction", opts)
+	defer builder.DeregisterDialect("no-set-transaction")
+
+	called := false
+	fn := func(ctx context.Context, td *builder.TxDatabase) error {
+		called = true
+		return nil
+	}
+	ctx := context.Background()
+
+	db := builder.New("no-set-transaction", sqlx.NewSqlConnFromDB(mDB))
+	err = db.TransactCtx(ctx, fn, builder.TransactionOptions{Deferrable: true})
+	ds.EqualError(err, "builder: dialect does not support DEFERRABLE transactions [dialect=no-set-transaction]")
+
+	db = builder.New("no-set-transaction", noRawDBConn{sqlx.NewSqlConnFromDB(mDB)})
+	err = db.TransactCtx(ctx, fn, builder.TransactionOptions{Isolation: sql.LevelSerializable})
+	ds.EqualError(err, "builder: dialect does not support SET TRANSACTION [dialect=no-set-transaction]")
+	ds.False(called)
+	ds.NoError(mock.ExpectationsWereMet())
+}
+
+func (ds *databaseSuite) TestTransactCtx_withOptionsWithoutRawDB() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+
+	db := builder.New("mock", noRawDBConn{sqlx.NewSqlConnFromDB(mDB)})
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		return nil
+	}, builder.TransactionOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	ds.NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET TRANSACTION READ ONLY`).WillReturnError(errors.New("set transaction error"))
+	mock.ExpectRollback()
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		return nil
+	}, builder.TransactionOptions{ReadOnly: true})
+	ds.EqualError(err, "builder: set transaction error")
+
+	err = db.TransactCtx(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+		return nil
+	}, builder.TransactionOptions{Isolation: sql.LevelSnapshot})
+	ds.EqualError(err, "builder: dialect does not support isolation level Snapshot [dialect=default]")
+	ds.NoError(mock.ExpectationsWereMet())
+}
+
+func (ds *databaseSuite) TestTransactCtx_contextPropagation() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+	// a dataset that does not join the transaction would block waiting for a second connection
+	mDB.SetMaxOpenConns(1)
+
+	otherDB, otherMock, err := sqlmock.New()
+	ds.NoError(err)
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	other := builder.New("mock", sqlx.NewSqlConnFromDB(otherDB))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+	mock.ExpectExec(`INSERT INTO "items" \("address", "name"\) VALUES \('111 Test Addr', 'Test1'\)`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test2'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`TRUNCATE "items"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT 1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	otherMock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("222 Test Addr,Test2"))
+
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		txd, ok := builder.TxFromContext(ctx)
+		ds.True(ok)
+		ds.Same(td, txd)
+
+		var item testItem
+		if err := db.From("items").QueryRowCtx(ctx, &item); err != nil {
+			return err
+		}
+		if _, err := db.Insert("items").Rows(item).ExecCtx(ctx); err != nil {
+			return err
+		}
+		if _, err := db.Update("items").Set(builder.Record{"name": "Test2"}).AllowFullTable().ExecCtx(ctx); err != nil {
+			return err
+		}
+		if _, err := db.Delete("items").AllowFullTable().ExecCtx(ctx); err != nil {
+			return err
+		}
+		if _, err := db.Truncate("items").AllowFullTable().TruncateCtx(ctx); err != nil {
+			return err
+		}
+		if _, err := db.ExecCtx(ctx, "SELECT 1"); err != nil {
+			return err
+		}
+		// datasets of another database keep using their own connection
+		var otherItem testItem
+		if err := other.From("items").QueryRowCtx(ctx, &otherItem); err != nil {
+			return err
+		}
+		ds.Equal(testItem{Address: "222 Test Addr", Name: "Test2"}, otherItem)
+		return nil
+	})
+	ds.NoError(err)
+	ds.NoError(mock.ExpectationsWereMet())
+	ds.NoError(otherMock.ExpectationsWereMet())
+
+	_, ok := builder.TxFromContext(context.Background())
+	ds.False(ok)
+}
+
 func (ds *databaseSuite) TestDataRace() {
 	mDB, mock, err := sqlmock.New()
 	ds.NoError(err)
@@ -311,6 +497,114 @@ func (ds *databaseSuite) TestDataRace() {
 	wg.Wait()
 }
 
+func (ds *databaseSuite) TestAllowFullTable() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+
+	_, err = db.Update("items").Set(builder.Record{"name": "Test"}).Exec()
+	ds.Equal(builder.ErrFullTableNotAllowed, err)
+	var items []testItem
+	ds.Equal(builder.ErrFullTableNotAllowed, db.Delete("items").Returning("name").QueryRows(&items))
+	_, err = db.Truncate("items").Truncate()
+	ds.Equal(builder.ErrTruncateNotAllowed, err)
+	// the dialect does not support LIMIT on DELETE so the statement would delete every row
+	_, err = db.Delete("items").Limit(10).Exec()
+	ds.Equal(builder.ErrFullTableNotAllowed, err)
+
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test' WHERE \("id" = 1\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `items` LIMIT 10").WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test'`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`TRUNCATE "items"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = db.Update("items").Set(builder.Record{"name": "Test"}).Where(builder.C("id").Eq(1)).Exec()
+	ds.NoError(err)
+	_, err = builder.New("mysql", sqlx.NewSqlConnFromDB(mDB)).Delete("items").Limit(10).Exec()
+	ds.NoError(err)
+	_, err = db.Update("items").Set(builder.Record{"name": "Test"}).AllowFullTable().Exec()
+	ds.NoError(err)
+	_, err = db.Truncate("items").AllowFullTable().Truncate()
+	ds.NoError(err)
+
+	// the statements are refused inside of transactions too
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	ds.Equal(builder.ErrFullTableNotAllowed, db.Transact(func(td *builder.TxDatabase) error {
+		_, err := td.Delete("items").Exec()
+		return err
+	}))
+
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	db.AllowFullTable(true)
+	_, err = db.Delete("items").Exec()
+	ds.NoError(err)
+	ds.NoError(mock.ExpectationsWereMet())
+}
+
+func (ds *databaseSuite) TestOptions() {
+	type optionsItem struct {
+		ID   int64 `db:"id"`
+		Name string
+	}
+	mDB, _, err := sqlmock.New()
+	ds.NoError(err)
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	loc := time.FixedZone("test", 3600)
+
+	prepared := builder.New("mock", conn, builder.Options{Prepared: true, IgnoreUntaggedFields: true})
+	renamed := builder.New("mock", conn, builder.Options{ColumnRenameFunction: strings.ToUpper, TimeLocation: loc})
+	db := builder.New("mock", conn)
+
+	query, args, err := prepared.From("items").Select(optionsItem{}).Where(builder.C("created").Eq(created)).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`SELECT "id" FROM "items" WHERE ("created" = ?)`, query)
+	ds.Equal([]any{created}, args)
+
+	query, args, err = renamed.From("items").Select(optionsItem{}).Where(builder.C("created").Eq(created)).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`SELECT "NAME", "id" FROM "items" WHERE ("created" = '2024-01-02T04:04:05+01:00')`, query)
+	ds.Empty(args)
+
+	query, _, err = renamed.Insert("items").Rows(optionsItem{ID: 1, Name: "Test"}).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`INSERT INTO "items" ("NAME", "id") VALUES ('Test', 1)`, query)
+
+	query, _, err = renamed.Update("items").Set(optionsItem{ID: 1, Name: "Test"}).Where(builder.C("id").Eq(1)).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`UPDATE "items" SET "NAME"='Test',"id"=1 WHERE ("id" = 1)`, query)
+
+	// the Options of one database do not change the package level settings
+	query, args, err = db.From("items").Select(optionsItem{}).Where(builder.C("created").Eq(created)).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`SELECT "id", "name" FROM "items" WHERE ("created" = '2024-01-02T03:04:05Z')`, query)
+	ds.Empty(args)
+}
+
+func (ds *databaseSuite) TestOptions_inherited() {
+	mDB, mock, err := sqlmock.New()
+	ds.NoError(err)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB), builder.Options{TimeLocation: time.FixedZone("test", 3600)})
+
+	query, _, err := db.From("items").Where(builder.C("created").Eq(created)).WithDialect("default").ToSQL()
+	ds.NoError(err)
+	ds.Equal(`SELECT * FROM "items" WHERE ("created" = '2024-01-02T04:04:05+01:00')`, query)
+	query, _, err = db.From("items").Where(builder.C("created").Eq(created)).Update().
+		Set(builder.Record{"name": "Test"}).ToSQL()
+	ds.NoError(err)
+	ds.Equal(`UPDATE "items" SET "name"='Test' WHERE ("created" = '2024-01-02T04:04:05+01:00')`, query)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "items" SET "created"='2024-01-02T04:04:05\+01:00' WHERE \("id" = 1\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	ds.NoError(db.Transact(func(td *builder.TxDatabase) error {
+		_, err := td.Update("items").Set(builder.Record{"created": created}).Where(builder.C("id").Eq(1)).Exec()
+		return err
+	}))
+	ds.NoError(mock.ExpectationsWereMet())
+}
+
 func TestDatabaseSuite(t *testing.T) {
 	suite.Run(t, new(databaseSuite))
 }
@@ -548,6 +842,294 @@ func (tds *txdatabaseSuite) TestTxDataRace() {
 	})
 }
 
+func (tds *txdatabaseSuite) TestSavepoint() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "items" \("name"\) VALUES \('Test1'\)`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`SAVEPOINT "builder_sp_2"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "items" \("name"\) VALUES \('Test2'\)`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT "builder_sp_2"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	db := builder.New("mock", conn)
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		return td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			if _, err := td.Insert("items").Rows(builder.Record{"name": "Test1"}).ExecCtx(ctx); err != nil {
+				return err
+			}
+			return td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+				_, err := td.Insert("items").Rows(builder.Record{"name": "Test2"}).ExecCtx(ctx)
+				return err
+			})
+		})
+	})
+	tds.NoError(err)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestSavepoint_withError() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT "builder_sp_2"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "builder_sp_2"`).
+		WillReturnError(errors.New("rollback to savepoint error"))
+	mock.ExpectCommit()
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	db := builder.New("mock", conn)
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		err := td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			return errors.New("savepoint error")
+		})
+		tds.EqualError(err, "builder: savepoint error")
+
+		err = td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			return errors.New("savepoint error")
+		})
+		tds.EqualError(
+			err,
+			"savepoint failed: builder: savepoint error, rollback to savepoint failed: builder: rollback to savepoint error",
+		)
+		return nil
+	})
+	tds.NoError(err)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestSavepoint_withPanic() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	db := builder.New("mock", conn)
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		return td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			panic("savepoint panic")
+		})
+	})
+	tds.EqualError(err, `recover from "savepoint panic"`)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestSavepoint_notSupported() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	opts := builder.DefaultDialectOptions()
+	opts.SupportsSavepoint = false
+	builder.RegisterDialect("no-savepoint", opts)
+	defer builder.DeregisterDialect("no-savepoint")
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	db := builder.New("no-savepoint", conn)
+	called := false
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		return td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			called = true
+			return nil
+		})
+	})
+	tds.EqualError(err, "builder: dialect does not support SAVEPOINT [dialect=no-savepoint]")
+	tds.False(called)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestOnCommit() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	var calls []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) { calls = append(calls, name) }
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		td.OnCommit(record("commit_1"))
+		td.OnRollback(record("rollback_1"))
+		td.OnCommit(func(ctx context.Context) { panic("callback panic") })
+		td.OnCommit(record("commit_2"))
+		tds.Empty(calls)
+		return nil
+	})
+	tds.NoError(err)
+	tds.Equal([]string{"commit_1", "commit_2"}, calls)
+
+	calls = nil
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+	err = db.Transact(func(td *builder.TxDatabase) error {
+		td.OnCommit(record("commit_1"))
+		td.OnRollback(record("rollback_1"))
+		return nil
+	})
+	tds.EqualError(err, "builder: commit error")
+	tds.Equal([]string{"rollback_1"}, calls)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestOnRollback() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	var calls []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) { calls = append(calls, name) }
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		td.OnRollback(record("rollback_1"))
+		td.OnCommit(record("commit_1"))
+		td.OnRollback(func(ctx context.Context) { panic("callback panic") })
+		td.OnRollback(record("rollback_2"))
+		return errors.New("transaction error")
+	})
+	tds.EqualError(err, "builder: transaction error")
+	tds.Equal([]string{"rollback_1", "rollback_2"}, calls)
+
+	calls = nil
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		td.OnRollback(record("rollback_1"))
+		panic("transaction panic")
+	}, builder.TransactionOptions{ReadOnly: true})
+	tds.EqualError(err, `recover from "transaction panic"`)
+	tds.Equal([]string{"rollback_1"}, calls)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestOnCommit_withSavepoint() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	var calls []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) { calls = append(calls, name) }
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT "builder_sp_2"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "builder_sp_2"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		td.OnCommit(record("commit_outer"))
+		td.OnRollback(record("rollback_outer"))
+		tds.NoError(td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			td.OnCommit(record("commit_released"))
+			td.OnRollback(record("rollback_released"))
+			return nil
+		}))
+		tds.EqualError(td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			td.OnCommit(record("commit_rolled_back"))
+			td.OnRollback(record("rollback_rolled_back"))
+			return errors.New("savepoint error")
+		}), "builder: savepoint error")
+		tds.Equal([]string{"rollback_rolled_back"}, calls)
+		return nil
+	})
+	tds.NoError(err)
+	tds.Equal([]string{"rollback_rolled_back", "commit_outer", "commit_released"}, calls)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestOnRollback_withSavepoint() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	var calls []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) { calls = append(calls, name) }
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "builder_sp_1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		td.OnRollback(record("rollback_outer"))
+		tds.Error(td.Savepoint(ctx, func(ctx context.Context, td *builder.TxDatabase) error {
+			td.OnRollback(record("rollback_savepoint"))
+			return errors.New("savepoint error")
+		}))
+		// called while the outer transaction is still open
+		tds.Equal([]string{"rollback_savepoint"}, calls)
+		return errors.New("transaction error")
+	})
+	tds.EqualError(err, "builder: transaction error")
+	// the savepoint callback is not called a second time when the outer transaction is rolled back
+	tds.Equal([]string{"rollback_savepoint", "rollback_outer"}, calls)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
+func (tds *txdatabaseSuite) TestStatementTimeout() {
+	mDB, mock, err := sqlmock.New()
+	tds.NoError(err)
+
+	opts := builder.DefaultDialectOptions()
+	opts.StatementTimeoutFragment = []byte("SET LOCAL statement_timeout = ")
+	opts.ResetStatementTimeoutFragment = []byte("SET LOCAL statement_timeout = DEFAULT")
+	builder.RegisterDialect("statement-timeout", opts)
+	defer builder.DeregisterDialect("statement-timeout")
+
+	db := builder.New("statement-timeout", sqlx.NewSqlConnFromDB(mDB))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL statement_timeout = 1500`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "address", "name" FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+	mock.ExpectExec(`SET LOCAL statement_timeout = DEFAULT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE "items" SET "name"='Test2'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SET LOCAL statement_timeout = 1000`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnError(errors.New("canceling statement due to statement timeout"))
+	mock.ExpectExec(`SET LOCAL statement_timeout = DEFAULT`).
+		WillReturnError(errors.New("current transaction is aborted"))
+	mock.ExpectRollback()
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		var items []testItem
+		err := td.From("items").Select("address", "name").WithTimeout(1500*time.Millisecond).QueryRowsCtx(ctx, &items)
+		tds.NoError(err)
+		tds.Len(items, 1)
+
+		// datasets without a timeout do not change the statement timeout
+		_, err = td.Update("items").Set(builder.Record{"name": "Test2"}).AllowFullTable().ExecCtx(ctx)
+		tds.NoError(err)
+
+		_, err = db.Delete("items").WithTimeout(time.Second).AllowFullTable().ExecCtx(ctx)
+		return err
+	})
+	tds.EqualError(err, "builder: canceling statement due to statement timeout")
+	tds.NoError(mock.ExpectationsWereMet())
+
+	// statements executed outside of a transaction only use the context
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = db.Delete("items").WithTimeout(time.Second).AllowFullTable().ExecCtx(context.Background())
+	tds.NoError(err)
+	tds.NoError(mock.ExpectationsWereMet())
+}
+
 func TestTxDatabaseSuite(t *testing.T) {
 	suite.Run(t, new(txdatabaseSuite))
 }
@@ -0,0 +1,1897 @@
+package builder
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+)
+
+type (
+	// Options used when decoding a dataset from JSON. See UnmarshalSelectDataset
+	JSONOptions struct {
+		// Set to true to allow raw SQL in the decoded dataset, e.g. literals (other than *, DEFAULT and ?), WITH table
+		// hints and optimizer hints. Only enable this for JSON from a trusted source.
+		AllowLiterals bool
+	}
+
+	// The versioned JSON representation of a dataset
+	jsonDataset struct {
+		Version int         `json:"version"`
+		Type    string      `json:"type"`
+		Dialect string      `json:"dialect,omitempty"`
+		Select  *jsonSelect `json:"select,omitempty"`
+		Update  *jsonUpdate `json:"update,omitempty"`
+		Delete  *jsonDelete `json:"delete,omitempty"`
+	}
+
+	jsonSelect struct {
+		With           []*jsonExpression `json:"with,omitempty"`
+		Distinct       bool              `json:"distinct,omitempty"`
+		DistinctOn     []*jsonExpression `json:"distinct_on,omitempty"`
+		Select         []*jsonExpression `json:"select,omitempty"`
+		From           []*jsonExpression `json:"from,omitempty"`
+		Joins          []*jsonJoin       `json:"joins,omitempty"`
+		Where          []*jsonExpression `json:"where,omitempty"`
+		GroupBy        []*jsonExpression `json:"group_by,omitempty"`
+		Having         []*jsonExpression `json:"having,omitempty"`
+		Windows        []*jsonExpression `json:"windows,omitempty"`
+		Compounds      []*jsonCompound   `json:"compounds,omitempty"`
+		Order          []*jsonExpression `json:"order,omitempty"`
+		Limit          *jsonExpression   `json:"limit,omitempty"`
+		Offset         uint              `json:"offset,omitempty"`
+		Lock           *jsonLock         `json:"lock,omitempty"`
+		Alias          *jsonExpression   `json:"alias,omitempty"`
+		Timeout        string            `json:"timeout,omitempty"`
+		OptimizerHints []string          `json:"optimizer_hints,omitempty"`
+	}
+
+	jsonUpdate struct {
+		With           []*jsonExpression `json:"with,omitempty"`
+		Table          *jsonExpression   `json:"table"`
+		Set            []*jsonSet        `json:"set"`
+		From           []*jsonExpression `json:"from,omitempty"`
+		Where          []*jsonExpression `json:"where,omitempty"`
+		Order          []*jsonExpression `json:"order,omitempty"`
+		Limit          *jsonExpression   `json:"limit,omitempty"`
+		Returning      []*jsonExpression `json:"returning,omitempty"`
+		Timeout        string            `json:"timeout,omitempty"`
+		OptimizerHints []string          `json:"optimizer_hints,omitempty"`
+	}
+
+	jsonDelete struct {
+		With           []*jsonExpression `json:"with,omitempty"`
+		From           *jsonExpression   `json:"from"`
+		Where          []*jsonExpression `json:"where,omitempty"`
+		Order          []*jsonExpression `json:"order,omitempty"`
+		Limit          *jsonExpression   `json:"limit,omitempty"`
+		Returning      []*jsonExpression `json:"returning,omitempty"`
+		Timeout        string            `json:"timeout,omitempty"`
+		OptimizerHints []string          `json:"optimizer_hints,omitempty"`
+	}
+
+	jsonSet struct {
+		Col   string          `json:"col"`
+		Value *jsonExpression `json:"value"`
+	}
+
+	jsonJoin struct {
+		Type  string            `json:"type"`
+		Table *jsonExpression   `json:"table"`
+		On    []*jsonExpression `json:"on,omitempty"`
+		Using []*jsonExpression `json:"using,omitempty"`
+	}
+
+	jsonCompound struct {
+		Type  string      `json:"type"`
+		Query *jsonSelect `json:"query"`
+	}
+
+	jsonLock struct {
+		Strength string            `json:"strength"`
+		Wait     string            `json:"wait,omitempty"`
+		Of       []*jsonExpression `json:"of,omitempty"`
+	}
+
+	jsonTableHint struct {
+		Type   string   `json:"type"`
+		Values []string `json:"values"`
+	}
+
+	jsonCTESearch struct {
+		Type string            `json:"type"`
+		By   []*jsonExpression `json:"by"`
+		Set  *jsonExpression   `json:"set"`
+	}
+
+	jsonCTECycle struct {
+		Cols    []*jsonExpression `json:"cols"`
+		Set     *jsonExpression   `json:"set"`
+		To      *jsonExpression   `json:"to,omitempty"`
+		Default *jsonExpression   `json:"default,omitempty"`
+		Using   *jsonExpression   `json:"using"`
+	}
+
+	jsonTableFuncColumn struct {
+		Name string `json:"name"`
+		Type string `json:"type,omitempty"`
+	}
+
+	jsonCaseWhen struct {
+		When *jsonExpression `json:"when"`
+		Then *jsonExpression `json:"then"`
+	}
+
+	// A node of the expression tree, the fields used depend on the type of the node
+	jsonExpression struct {
+		Type       string                     `json:"type"`
+		Schema     string                     `json:"schema,omitempty"`
+		Table      string                     `json:"table,omitempty"`
+		Col        string                     `json:"col,omitempty"`
+		Name       string                     `json:"name,omitempty"`
+		Op         string                     `json:"op,omitempty"`
+		Value      any                        `json:"value,omitempty"`
+		SQL        string                     `json:"sql,omitempty"`
+		Recursive  bool                       `json:"recursive,omitempty"`
+		Desc       bool                       `json:"desc,omitempty"`
+		Nulls      string                     `json:"nulls,omitempty"`
+		Expr       *jsonExpression            `json:"expr,omitempty"`
+		As         *jsonExpression            `json:"as,omitempty"`
+		LHS        *jsonExpression            `json:"lhs,omitempty"`
+		RHS        *jsonExpression            `json:"rhs,omitempty"`
+		Start      *jsonExpression            `json:"start,omitempty"`
+		End        *jsonExpression            `json:"end,omitempty"`
+		Items      []*jsonExpression          `json:"items,omitempty"`
+		Map        map[string]*jsonExpression `json:"map,omitempty"`
+		Parent     *jsonExpression            `json:"parent,omitempty"`
+		Partition  []*jsonExpression          `json:"partition,omitempty"`
+		Order      []*jsonExpression          `json:"order,omitempty"`
+		Window     *jsonExpression            `json:"window,omitempty"`
+		WindowName *jsonExpression            `json:"window_name,omitempty"`
+		Hints      []*jsonTableHint           `json:"hints,omitempty"`
+		Whens      []*jsonCaseWhen            `json:"whens,omitempty"`
+		Else       *jsonExpression            `json:"else,omitempty"`
+		Query      *jsonSelect                `json:"query,omitempty"`
+		// the rows of a VALUES list
+		Rows [][]*jsonExpression `json:"rows,omitempty"`
+		// the columns and WITH ORDINALITY of a table function
+		Columns    []*jsonTableFuncColumn `json:"columns,omitempty"`
+		Ordinality bool                   `json:"ordinality,omitempty"`
+		// the materialization, search and cycle clauses of a common table expression
+		Materialized string         `json:"materialized,omitempty"`
+		Search       *jsonCTESearch `json:"search,omitempty"`
+		Cycle        *jsonCTECycle  `json:"cycle,omitempty"`
+	}
+
+	jsonDecoder struct {
+		opts    JSONOptions
+		dialect string
+	}
+)
+
+// The version of the JSON representation of datasets
+const jsonDatasetVersion = 1
+
+var (
+	booleanOperationNames = map[exp.BooleanOperation]string{
+		exp.EqOp: "eq", exp.NeqOp: "neq", exp.IsOp: "is", exp.IsNotOp: "is_not",
+		exp.GtOp: "gt", exp.GteOp: "gte", exp.LtOp: "lt", exp.LteOp: "lte",
+		exp.InOp: "in", exp.NotInOp: "not_in",
+		exp.LikeOp: "like", exp.NotLikeOp: "not_like", exp.ILikeOp: "ilike", exp.NotILikeOp: "not_ilike",
+		exp.RegexpLikeOp: "regexp_like", exp.RegexpNotLikeOp: "regexp_not_like",
+		exp.RegexpILikeOp: "regexp_ilike", exp.RegexpNotILikeOp: "regexp_not_ilike",
+		exp.ArrayContainsOp: "contains", exp.ArrayContainedByOp: "contained_by", exp.ArrayOverlapOp: "overlaps",
+		exp.EqAnyOp: "eq_any",
+	}
+	bitwiseOperationNames = map[exp.BitwiseOperation]string{
+		exp.BitwiseInversionOp: "inversion", exp.BitwiseOrOp: "or", exp.BitwiseAndOp: "and",
+		exp.BitwiseXorOp: "xor", exp.BitwiseLeftShiftOp: "left_shift", exp.BitwiseRightShiftOp: "right_shift",
+	}
+	rangeOperationNames = map[exp.RangeOperation]string{
+		exp.BetweenOp: "between", exp.NotBetweenOp: "not_between",
+	}
+	joinTypeNames = map[exp.JoinType]string{
+		exp.InnerJoinType: "inner", exp.FullOuterJoinType: "full_outer", exp.RightOuterJoinType: "right_outer",
+		exp.LeftOuterJoinType: "left_outer", exp.FullJoinType: "full", exp.RightJoinType: "right",
+		exp.LeftJoinType: "left", exp.NaturalJoinType: "natural", exp.NaturalLeftJoinType: "natural_left",
+		exp.NaturalRightJoinType: "natural_right", exp.NaturalFullJoinType: "natural_full",
+		exp.CrossJoinType: "cross",
+	}
+	compoundTypeNames = map[exp.CompoundType]string{
+		exp.UnionCompoundType: "union", exp.UnionAllCompoundType: "union_all",
+		exp.IntersectCompoundType: "intersect", exp.IntersectAllCompoundType: "intersect_all",
+	}
+	lockStrengthNames = map[exp.LockStrength]string{
+		exp.ForUpdate: "update", exp.ForNoKeyUpdate: "no_key_update", exp.ForShare: "share",
+		exp.ForKeyShare: "key_share",
+	}
+	cteMaterializationNames = map[exp.CTEMaterialization]string{
+		exp.DefaultMaterializationCTE: "", exp.MaterializedCTE: "materialized",
+		exp.NotMaterializedCTE: "not_materialized",
+	}
+	cteSearchTypeNames = map[exp.CTESearchType]string{
+		exp.DepthFirstSearch: "depth_first", exp.BreadthFirstSearch: "breadth_first",
+	}
+	waitOptionNames = map[exp.WaitOption]string{
+		exp.Wait: "", exp.NoWait: "nowait", exp.SkipLocked: "skip_locked",
+	}
+	tableHintTypeNames = map[exp.TableHintType]string{
+		exp.UseIndexHint: "use_index", exp.ForceIndexHint: "force_index", exp.IgnoreIndexHint: "ignore_index",
+		exp.WithTableHint: "with",
+	}
+
+	// raw SQL that is part of the JSON representation must match these patterns unless literals are allowed
+	functionNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	castTypeRegexp     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_ ]*(\([0-9, ]*\))?(\[\])*$`)
+	cteNameRegexp      = regexp.MustCompile(
+		`^[A-Za-z_][A-Za-z0-9_]*(\s*\(\s*[A-Za-z_][A-Za-z0-9_]*(\s*,\s*[A-Za-z_][A-Za-z0-9_]*)*\s*\))?$`,
+	)
+	// the quote characters of the dialects, identifiers are written between them without escaping so decoded
+	// identifiers can not contain them
+	identifierQuoteChars = "\"`[]"
+)
+
+func errUnsupportedJSONExpression(e any) error {
+	return errors.New("unable to encode %T to JSON", e)
+}
+
+func errUnsupportedJSONVersion(version int) error {
+	return errors.New("unsupported JSON dataset version %d", version)
+}
+
+func errUnexpectedJSONDatasetType(expected, actual string) error {
+	return errors.New("expected a JSON %s dataset got %q", expected, actual)
+}
+
+func errInvalidJSONExpression(t, field string) error {
+	return errors.New("invalid JSON expression %q [%s]", t, field)
+}
+
+func errInvalidJSONIdentifier(name, field string) error {
+	return errors.New("identifiers in JSON datasets can not contain quote characters got %q [%s]", name, field)
+}
+
+func errJSONLiteralNotAllowed(sql string) error {
+	return errors.New("raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=%s]", sql)
+}
+
+// Encodes the dataset to its versioned JSON representation, the dataset can be rebuilt with UnmarshalSelectDataset.
+// Values must be primitives, time.Time, []byte, driver.Valuer or slices of them.
+//
+//	data, err := json.Marshal(builder.From("user").Where(builder.C("id").Eq(1)))
+func (sd *SelectDataset) MarshalJSON() ([]byte, error) {
+	if sd.err != nil {
+		return nil, sd.err
+	}
+	s, err := encodeSelectClauses(sd.clauses)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonDataset{Version: jsonDatasetVersion, Type: "select", Dialect: sd.dialect.Dialect(), Select: s})
+}
+
+// Encodes the dataset to its versioned JSON representation, the dataset can be rebuilt with UnmarshalUpdateDataset.
+func (ud *UpdateDataset) MarshalJSON() ([]byte, error) {
+	if ud.err != nil {
+		return nil, ud.err
+	}
+	u, err := encodeUpdateClauses(ud.clauses)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonDataset{Version: jsonDatasetVersion, Type: "update", Dialect: ud.dialect.Dialect(), Update: u})
+}
+
+// Encodes the dataset to its versioned JSON representation, the dataset can be rebuilt with UnmarshalDeleteDataset.
+func (dd *DeleteDataset) MarshalJSON() ([]byte, error) {
+	if dd.err != nil {
+		return nil, dd.err
+	}
+	d, err := encodeDeleteClauses(dd.clauses)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonDataset{Version: jsonDatasetVersion, Type: "delete", Dialect: dd.dialect.Dialect(), Delete: d})
+}
+
+// Rebuilds a SelectDataset from the JSON created by SelectDataset#MarshalJSON. Raw SQL (e.g. L("col = 1")) is
+// rejected unless JSONOptions#AllowLiterals is set, only the first JSONOptions are used.
+//
+//	ds, err := builder.UnmarshalSelectDataset(data)
+func UnmarshalSelectDataset(data []byte, opts ...JSONOptions) (*SelectDataset, error) {
+	jd, d, err := decodeJSONDataset(data, "select", opts)
+	if err != nil {
+		return nil, err
+	}
+	if jd.Select == nil {
+		return nil, errInvalidJSONExpression("select", "select")
+	}
+	return d.selectDataset(jd.Select)
+}
+
+// Rebuilds an UpdateDataset from the JSON created by UpdateDataset#MarshalJSON. See UnmarshalSelectDataset.
+func UnmarshalUpdateDataset(data []byte, opts ...JSONOptions) (*UpdateDataset, error) {
+	jd, d, err := decodeJSONDataset(data, "update", opts)
+	if err != nil {
+		return nil, err
+	}
+	if jd.Update == nil {
+		return nil, errInvalidJSONExpression("update", "update")
+	}
+	clauses, err := d.updateClauses(jd.Update)
+	if err != nil {
+		return nil, err
+	}
+	return newUpdateDataset(d.dialect, nil, nil).copy(clauses), nil
+}
+
+// Rebuilds a DeleteDataset from the JSON created by DeleteDataset#MarshalJSON. See UnmarshalSelectDataset.
+func UnmarshalDeleteDataset(data []byte, opts ...JSONOptions) (*DeleteDataset, error) {
+	jd, d, err := decodeJSONDataset(data, "delete", opts)
+	if err != nil {
+		return nil, err
+	}
+	if jd.Delete == nil {
+		return nil, errInvalidJSONExpression("delete", "delete")
+	}
+	clauses, err := d.deleteClauses(jd.Delete)
+	if err != nil {
+		return nil, err
+	}
+	return newDeleteDataset(d.dialect, nil, nil).copy(clauses), nil
+}
+
+func decodeJSONDataset(data []byte, datasetType string, opts []JSONOptions) (*jsonDataset, *jsonDecoder, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// numbers are decoded as json.Number so integers are not converted to float64
+	dec.UseNumber()
+	jd := new(jsonDataset)
+	if err := dec.Decode(jd); err != nil {
+		return nil, nil, err
+	}
+	if jd.Version != jsonDatasetVersion {
+		return nil, nil, errUnsupportedJSONVersion(jd.Version)
+	}
+	if jd.Type != datasetType {
+		return nil, nil, errUnexpectedJSONDatasetType(datasetType, jd.Type)
+	}
+	d := &jsonDecoder{dialect: jd.Dialect}
+	if len(opts) > 0 {
+		d.opts = opts[0]
+	}
+	if d.dialect == "" {
+		d.dialect = "default"
+	}
+	return jd, d, nil
+}
+
+func encodeSelectClauses(c exp.SelectClauses) (s *jsonSelect, err error) {
+	s = &jsonSelect{Offset: c.Offset(), OptimizerHints: c.OptimizerHints(), Timeout: encodeTimeout(c.Timeout())}
+	if s.With, err = encodeCommonTables(c.CommonTables()); err != nil {
+		return nil, err
+	}
+	if distinct := c.Distinct(); distinct != nil {
+		s.Distinct = true
+		if s.DistinctOn, err = encodeColumnList(distinct); err != nil {
+			return nil, err
+		}
+	}
+	if !c.IsDefaultSelect() {
+		if s.Select, err = encodeColumnList(c.Select()); err != nil {
+			return nil, err
+		}
+	}
+	if s.From, err = encodeColumnList(c.From()); err != nil {
+		return nil, err
+	}
+	for _, j := range c.Joins() {
+		jj, err := encodeJoin(j)
+		if err != nil {
+			return nil, err
+		}
+		s.Joins = append(s.Joins, jj)
+	}
+	if s.Where, err = encodeExpressionList(c.Where()); err != nil {
+		return nil, err
+	}
+	if s.GroupBy, err = encodeColumnList(c.GroupBy()); err != nil {
+		return nil, err
+	}
+	if s.Having, err = encodeExpressionList(c.Having()); err != nil {
+		return nil, err
+	}
+	for _, w := range c.Windows() {
+		jw, err := encodeExpression(w)
+		if err != nil {
+			return nil, err
+		}
+		s.Windows = append(s.Windows, jw)
+	}
+	for _, ce := range c.Compounds() {
+		rhs, ok := ce.RHS().(*SelectDataset)
+		if !ok {
+			return nil, errUnsupportedJSONExpression(ce.RHS())
+		}
+		q, err := encodeSelectClauses(rhs.clauses)
+		if err != nil {
+			return nil, err
+		}
+		s.Compounds = append(s.Compounds, &jsonCompound{Type: compoundTypeNames[ce.Type()], Query: q})
+	}
+	if s.Order, err = encodeColumnList(c.Order()); err != nil {
+		return nil, err
+	}
+	if c.HasLimit() {
+		if s.Limit, err = encodeValue(c.Limit()); err != nil {
+			return nil, err
+		}
+	}
+	if l := c.Lock(); l != nil && l.Strength() != exp.ForNolock {
+		s.Lock = &jsonLock{Strength: lockStrengthNames[l.Strength()], Wait: waitOptionNames[l.WaitOption()]}
+		for _, of := range l.Of() {
+			jof, err := encodeExpression(of)
+			if err != nil {
+				return nil, err
+			}
+			s.Lock.Of = append(s.Lock.Of, jof)
+		}
+	}
+	if c.HasAlias() {
+		if s.Alias, err = encodeExpression(c.Alias()); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func encodeUpdateClauses(c exp.UpdateClauses) (u *jsonUpdate, err error) {
+	u = &jsonUpdate{OptimizerHints: c.OptimizerHints(), Timeout: encodeTimeout(c.Timeout())}
+	if u.With, err = encodeCommonTables(c.CommonTables()); err != nil {
+		return nil, err
+	}
+	if u.Table, err = encodeValue(c.Table()); err != nil {
+		return nil, err
+	}
+	if c.HasSetValues() {
+		updates, err := exp.NewUpdateExpressions(c.SetValues())
+		if err != nil {
+			return nil, err
+		}
+		for _, update := range updates {
+			col, err := encodeValue(update.Col())
+			if err != nil {
+				return nil, err
+			}
+			val, err := encodeValue(update.Val())
+			if err != nil {
+				return nil, err
+			}
+			u.Set = append(u.Set, &jsonSet{Col: identifierPath(col), Value: val})
+		}
+	}
+	if u.From, err = encodeColumnList(c.From()); err != nil {
+		return nil, err
+	}
+	if u.Where, err = encodeExpressionList(c.Where()); err != nil {
+		return nil, err
+	}
+	if u.Order, err = encodeColumnList(c.Order()); err != nil {
+		return nil, err
+	}
+	if c.HasLimit() {
+		if u.Limit, err = encodeValue(c.Limit()); err != nil {
+			return nil, err
+		}
+	}
+	if u.Returning, err = encodeColumnList(c.Returning()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func encodeDeleteClauses(c exp.DeleteClauses) (d *jsonDelete, err error) {
+	d = &jsonDelete{OptimizerHints: c.OptimizerHints(), Timeout: encodeTimeout(c.Timeout())}
+	if d.With, err = encodeCommonTables(c.CommonTables()); err != nil {
+		return nil, err
+	}
+	if c.HasFrom() {
+		if d.From, err = encodeExpression(c.From()); err != nil {
+			return nil, err
+		}
+	}
+	if d.Where, err = encodeExpressionList(c.Where()); err != nil {
+		return nil, err
+	}
+	if d.Order, err = encodeColumnList(c.Order()); err != nil {
+		return nil, err
+	}
+	if c.HasLimit() {
+		if d.Limit, err = encodeValue(c.Limit()); err != nil {
+			return nil, err
+		}
+	}
+	if d.Returning, err = encodeColumnList(c.Returning()); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func encodeTimeout(timeout time.Duration) string {
+	if timeout <= 0 {
+		return ""
+	}
+	return timeout.String()
+}
+
+func encodeCommonTables(ctes []exp.CommonTableExpression) ([]*jsonExpression, error) {
+	var encoded []*jsonExpression
+	for _, cte := range ctes {
+		e, err := encodeExpression(cte)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, e)
+	}
+	return encoded, nil
+}
+
+func encodeJoin(j exp.JoinExpression) (*jsonJoin, error) {
+	table, err := encodeValue(j.Table())
+	if err != nil {
+		return nil, err
+	}
+	jj := &jsonJoin{Type: joinTypeNames[j.JoinType()], Table: table}
+	if cj, ok := j.(exp.ConditionedJoinExpression); ok && !cj.IsConditionEmpty() {
+		switch c := cj.Condition().(type) {
+		case exp.JoinOnCondition:
+			jj.On, err = encodeExpressionList(c.On())
+		case exp.JoinUsingCondition:
+			jj.Using, err = encodeColumnList(c.Using())
+		}
+	}
+	return jj, err
+}
+
+func encodeColumnList(cl exp.ColumnListExpression) ([]*jsonExpression, error) {
+	if cl == nil {
+		return nil, nil
+	}
+	return encodeExpressions(cl.Columns())
+}
+
+func encodeExpressionList(el exp.ExpressionList) ([]*jsonExpression, error) {
+	if el == nil {
+		return nil, nil
+	}
+	return encodeExpressions(el.Expressions())
+}
+
+func encodeExpressions(es []exp.Expression) ([]*jsonExpression, error) {
+	encoded := make([]*jsonExpression, 0, len(es))
+	for _, e := range es {
+		je, err := encodeValue(e)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, je)
+	}
+	return encoded, nil
+}
+
+func encodeValues(vals []any) ([]*jsonExpression, error) {
+	encoded := make([]*jsonExpression, 0, len(vals))
+	for _, v := range vals {
+		je, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, je)
+	}
+	return encoded, nil
+}
+
+// encodes a value of an expression, e.g. the RHS of a boolean expression
+func encodeValue(val any) (*jsonExpression, error) {
+	switch v := val.(type) {
+	case nil:
+		return &jsonExpression{Type: "null"}, nil
+	case exp.Op:
+		return encodeMap("op", v)
+	case exp.Expression:
+		return encodeExpression(v)
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return &jsonExpression{Type: "value", Value: v}, nil
+	case time.Time:
+		return &jsonExpression{Type: "time", Value: v.Format(time.RFC3339Nano)}, nil
+	case *time.Time:
+		if v == nil {
+			return &jsonExpression{Type: "null"}, nil
+		}
+		return encodeValue(*v)
+	case []byte:
+		return &jsonExpression{Type: "bytes", Value: v}, nil
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return &jsonExpression{Type: "null"}, nil
+		}
+		dv, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		return encodeValue(dv)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		encoded, err := encodeValues(items)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonExpression{Type: "list", Items: encoded}, nil
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return &jsonExpression{Type: "value", Value: rv.Interface()}, nil
+	case reflect.Invalid:
+		return &jsonExpression{Type: "null"}, nil
+	}
+	return nil, errUnsupportedJSONExpression(val)
+}
+
+func encodeMap(t string, m map[string]any) (*jsonExpression, error) {
+	je := &jsonExpression{Type: t, Map: make(map[string]*jsonExpression, len(m))}
+	for key, val := range m {
+		encoded, err := encodeValue(val)
+		if err != nil {
+			return nil, err
+		}
+		je.Map[key] = encoded
+	}
+	return je, nil
+}
+
+// nolint:gocyclo // not complex just long
+func encodeExpression(e exp.Expression) (je *jsonExpression, err error) {
+	switch t := e.(type) {
+	case *SelectDataset:
+		if t.err != nil {
+			return nil, t.err
+		}
+		q, err := encodeSelectClauses(t.clauses)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonExpression{Type: "select", Query: q}, nil
+	case exp.IdentifierExpression:
+		je = &jsonExpression{Type: "ident", Schema: t.GetSchema(), Table: t.GetTable()}
+		switch col := t.GetCol().(type) {
+		case nil:
+		case string:
+			je.Col = col
+		case exp.LiteralExpression:
+			if col.Literal() != "*" {
+				return nil, errUnsupportedJSONExpression(col)
+			}
+			je.Col = "*"
+		default:
+			return nil, errUnsupportedJSONExpression(col)
+		}
+		return je, nil
+	case exp.LiteralExpression:
+		je = &jsonExpression{Type: "literal", SQL: t.Literal()}
+		je.Items, err = encodeValues(t.Args())
+		return je, err
+	case exp.Ex:
+		return encodeMap("ex", t)
+	case exp.ExOr:
+		return encodeMap("ex_or", t)
+	case exp.ExpressionList:
+		je = &jsonExpression{Type: "and"}
+		if t.Type() == exp.OrType {
+			je.Type = "or"
+		}
+		je.Items, err = encodeExpressions(t.Expressions())
+		return je, err
+	case exp.ColumnListExpression:
+		je = &jsonExpression{Type: "columns"}
+		je.Items, err = encodeColumnList(t)
+		return je, err
+	case exp.BooleanExpression:
+		je = &jsonExpression{Type: "bool", Op: booleanOperationNames[t.Op()]}
+		if je.LHS, err = encodeValue(t.LHS()); err != nil {
+			return nil, err
+		}
+		je.RHS, err = encodeValue(t.RHS())
+		return je, err
+	case exp.BitwiseExpression:
+		je = &jsonExpression{Type: "bitwise", Op: bitwiseOperationNames[t.Op()]}
+		if t.LHS() != nil {
+			if je.LHS, err = encodeValue(t.LHS()); err != nil {
+				return nil, err
+			}
+		}
+		je.RHS, err = encodeValue(t.RHS())
+		return je, err
+	case exp.RangeExpression:
+		je = &jsonExpression{Type: "range", Op: rangeOperationNames[t.Op()]}
+		if je.LHS, err = encodeValue(t.LHS()); err != nil {
+			return nil, err
+		}
+		if je.Start, err = encodeValue(t.RHS().Start()); err != nil {
+			return nil, err
+		}
+		je.End, err = encodeValue(t.RHS().End())
+		return je, err
+	case exp.SQLWindowFunctionExpression:
+		je = &jsonExpression{Type: "window_func"}
+		if je.Expr, err = encodeExpression(t.Func()); err != nil {
+			return nil, err
+		}
+		if t.HasWindow() {
+			if je.Window, err = encodeExpression(t.Window()); err != nil {
+				return nil, err
+			}
+		}
+		if t.HasWindowName() {
+			if je.WindowName, err = encodeExpression(t.WindowName()); err != nil {
+				return nil, err
+			}
+		}
+		return je, nil
+	case exp.SQLFunctionExpression:
+		je = &jsonExpression{Type: "func", Name: t.Name()}
+		je.Items, err = encodeValues(t.Args())
+		return je, err
+	case exp.WindowExpression:
+		je = &jsonExpression{Type: "window"}
+		if t.HasName() {
+			if je.WindowName, err = encodeExpression(t.Name()); err != nil {
+				return nil, err
+			}
+		}
+		if t.HasParent() {
+			if je.Parent, err = encodeExpression(t.Parent()); err != nil {
+				return nil, err
+			}
+		}
+		if je.Partition, err = encodeColumnList(t.PartitionCols()); err != nil {
+			return nil, err
+		}
+		je.Order, err = encodeColumnList(t.OrderCols())
+		return je, err
+	case exp.OrderedExpression:
+		je = &jsonExpression{Type: "order", Desc: !t.IsAsc()}
+		switch t.NullSortType() {
+		case exp.NullsFirstSortType:
+			je.Nulls = "first"
+		case exp.NullsLastSortType:
+			je.Nulls = "last"
+		}
+		je.Expr, err = encodeValue(t.SortExpression())
+		return je, err
+	case exp.TableHintExpression:
+		je = &jsonExpression{Type: "table_hint"}
+		for _, h := range t.Hints() {
+			je.Hints = append(je.Hints, &jsonTableHint{Type: tableHintTypeNames[h.Type()], Values: h.Values()})
+		}
+		je.Expr, err = encodeValue(t.Table())
+		return je, err
+	case exp.AliasedExpression:
+		je = &jsonExpression{Type: "alias"}
+		if je.Expr, err = encodeValue(t.Aliased()); err != nil {
+			return nil, err
+		}
+		je.As, err = encodeExpression(t.GetAs())
+		return je, err
+	case exp.ArrayExpression:
+		je = &jsonExpression{Type: "array"}
+		je.Items, err = encodeValues(t.Elements())
+		return je, err
+	case exp.CastExpression:
+		je = &jsonExpression{Type: "cast", Name: t.Type().Literal()}
+		je.Expr, err = encodeValue(t.Casted())
+		return je, err
+	case exp.CaseExpression:
+		je = &jsonExpression{Type: "case"}
+		if t.GetValue() != nil {
+			if je.Expr, err = encodeValue(t.GetValue()); err != nil {
+				return nil, err
+			}
+		}
+		for _, w := range t.GetWhens() {
+			jw := &jsonCaseWhen{}
+			if jw.When, err = encodeValue(w.Condition()); err != nil {
+				return nil, err
+			}
+			if jw.Then, err = encodeValue(w.Result()); err != nil {
+				return nil, err
+			}
+			je.Whens = append(je.Whens, jw)
+		}
+		if t.GetElse() != nil {
+			je.Else, err = encodeValue(t.GetElse().Result())
+		}
+		return je, err
+	case exp.CommonTableExpression:
+		return encodeCommonTable(t)
+	case exp.LateralExpression:
+		je = &jsonExpression{Type: "lateral"}
+		je.Expr, err = encodeValue(t.Table())
+		return je, err
+	case exp.ValuesListExpression:
+		return encodeValuesList(t)
+	case exp.TableFunctionExpression:
+		return encodeTableFunction(t)
+	}
+	return nil, errUnsupportedJSONExpression(e)
+}
+
+func encodeTableFunction(tf exp.TableFunctionExpression) (je *jsonExpression, err error) {
+	je = &jsonExpression{Type: "table_func", Name: tf.Name(), Ordinality: tf.IsWithOrdinality()}
+	if je.Items, err = encodeValues(tf.Args()); err != nil {
+		return nil, err
+	}
+	for _, col := range tf.Columns() {
+		je.Columns = append(je.Columns, &jsonTableFuncColumn{Name: col.Name(), Type: col.Type()})
+	}
+	if tf.GetAs() != nil {
+		je.As, err = encodeExpression(tf.GetAs())
+	}
+	return je, err
+}
+
+func encodeValuesList(vl exp.ValuesListExpression) (je *jsonExpression, err error) {
+	je = &jsonExpression{Type: "values"}
+	for _, row := range vl.Rows() {
+		jr, err := encodeValues(row)
+		if err != nil {
+			return nil, err
+		}
+		je.Rows = append(je.Rows, jr)
+	}
+	if vl.GetAs() != nil {
+		if je.As, err = encodeExpression(vl.GetAs()); err != nil {
+			return nil, err
+		}
+	}
+	je.Items, err = encodeColumnList(vl.Cols())
+	return je, err
+}
+
+func encodeCommonTable(cte exp.CommonTableExpression) (je *jsonExpression, err error) {
+	je = &jsonExpression{
+		Type:         "cte",
+		Recursive:    cte.IsRecursive(),
+		Materialized: cteMaterializationNames[cte.Materialization()],
+	}
+	if cte.Identifier() != nil {
+		if je.As, err = encodeExpression(cte.Identifier()); err != nil {
+			return nil, err
+		}
+	} else {
+		je.Name = cte.Name().Literal()
+	}
+	if je.Items, err = encodeColumnList(cte.Cols()); err != nil {
+		return nil, err
+	}
+	if search := cte.SearchClause(); search != nil {
+		je.Search = &jsonCTESearch{Type: cteSearchTypeNames[search.Type()]}
+		if je.Search.By, err = encodeColumnList(search.By()); err != nil {
+			return nil, err
+		}
+		if je.Search.Set, err = encodeExpression(search.Set()); err != nil {
+			return nil, err
+		}
+	}
+	if cycle := cte.CycleClause(); cycle != nil {
+		if je.Cycle, err = encodeCTECycle(cycle); err != nil {
+			return nil, err
+		}
+	}
+	je.Expr, err = encodeValue(cte.SubQuery())
+	return je, err
+}
+
+func encodeCTECycle(cycle exp.CTECycle) (jc *jsonCTECycle, err error) {
+	jc = &jsonCTECycle{}
+	if jc.Cols, err = encodeColumnList(cycle.Cols()); err != nil {
+		return nil, err
+	}
+	if jc.Set, err = encodeExpression(cycle.Set()); err != nil {
+		return nil, err
+	}
+	if cycle.HasMark() {
+		if jc.To, err = encodeValue(cycle.MarkValue()); err != nil {
+			return nil, err
+		}
+		if jc.Default, err = encodeValue(cycle.MarkDefault()); err != nil {
+			return nil, err
+		}
+	}
+	jc.Using, err = encodeExpression(cycle.Using())
+	return jc, err
+}
+
+// returns the dotted path of an encoded identifier e.g. table.col
+func identifierPath(je *jsonExpression) string {
+	path := je.Col
+	if je.Table != "" {
+		path = je.Table + "." + path
+	}
+	if je.Schema != "" {
+		path = je.Schema + "." + path
+	}
+	return path
+}
+
+func (d *jsonDecoder) selectDataset(s *jsonSelect) (*SelectDataset, error) {
+	clauses, err := d.selectClauses(s)
+	if err != nil {
+		return nil, err
+	}
+	return newDataset(d.dialect, nil, nil).copy(clauses), nil
+}
+
+// nolint:gocyclo // not complex just long
+func (d *jsonDecoder) selectClauses(s *jsonSelect) (exp.SelectClauses, error) {
+	c := exp.NewSelectClauses()
+	ctes, err := d.commonTables(s.With)
+	if err != nil {
+		return nil, err
+	}
+	for _, cte := range ctes {
+		c = c.CommonTablesAppend(cte)
+	}
+	if s.Distinct {
+		on, err := d.expressions(s.DistinctOn)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetDistinct(exp.NewColumnListExpression(toAny(on)...))
+	}
+	if len(s.Select) > 0 {
+		cols, err := d.expressions(s.Select)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetSelect(exp.NewColumnListExpression(toAny(cols)...))
+	}
+	if len(s.From) > 0 {
+		from, err := d.expressions(s.From)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetFrom(exp.NewColumnListExpression(toAny(from)...))
+	}
+	for _, jj := range s.Joins {
+		j, err := d.join(jj)
+		if err != nil {
+			return nil, err
+		}
+		c = c.JoinsAppend(j)
+	}
+	where, err := d.expressions(s.Where)
+	if err != nil {
+		return nil, err
+	}
+	c = c.WhereAppend(where...)
+	if len(s.GroupBy) > 0 {
+		groupBy, err := d.expressions(s.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetGroupBy(exp.NewColumnListExpression(toAny(groupBy)...))
+	}
+	having, err := d.expressions(s.Having)
+	if err != nil {
+		return nil, err
+	}
+	c = c.HavingAppend(having...)
+	for _, jw := range s.Windows {
+		w, err := d.expression(jw)
+		if err != nil {
+			return nil, err
+		}
+		window, ok := w.(exp.WindowExpression)
+		if !ok {
+			return nil, errInvalidJSONExpression(jw.Type, "windows")
+		}
+		c = c.WindowsAppend(window)
+	}
+	for _, jc := range s.Compounds {
+		var ct exp.CompoundType
+		if !lookupName(compoundTypeNames, jc.Type, &ct) || jc.Query == nil {
+			return nil, errInvalidJSONExpression(jc.Type, "compounds")
+		}
+		rhs, err := d.selectDataset(jc.Query)
+		if err != nil {
+			return nil, err
+		}
+		c = c.CompoundsAppend(exp.NewCompoundExpression(ct, rhs))
+	}
+	order, err := d.orderedExpressions(s.Order)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) > 0 {
+		c = c.SetOrder(order...)
+	}
+	if s.Limit != nil {
+		limit, err := d.value(s.Limit)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetLimit(limit)
+	}
+	c = c.SetOffset(s.Offset)
+	if s.Lock != nil {
+		var strength exp.LockStrength
+		if !lookupName(lockStrengthNames, s.Lock.Strength, &strength) {
+			return nil, errInvalidJSONExpression(s.Lock.Strength, "lock")
+		}
+		var wait exp.WaitOption
+		if !lookupName(waitOptionNames, s.Lock.Wait, &wait) {
+			return nil, errInvalidJSONExpression(s.Lock.Wait, "lock")
+		}
+		of, err := d.identifiers(s.Lock.Of, "lock")
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetLock(exp.NewLock(strength, wait, of...))
+	}
+	if s.Alias != nil {
+		alias, err := d.identifier(s.Alias, "alias")
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetAlias(alias)
+	}
+	timeout, err := d.timeout(s.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c = c.SetTimeout(timeout)
+	hints, err := d.optimizerHints(s.OptimizerHints)
+	if err != nil {
+		return nil, err
+	}
+	return c.OptimizerHintsAppend(hints...), nil
+}
+
+func (d *jsonDecoder) updateClauses(u *jsonUpdate) (exp.UpdateClauses, error) {
+	c := exp.NewUpdateClauses()
+	ctes, err := d.commonTables(u.With)
+	if err != nil {
+		return nil, err
+	}
+	for _, cte := range ctes {
+		c = c.CommonTablesAppend(cte)
+	}
+	if u.Table == nil {
+		return nil, errInvalidJSONExpression("update", "table")
+	}
+	table, err := d.expression(u.Table)
+	if err != nil {
+		return nil, err
+	}
+	c = c.SetTable(table)
+	if len(u.Set) > 0 {
+		record := make(exp.Record, len(u.Set))
+		for _, s := range u.Set {
+			if s.Value == nil {
+				return nil, errInvalidJSONExpression(s.Col, "set")
+			}
+			if err := d.identifierNames("set", s.Col); err != nil {
+				return nil, err
+			}
+			if record[s.Col], err = d.value(s.Value); err != nil {
+				return nil, err
+			}
+		}
+		c = c.SetSetValues(record)
+	}
+	if len(u.From) > 0 {
+		from, err := d.expressions(u.From)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetFrom(exp.NewColumnListExpression(toAny(from)...))
+	}
+	where, err := d.expressions(u.Where)
+	if err != nil {
+		return nil, err
+	}
+	c = c.WhereAppend(where...)
+	order, err := d.orderedExpressions(u.Order)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) > 0 {
+		c = c.SetOrder(order...)
+	}
+	if u.Limit != nil {
+		limit, err := d.value(u.Limit)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetLimit(limit)
+	}
+	if len(u.Returning) > 0 {
+		returning, err := d.expressions(u.Returning)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetReturning(exp.NewColumnListExpression(toAny(returning)...))
+	}
+	timeout, err := d.timeout(u.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c = c.SetTimeout(timeout)
+	hints, err := d.optimizerHints(u.OptimizerHints)
+	if err != nil {
+		return nil, err
+	}
+	return c.OptimizerHintsAppend(hints...), nil
+}
+
+func (d *jsonDecoder) deleteClauses(dl *jsonDelete) (exp.DeleteClauses, error) {
+	c := exp.NewDeleteClauses()
+	ctes, err := d.commonTables(dl.With)
+	if err != nil {
+		return nil, err
+	}
+	for _, cte := range ctes {
+		c = c.CommonTablesAppend(cte)
+	}
+	if dl.From != nil {
+		from, err := d.identifier(dl.From, "from")
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetFrom(from)
+	}
+	where, err := d.expressions(dl.Where)
+	if err != nil {
+		return nil, err
+	}
+	c = c.WhereAppend(where...)
+	order, err := d.orderedExpressions(dl.Order)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) > 0 {
+		c = c.SetOrder(order...)
+	}
+	if dl.Limit != nil {
+		limit, err := d.value(dl.Limit)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetLimit(limit)
+	}
+	if len(dl.Returning) > 0 {
+		returning, err := d.expressions(dl.Returning)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetReturning(exp.NewColumnListExpression(toAny(returning)...))
+	}
+	timeout, err := d.timeout(dl.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c = c.SetTimeout(timeout)
+	hints, err := d.optimizerHints(dl.OptimizerHints)
+	if err != nil {
+		return nil, err
+	}
+	return c.OptimizerHintsAppend(hints...), nil
+}
+
+func (d *jsonDecoder) timeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(timeout)
+}
+
+func (d *jsonDecoder) optimizerHints(hints []string) ([]string, error) {
+	if len(hints) > 0 && !d.opts.AllowLiterals {
+		return nil, errJSONLiteralNotAllowed(hints[0])
+	}
+	return hints, nil
+}
+
+func (d *jsonDecoder) commonTables(jes []*jsonExpression) ([]exp.CommonTableExpression, error) {
+	ctes := make([]exp.CommonTableExpression, 0, len(jes))
+	for _, je := range jes {
+		e, err := d.expression(je)
+		if err != nil {
+			return nil, err
+		}
+		cte, ok := e.(exp.CommonTableExpression)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "with")
+		}
+		ctes = append(ctes, cte)
+	}
+	return ctes, nil
+}
+
+func (d *jsonDecoder) join(jj *jsonJoin) (exp.JoinExpression, error) {
+	var jt exp.JoinType
+	if !lookupName(joinTypeNames, jj.Type, &jt) || jj.Table == nil {
+		return nil, errInvalidJSONExpression(jj.Type, "joins")
+	}
+	table, err := d.expression(jj.Table)
+	if err != nil {
+		return nil, err
+	}
+	if !exp.ConditionedJoinTypes[jt] {
+		return exp.NewUnConditionedJoinExpression(jt, table), nil
+	}
+	if len(jj.Using) > 0 {
+		using, err := d.expressions(jj.Using)
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewConditionedJoinExpression(jt, table, exp.NewJoinUsingCondition(toAny(using)...)), nil
+	}
+	on, err := d.expressions(jj.On)
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewConditionedJoinExpression(jt, table, exp.NewJoinOnCondition(on...)), nil
+}
+
+func (d *jsonDecoder) orderedExpressions(jes []*jsonExpression) ([]exp.OrderedExpression, error) {
+	order := make([]exp.OrderedExpression, 0, len(jes))
+	for _, je := range jes {
+		e, err := d.expression(je)
+		if err != nil {
+			return nil, err
+		}
+		oe, ok := e.(exp.OrderedExpression)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "order")
+		}
+		order = append(order, oe)
+	}
+	return order, nil
+}
+
+func (d *jsonDecoder) identifiers(jes []*jsonExpression, field string) ([]exp.IdentifierExpression, error) {
+	idents := make([]exp.IdentifierExpression, 0, len(jes))
+	for _, je := range jes {
+		ident, err := d.identifier(je, field)
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+	}
+	return idents, nil
+}
+
+// decodes the names of encoded column identifiers, e.g. the columns of a CTE
+func (d *jsonDecoder) columnNames(jes []*jsonExpression, field string) ([]string, error) {
+	cols, err := d.identifiers(jes, field)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		name, ok := col.GetCol().(string)
+		if !ok {
+			return nil, errInvalidJSONExpression("ident", field)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (d *jsonDecoder) identifier(je *jsonExpression, field string) (exp.IdentifierExpression, error) {
+	if je == nil || je.Type != "ident" {
+		return nil, errInvalidJSONExpression(typeOf(je), field)
+	}
+	return d.identifierExpression(je, field)
+}
+
+// decodes the schema, table and column of an "ident" node
+func (d *jsonDecoder) identifierExpression(je *jsonExpression, field string) (exp.IdentifierExpression, error) {
+	if err := d.identifierNames(field, je.Schema, je.Table, je.Col); err != nil {
+		return nil, err
+	}
+	return exp.NewIdentifierExpression(je.Schema, je.Table, je.Col), nil
+}
+
+// returns an error if a decoded name written as an identifier contains a quote character
+func (d *jsonDecoder) identifierNames(field string, names ...string) error {
+	for _, name := range names {
+		if strings.ContainsAny(name, identifierQuoteChars) {
+			return errInvalidJSONIdentifier(name, field)
+		}
+	}
+	return nil
+}
+
+func (d *jsonDecoder) expressions(jes []*jsonExpression) ([]exp.Expression, error) {
+	es := make([]exp.Expression, 0, len(jes))
+	for _, je := range jes {
+		e, err := d.expression(je)
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	return es, nil
+}
+
+func (d *jsonDecoder) expression(je *jsonExpression) (exp.Expression, error) {
+	v, err := d.value(je)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := v.(exp.Expression)
+	if !ok {
+		return nil, errInvalidJSONExpression(je.Type, "expression")
+	}
+	return e, nil
+}
+
+func (d *jsonDecoder) values(jes []*jsonExpression) ([]any, error) {
+	vals := make([]any, 0, len(jes))
+	for _, je := range jes {
+		v, err := d.value(je)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+func (d *jsonDecoder) mapValues(je *jsonExpression) (map[string]any, error) {
+	m := make(map[string]any, len(je.Map))
+	for key, jv := range je.Map {
+		v, err := d.value(jv)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// decodes the values of an "ex" or "ex_or" node, the keys are the columns
+func (d *jsonDecoder) columnValues(je *jsonExpression) (map[string]any, error) {
+	for col := range je.Map {
+		if err := d.identifierNames("map", col); err != nil {
+			return nil, err
+		}
+	}
+	return d.mapValues(je)
+}
+
+// nolint:gocyclo // not complex just long
+func (d *jsonDecoder) value(je *jsonExpression) (any, error) {
+	if je == nil {
+		return nil, errInvalidJSONExpression("", "expression")
+	}
+	switch je.Type {
+	case "null":
+		return nil, nil
+	case "value":
+		return jsonPrimitive(je.Value)
+	case "time":
+		s, ok := je.Value.(string)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "value")
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case "bytes":
+		s, ok := je.Value.(string)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "value")
+		}
+		var b []byte
+		err := json.Unmarshal([]byte(`"`+s+`"`), &b)
+		return b, err
+	case "list":
+		return d.values(je.Items)
+	case "select":
+		if je.Query == nil {
+			return nil, errInvalidJSONExpression(je.Type, "query")
+		}
+		return d.selectDataset(je.Query)
+	case "ident":
+		return d.identifierExpression(je, "ident")
+	case "literal":
+		return d.literal(je)
+	case "ex":
+		m, err := d.columnValues(je)
+		return exp.Ex(m), err
+	case "ex_or":
+		m, err := d.columnValues(je)
+		return exp.ExOr(m), err
+	case "op":
+		m, err := d.mapValues(je)
+		return exp.Op(m), err
+	case "and", "or":
+		es, err := d.expressions(je.Items)
+		if err != nil {
+			return nil, err
+		}
+		if je.Type == "or" {
+			return exp.NewExpressionList(exp.OrType, es...), nil
+		}
+		return exp.NewExpressionList(exp.AndType, es...), nil
+	case "columns":
+		es, err := d.expressions(je.Items)
+		return exp.NewColumnListExpression(toAny(es)...), err
+	case "bool":
+		return d.booleanExpression(je)
+	case "bitwise":
+		return d.bitwiseExpression(je)
+	case "range":
+		return d.rangeExpression(je)
+	case "func":
+		if !functionNameRegexp.MatchString(je.Name) && !d.opts.AllowLiterals {
+			return nil, errJSONLiteralNotAllowed(je.Name)
+		}
+		args, err := d.values(je.Items)
+		return exp.NewSQLFunctionExpression(je.Name, args...), err
+	case "window_func":
+		return d.windowFunctionExpression(je)
+	case "window":
+		return d.windowExpression(je)
+	case "order":
+		return d.orderedExpression(je)
+	case "table_hint":
+		return d.tableHintExpression(je)
+	case "alias":
+		e, err := d.value(je.Expr)
+		if err != nil {
+			return nil, err
+		}
+		as, err := d.expression(je.As)
+		if err != nil {
+			return nil, err
+		}
+		if ds, ok := e.(*SelectDataset); ok {
+			return exp.NewAliasExpression(ds, as), nil
+		}
+		expr, ok := e.(exp.Expression)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "expr")
+		}
+		return exp.NewAliasExpression(expr, as), nil
+	case "cast":
+		if !castTypeRegexp.MatchString(je.Name) && !d.opts.AllowLiterals {
+			return nil, errJSONLiteralNotAllowed(je.Name)
+		}
+		e, err := d.expression(je.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewCastExpression(e, je.Name), nil
+	case "case":
+		return d.caseExpression(je)
+	case "array":
+		vals, err := d.values(je.Items)
+		return exp.NewArrayExpression(vals), err
+	case "cte":
+		return d.commonTableExpression(je)
+	case "lateral":
+		e, err := d.expression(je.Expr)
+		if err != nil {
+			return nil, err
+		}
+		table, ok := e.(exp.AppendableExpression)
+		if !ok {
+			return nil, errInvalidJSONExpression(je.Type, "expr")
+		}
+		return exp.NewLateralExpression(table), nil
+	case "values":
+		return d.valuesList(je)
+	case "table_func":
+		return d.tableFunction(je)
+	}
+	return nil, errInvalidJSONExpression(je.Type, "type")
+}
+
+func (d *jsonDecoder) tableFunction(je *jsonExpression) (exp.Expression, error) {
+	if !functionNameRegexp.MatchString(je.Name) && !d.opts.AllowLiterals {
+		return nil, errJSONLiteralNotAllowed(je.Name)
+	}
+	args, err := d.values(je.Items)
+	if err != nil {
+		return nil, err
+	}
+	tf := exp.NewTableFunctionExpression(je.Name, args...)
+	if je.As != nil {
+		alias, err := d.identifier(je.As, "as")
+		if err != nil {
+			return nil, err
+		}
+		tf = tf.As(alias.GetTable())
+	}
+	for _, col := range je.Columns {
+		if col == nil || col.Name == "" {
+			return nil, errInvalidJSONExpression(je.Type, "columns")
+		}
+		if err := d.identifierNames("columns", col.Name); err != nil {
+			return nil, err
+		}
+		if col.Type != "" && !castTypeRegexp.MatchString(col.Type) && !d.opts.AllowLiterals {
+			return nil, errJSONLiteralNotAllowed(col.Type)
+		}
+		tf = tf.Column(col.Name, col.Type)
+	}
+	if je.Ordinality {
+		tf = tf.WithOrdinality()
+	}
+	return tf, nil
+}
+
+func (d *jsonDecoder) valuesList(je *jsonExpression) (exp.Expression, error) {
+	rows := make([][]any, 0, len(je.Rows))
+	for _, jr := range je.Rows {
+		row, err := d.values(jr)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	vl := exp.NewValuesListExpression(rows)
+	if je.As != nil {
+		alias, err := d.identifier(je.As, "as")
+		if err != nil {
+			return nil, err
+		}
+		cols, err := d.columnNames(je.Items, "items")
+		if err != nil {
+			return nil, err
+		}
+		vl = vl.As(alias.GetTable(), cols...)
+	}
+	return vl, nil
+}
+
+// decodes a literal, only the literals created by Star, Default and V are allowed unless literals are allowed
+func (d *jsonDecoder) literal(je *jsonExpression) (exp.LiteralExpression, error) {
+	args, err := d.values(je.Items)
+	if err != nil {
+		return nil, err
+	}
+	safe := (je.SQL == "*" || je.SQL == "DEFAULT") && len(args) == 0 || je.SQL == "?" && len(args) == 1
+	if !safe && !d.opts.AllowLiterals {
+		return nil, errJSONLiteralNotAllowed(je.SQL)
+	}
+	return exp.NewLiteralExpression(je.SQL, args...), nil
+}
+
+func (d *jsonDecoder) booleanExpression(je *jsonExpression) (exp.Expression, error) {
+	var op exp.BooleanOperation
+	if !lookupName(booleanOperationNames, je.Op, &op) {
+		return nil, errInvalidJSONExpression(je.Op, "op")
+	}
+	lhs, err := d.expression(je.LHS)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := d.value(je.RHS)
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewBooleanExpression(op, lhs, rhs), nil
+}
+
+func (d *jsonDecoder) bitwiseExpression(je *jsonExpression) (exp.Expression, error) {
+	var op exp.BitwiseOperation
+	if !lookupName(bitwiseOperationNames, je.Op, &op) {
+		return nil, errInvalidJSONExpression(je.Op, "op")
+	}
+	var lhs exp.Expression
+	if je.LHS != nil {
+		var err error
+		if lhs, err = d.expression(je.LHS); err != nil {
+			return nil, err
+		}
+	}
+	rhs, err := d.value(je.RHS)
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewBitwiseExpression(op, lhs, rhs), nil
+}
+
+func (d *jsonDecoder) rangeExpression(je *jsonExpression) (exp.Expression, error) {
+	var op exp.RangeOperation
+	if !lookupName(rangeOperationNames, je.Op, &op) {
+		return nil, errInvalidJSONExpression(je.Op, "op")
+	}
+	lhs, err := d.expression(je.LHS)
+	if err != nil {
+		return nil, err
+	}
+	start, err := d.value(je.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := d.value(je.End)
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewRangeExpression(op, lhs, exp.NewRangeVal(start, end)), nil
+}
+
+func (d *jsonDecoder) windowFunctionExpression(je *jsonExpression) (exp.Expression, error) {
+	e, err := d.expression(je.Expr)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := e.(exp.SQLFunctionExpression)
+	if !ok {
+		return nil, errInvalidJSONExpression(je.Type, "expr")
+	}
+	var name exp.IdentifierExpression
+	if je.WindowName != nil {
+		if name, err = d.identifier(je.WindowName, "window_name"); err != nil {
+			return nil, err
+		}
+	}
+	var window exp.WindowExpression
+	if je.Window != nil {
+		w, err := d.windowExpression(je.Window)
+		if err != nil {
+			return nil, err
+		}
+		window = w.(exp.WindowExpression)
+	}
+	return exp.NewSQLWindowFunctionExpression(fn, name, window), nil
+}
+
+func (d *jsonDecoder) windowExpression(je *jsonExpression) (exp.Expression, error) {
+	if je.Type != "window" {
+		return nil, errInvalidJSONExpression(je.Type, "window")
+	}
+	var name, parent exp.IdentifierExpression
+	var err error
+	if je.WindowName != nil {
+		if name, err = d.identifier(je.WindowName, "window_name"); err != nil {
+			return nil, err
+		}
+	}
+	if je.Parent != nil {
+		if parent, err = d.identifier(je.Parent, "parent"); err != nil {
+			return nil, err
+		}
+	}
+	partition, err := d.expressions(je.Partition)
+	if err != nil {
+		return nil, err
+	}
+	order, err := d.expressions(je.Order)
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewWindowExpression(
+		name,
+		parent,
+		exp.NewColumnListExpression(toAny(partition)...),
+		exp.NewColumnListExpression(toAny(order)...),
+	), nil
+}
+
+func (d *jsonDecoder) orderedExpression(je *jsonExpression) (exp.Expression, error) {
+	e, err := d.expression(je.Expr)
+	if err != nil {
+		return nil, err
+	}
+	direction := exp.AscDir
+	if je.Desc {
+		direction = exp.DescSortDir
+	}
+	nulls := exp.NoNullsSortType
+	switch je.Nulls {
+	case "":
+	case "first":
+		nulls = exp.NullsFirstSortType
+	case "last":
+		nulls = exp.NullsLastSortType
+	default:
+		return nil, errInvalidJSONExpression(je.Nulls, "nulls")
+	}
+	return exp.NewOrderedExpression(e, direction, nulls), nil
+}
+
+func (d *jsonDecoder) commonTableExpression(je *jsonExpression) (exp.Expression, error) {
+	e, err := d.expression(je.Expr)
+	if err != nil {
+		return nil, err
+	}
+	var cte exp.CommonTableExpression
+	if je.As != nil {
+		name, err := d.identifier(je.As, "as")
+		if err != nil {
+			return nil, err
+		}
+		cte = exp.NewNamedCommonTableExpression(name, e)
+	} else {
+		if !cteNameRegexp.MatchString(je.Name) && !d.opts.AllowLiterals {
+			return nil, errJSONLiteralNotAllowed(je.Name)
+		}
+		cte = exp.NewCommonTableExpression(false, je.Name, e)
+	}
+	if je.Recursive {
+		cte = cte.Recursive()
+	}
+	if len(je.Items) > 0 {
+		names, err := d.columnNames(je.Items, "items")
+		if err != nil {
+			return nil, err
+		}
+		cte = cte.Columns(names...)
+	}
+	var materialization exp.CTEMaterialization
+	if !lookupName(cteMaterializationNames, je.Materialized, &materialization) {
+		return nil, errInvalidJSONExpression(je.Materialized, "materialized")
+	}
+	switch materialization {
+	case exp.MaterializedCTE:
+		cte = cte.Materialized()
+	case exp.NotMaterializedCTE:
+		cte = cte.NotMaterialized()
+	}
+	if je.Search != nil {
+		var searchType exp.CTESearchType
+		if !lookupName(cteSearchTypeNames, je.Search.Type, &searchType) {
+			return nil, errInvalidJSONExpression(je.Search.Type, "search")
+		}
+		by, err := d.expressions(je.Search.By)
+		if err != nil {
+			return nil, err
+		}
+		set, err := d.identifier(je.Search.Set, "set")
+		if err != nil {
+			return nil, err
+		}
+		cte = cte.Search(exp.NewCTESearch(searchType, exp.NewColumnListExpression(toAny(by)...), set))
+	}
+	if je.Cycle != nil {
+		cycle, err := d.cteCycle(je.Cycle)
+		if err != nil {
+			return nil, err
+		}
+		cte = cte.Cycle(cycle)
+	}
+	return cte, nil
+}
+
+func (d *jsonDecoder) cteCycle(jc *jsonCTECycle) (exp.CTECycle, error) {
+	cols, err := d.expressions(jc.Cols)
+	if err != nil {
+		return nil, err
+	}
+	set, err := d.identifier(jc.Set, "set")
+	if err != nil {
+		return nil, err
+	}
+	using, err := d.identifier(jc.Using, "using")
+	if err != nil {
+		return nil, err
+	}
+	cycle := exp.NewCTECycle(exp.NewColumnListExpression(toAny(cols)...), set, using)
+	if jc.To != nil {
+		to, err := d.value(jc.To)
+		if err != nil {
+			return nil, err
+		}
+		def, err := d.value(jc.Default)
+		if err != nil {
+			return nil, err
+		}
+		cycle = cycle.Mark(to, def)
+	}
+	return cycle, nil
+}
+
+func (d *jsonDecoder) tableHintExpression(je *jsonExpression) (exp.Expression, error) {
+	table, err := d.expression(je.Expr)
+	if err != nil {
+		return nil, err
+	}
+	hints := make([]exp.TableHint, 0, len(je.Hints))
+	for _, h := range je.Hints {
+		var t exp.TableHintType
+		if !lookupName(tableHintTypeNames, h.Type, &t) {
+			return nil, errInvalidJSONExpression(h.Type, "hints")
+		}
+		if t == exp.WithTableHint && !d.opts.AllowLiterals {
+			return nil, errJSONLiteralNotAllowed(h.Type)
+		}
+		hints = append(hints, exp.NewTableHint(t, h.Values...))
+	}
+	return exp.NewTableHintExpression(table, hints...), nil
+}
+
+func (d *jsonDecoder) caseExpression(je *jsonExpression) (exp.Expression, error) {
+	c := exp.NewCaseExpression()
+	if je.Expr != nil {
+		v, err := d.value(je.Expr)
+		if err != nil {
+			return nil, err
+		}
+		c = c.Value(v)
+	}
+	for _, w := range je.Whens {
+		cond, err := d.value(w.When)
+		if err != nil {
+			return nil, err
+		}
+		result, err := d.value(w.Then)
+		if err != nil {
+			return nil, err
+		}
+		c = c.When(cond, result)
+	}
+	if je.Else != nil {
+		result, err := d.value(je.Else)
+		if err != nil {
+			return nil, err
+		}
+		c = c.Else(result)
+	}
+	return c, nil
+}
+
+// converts a decoded JSON value to an int64, float64, string or bool
+func jsonPrimitive(v any) (any, error) {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	case string, bool:
+		return t, nil
+	}
+	return nil, errInvalidJSONExpression("value", "value")
+}
+
+// sets the key of the names map with the name into dest, returns false if the name is unknown
+func lookupName(names any, name string, dest any) bool {
+	iter := reflect.ValueOf(names).MapRange()
+	for iter.Next() {
+		if iter.Value().String() == name {
+			reflect.ValueOf(dest).Elem().Set(iter.Key())
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(je *jsonExpression) string {
+	if je == nil {
+		return ""
+	}
+	return je.Type
+}
+
+func toAny(es []exp.Expression) []any {
+	vals := make([]any, 0, len(es))
+	for _, e := range es {
+		vals = append(vals, e)
+	}
+	return vals
+}
@@ -0,0 +1,305 @@
+package builder_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Tooooommy/builder/v9"
+	_ "github.com/Tooooommy/builder/v9/dialect/mysql"
+	_ "github.com/Tooooommy/builder/v9/dialect/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type datasetJSONSuite struct {
+	suite.Suite
+}
+
+func (djs *datasetJSONSuite) assertSelectRoundTrip(ds *builder.SelectDataset) *builder.SelectDataset {
+	data, err := json.Marshal(ds)
+	djs.Require().NoError(err)
+	decoded, err := builder.UnmarshalSelectDataset(data)
+	djs.Require().NoError(err)
+	djs.Equal(ds.Dialect(), decoded.Dialect())
+	for _, prepared := range []bool{false, true} {
+		expectedSQL, expectedArgs, err := ds.Prepared(prepared).ToSQL()
+		djs.Require().NoError(err)
+		sql, args, err := decoded.Prepared(prepared).ToSQL()
+		djs.Require().NoError(err)
+		djs.Equal(expectedSQL, sql)
+		djs.Equal(expectedArgs, args)
+	}
+	return decoded
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset() {
+	djs.assertSelectRoundTrip(builder.From("items"))
+	djs.assertSelectRoundTrip(builder.Dialect("mysql").From(builder.S("public").Table("items").As("i")).
+		Select("id", builder.I("i.name"), builder.COUNT("*").As("count"), builder.Star()).
+		Where(
+			builder.Ex{"name": "Bob", "age": builder.Op{"gt": 10}, "deleted_at": nil},
+			builder.ExOr{"a": []int{1, 2}, "b": builder.Op{"neq": "c"}},
+			builder.C("id").In(1, 2, 3),
+			builder.C("price").Between(builder.Range(1.5, 10)),
+			builder.C("flags").BitwiseAnd(4).Eq(4),
+			builder.Or(builder.C("active").IsTrue(), builder.C("created").Gt(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))),
+			builder.C("name").Like("B%"),
+		).
+		GroupBy("name").
+		Having(builder.SUM("price").Gt(100)).
+		Order(builder.C("name").Desc().NullsLast(), builder.C("id").Asc()).
+		Limit(10).
+		Offset(20))
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_joinsAndSubQueries() {
+	// sub queries are decoded with the dialect of the dataset
+	pg := builder.Dialect("postgres")
+	sub := pg.From("orders").Select("user_id").Where(builder.C("total").Gt(10))
+	djs.assertSelectRoundTrip(pg.From("users").
+		With("recent(id)", pg.From("users").Select("id").Where(builder.C("created").Gte(builder.L("?", 1)))).
+		Join(builder.T("accounts"), builder.On(builder.I("users.id").Eq(builder.I("accounts.user_id")))).
+		LeftJoin(builder.T("profiles"), builder.Using("user_id")).
+		CrossJoin(builder.T("tags")).
+		Where(builder.C("id").In(sub)).
+		Union(pg.From("admins")).
+		ForUpdate(builder.SkipLocked))
+	djs.assertSelectRoundTrip(builder.From(builder.From("users").As("u")).Select(
+		builder.Case().When(builder.C("age").Gt(18), "adult").Else("minor").As("kind"),
+		builder.Cast(builder.C("age"), "VARCHAR(10)"),
+		builder.DISTINCT("name"),
+	).Distinct())
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_commonTables() {
+	pg := builder.Dialect("postgres")
+	tree := pg.From("node").Select("id").UnionAll(pg.From("node").Select("parent_id"))
+	djs.assertSelectRoundTrip(pg.From("tree").WithCTE(
+		builder.CTE("tree", tree).Columns("id").
+			Search(builder.SearchBreadthFirst("ordercol", "id")).
+			Cycle(builder.Cycle("is_cycle", "path", "id").Mark("Y", "N")),
+		builder.CTE("active", pg.From("user")).Materialized(),
+	).With("legacy(id)", pg.From("user").Select("id")))
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_windows() {
+	djs.assertSelectRoundTrip(builder.Dialect("postgres").From("items").
+		Select(builder.ROW_NUMBER().Over(builder.W().PartitionBy("category").OrderBy(builder.C("price").Desc())),
+			builder.RANK().OverName(builder.I("w"))).
+		Window(builder.W("w").PartitionBy("category")))
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_arrays() {
+	ds := builder.From("items").Where(
+		builder.C("tags").Contains(builder.Array([]string{"a", "b"})),
+		builder.Ex{"id": builder.Op{"eqAny": []int{1, 2}}},
+	)
+	data, err := json.Marshal(ds)
+	djs.Require().NoError(err)
+	decoded, err := builder.UnmarshalSelectDataset(data)
+	djs.Require().NoError(err)
+
+	sql, _, err := decoded.ToSQL()
+	djs.NoError(err)
+	djs.Equal(`SELECT * FROM "items" WHERE (("tags" @> ARRAY['a', 'b']) AND ("id" = ANY(ARRAY[1, 2])))`, sql)
+
+	// decoded arrays hold the decoded elements, their parameter values are the same
+	_, args, err := decoded.Prepared(true).ToSQL()
+	djs.NoError(err)
+	djs.Len(args, 2)
+	for i, expected := range []string{`{"a","b"}`, "{1,2}"} {
+		v, err := args[i].(driver.Valuer).Value()
+		djs.NoError(err)
+		djs.Equal(expected, v)
+	}
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_values() {
+	pg := builder.Dialect("postgres")
+	values := builder.Values([][]any{{1, "a"}, {2, nil}}).As("v", "id", "name")
+	djs.assertSelectRoundTrip(pg.From(values).Select("id", "name"))
+	djs.assertSelectRoundTrip(pg.From("items").
+		Join(
+			builder.Values([][]any{{1, true}}).As("f", "item_id", "flag"),
+			builder.On(builder.I("f.item_id").Eq(builder.I("items.id"))),
+		).
+		WithCTE(builder.CTE("ids", builder.Values([][]any{{1}, {2}})).Columns("id")))
+	decoded := djs.assertSelectRoundTrip(builder.Dialect("mysql8").From(values))
+	sql, _, err := decoded.ToSQL()
+	djs.NoError(err)
+	djs.Equal("SELECT * FROM (VALUES ROW(1, 'a'), ROW(2, NULL)) AS `v`(`id`, `name`)", sql)
+
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"from":[
+		{"type":"values","rows":[[{"type":"value","value":1}]],"as":{"type":"ident","table":"v"},
+		 "items":[{"type":"literal","sql":"*"}]}
+	]}}`))
+	djs.EqualError(err, `builder: invalid JSON expression "literal" [items]`)
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_tableFunctions() {
+	pg := builder.Dialect("postgres")
+	djs.assertSelectRoundTrip(pg.From(builder.TableFunc("generate_series", 1, 3).As("s", "n")).Select("n"))
+	djs.assertSelectRoundTrip(pg.From("items").CrossJoin(
+		builder.Lateral(builder.Unnest(builder.I("items.tags")).WithOrdinality().As("t", "tag", "n")),
+	))
+	djs.assertSelectRoundTrip(pg.From(
+		builder.TableFunc("jsonb_to_recordset", `[{"a":1}]`).As("r").Column("a", "int").Column("b", "varchar(10)"),
+	))
+
+	_, err := builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"from":[
+		{"type":"table_func","name":"generate_series(1, 3); DROP TABLE users; --"}
+	]}}`))
+	djs.EqualError(err, "builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals "+
+		"[sql=generate_series(1, 3); DROP TABLE users; --]")
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"from":[
+		{"type":"table_func","name":"jsonb_to_recordset","as":{"type":"ident","table":"r"},
+		 "columns":[{"name":"a","type":"int) AS x; --"}]}
+	]}}`))
+	djs.EqualError(err, "builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=int) AS x; --]")
+}
+
+func (djs *datasetJSONSuite) TestSelectDataset_format() {
+	data, err := json.Marshal(builder.From("items").Where(builder.C("id").Eq(1)))
+	djs.NoError(err)
+	djs.JSONEq(`{
+		"version": 1,
+		"type": "select",
+		"dialect": "default",
+		"select": {
+			"from": [{"type": "ident", "col": "items"}],
+			"where": [{"type": "bool", "op": "eq", "lhs": {"type": "ident", "col": "id"}, "rhs": {"type": "value", "value": 1}}]
+		}
+	}`, string(data))
+}
+
+func (djs *datasetJSONSuite) TestUpdateDataset() {
+	ds := builder.Dialect("postgres").Update("items").
+		Set(builder.Record{"name": "Bob", "price": builder.L("?", 10), "updated": nil}).
+		Where(builder.C("id").Eq(1)).
+		Returning("id")
+	data, err := json.Marshal(ds)
+	djs.NoError(err)
+	decoded, err := builder.UnmarshalUpdateDataset(data)
+	djs.NoError(err)
+	expectedSQL, _, err := ds.ToSQL()
+	djs.NoError(err)
+	sql, _, err := decoded.ToSQL()
+	djs.NoError(err)
+	djs.Equal(expectedSQL, sql)
+}
+
+func (djs *datasetJSONSuite) TestDeleteDataset() {
+	ds := builder.Dialect("mysql").Delete("items").Where(builder.C("id").Eq(1)).Order(builder.C("id").Asc()).Limit(10)
+	data, err := json.Marshal(ds)
+	djs.NoError(err)
+	decoded, err := builder.UnmarshalDeleteDataset(data)
+	djs.NoError(err)
+	expectedSQL, _, err := ds.ToSQL()
+	djs.NoError(err)
+	sql, _, err := decoded.ToSQL()
+	djs.NoError(err)
+	djs.Equal(expectedSQL, sql)
+
+	_, err = builder.UnmarshalSelectDataset(data)
+	djs.EqualError(err, `builder: expected a JSON select dataset got "delete"`)
+}
+
+func (djs *datasetJSONSuite) TestLiterals() {
+	ds := builder.From("items").Where(builder.L("id = 1")).Select(builder.Default())
+	data, err := json.Marshal(ds)
+	djs.NoError(err)
+
+	_, err = builder.UnmarshalSelectDataset(data)
+	djs.EqualError(err, "builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=id = 1]")
+
+	decoded, err := builder.UnmarshalSelectDataset(data, builder.JSONOptions{AllowLiterals: true})
+	djs.NoError(err)
+	sql, _, err := decoded.ToSQL()
+	djs.NoError(err)
+	djs.Equal(`SELECT DEFAULT FROM "items" WHERE id = 1`, sql)
+}
+
+func (djs *datasetJSONSuite) TestRawSQLRejected() {
+	_, err := builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"select":[
+		{"type":"func","name":"COUNT(*); DROP TABLE users; --"}
+	]}}`))
+	djs.EqualError(err,
+		"builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=COUNT(*); DROP TABLE users; --]",
+	)
+
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"select":[
+		{"type":"cast","name":"INT) FROM users; --","expr":{"type":"ident","col":"id"}}
+	]}}`))
+	djs.EqualError(err,
+		"builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=INT) FROM users; --]",
+	)
+
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"optimizer_hints":["x"]}}`))
+	djs.EqualError(err, "builder: raw SQL is not allowed in JSON datasets, see JSONOptions.AllowLiterals [sql=x]")
+}
+
+func (djs *datasetJSONSuite) TestQuotedIdentifiersRejected() {
+	const injected = `x\" WHERE 1=1; DROP TABLE x; --`
+	selects := []string{
+		`"select":[{"type":"ident","col":"` + injected + `"}]`,
+		`"select":[{"type":"ident","schema":"` + injected + `","table":"t","col":"id"}]`,
+		`"select":[{"type":"alias","expr":{"type":"ident","col":"a"},"as":{"type":"ident","col":"` + injected + `"}}]`,
+		`"from":[{"type":"ident","table":"` + injected + `"}]`,
+		`"from":[{"type":"alias","expr":{"type":"ident","table":"t"},"as":{"type":"ident","table":"` + injected + `"}}]`,
+		`"where":[{"type":"ex","map":{"` + injected + `":{"type":"value","value":1}}}]`,
+		`"from":[{"type":"table_func","name":"f","columns":[{"name":"` + injected + `","type":"int"}]}]`,
+	}
+	for _, sel := range selects {
+		_, err := builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{` + sel + `}}`))
+		djs.Error(err, sel)
+		djs.Contains(err.Error(), "identifiers in JSON datasets can not contain quote characters", sel)
+	}
+
+	for _, name := range []string{"a`b", "a[b", "a]b"} {
+		_, err := builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{
+			"from":[{"type":"ident","table":"` + name + `"}]
+		}}`))
+		djs.EqualError(err,
+			fmt.Sprintf("builder: identifiers in JSON datasets can not contain quote characters got %q [ident]", name),
+		)
+	}
+
+	_, err := builder.UnmarshalUpdateDataset([]byte(`{"version":1,"type":"update","update":{
+		"table":{"type":"ident","col":"items"},"set":[{"col":"` + injected + `","value":{"type":"value","value":1}}]
+	}}`))
+	djs.EqualError(err, `builder: identifiers in JSON datasets can not contain quote characters got "x\" WHERE 1=1; `+
+		`DROP TABLE x; --" [set]`)
+
+	_, err = builder.UnmarshalUpdateDataset([]byte(`{"version":1,"type":"update","update":{
+		"table":{"type":"ident","col":"` + injected + `"},"set":[{"col":"a","value":{"type":"value","value":1}}]
+	}}`))
+	djs.ErrorContains(err, "identifiers in JSON datasets can not contain quote characters")
+
+	_, err = builder.UnmarshalDeleteDataset([]byte(`{"version":1,"type":"delete","delete":{
+		"from":{"type":"ident","col":"` + injected + `"}
+	}}`))
+	djs.EqualError(err, `builder: identifiers in JSON datasets can not contain quote characters got "x\" WHERE 1=1; `+
+		`DROP TABLE x; --" [from]`)
+}
+
+func (djs *datasetJSONSuite) TestInvalid() {
+	_, err := builder.UnmarshalSelectDataset([]byte(`{"version":2,"type":"select","select":{}}`))
+	djs.EqualError(err, "builder: unsupported JSON dataset version 2")
+
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"where":[
+		{"type":"bool","op":"approx","lhs":{"type":"ident","col":"id"},"rhs":{"type":"value","value":1}}
+	]}}`))
+	djs.EqualError(err, `builder: invalid JSON expression "approx" [op]`)
+
+	_, err = builder.UnmarshalSelectDataset([]byte(`{"version":1,"type":"select","select":{"where":[{"type":"unknown"}]}}`))
+	djs.EqualError(err, `builder: invalid JSON expression "unknown" [type]`)
+
+	_, err = json.Marshal(builder.From("items").Where(builder.C("id").Eq(struct{}{})))
+	djs.EqualError(err, "json: error calling MarshalJSON for type *builder.SelectDataset: builder: unable to encode struct {} to JSON")
+}
+
+func TestDatasetJSONSuite(t *testing.T) {
+	suite.Run(t, new(datasetJSONSuite))
+}
@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
@@ -20,21 +21,23 @@ type DeleteDataset struct {
 	isPrepared prepared
 	executor   sqlx.Session
 	err        error
+	// set to true to execute the statement without a WHERE or LIMIT clause, see AllowFullTable
+	allowFullTable bool
 }
 
 // used internally by database to create a database with a specific adapter
-func newDeleteDataset(d string, executor sqlx.Session) *DeleteDataset {
+func newDeleteDataset(d string, executor sqlx.Session, s *settings) *DeleteDataset {
 	return &DeleteDataset{
 		clauses:    exp.NewDeleteClauses(),
-		dialect:    GetDialect(d),
+		dialect:    s.dialect(d),
 		executor:   executor,
-		isPrepared: preparedNoPreference,
+		isPrepared: s.isPrepared(),
 		err:        nil,
 	}
 }
 
 func Delete(table any) *DeleteDataset {
-	return newDeleteDataset("default", nil).From(table)
+	return newDeleteDataset("default", nil, nil).From(table)
 }
 
 func (dd *DeleteDataset) Expression() exp.Expression {
@@ -63,7 +66,7 @@ func (dd *DeleteDataset) IsPrepared() bool {
 // Sets the adapter used to serialize values and create the SQL statement
 func (dd *DeleteDataset) WithDialect(dl string) *DeleteDataset {
 	ds := dd.copy(dd.GetClauses())
-	ds.dialect = GetDialect(dl)
+	ds.dialect = inheritSettings(GetDialect(dl), ds.dialect)
 	return ds
 }
 
@@ -84,17 +87,43 @@ func (dd *DeleteDataset) GetClauses() exp.DeleteClauses {
 	return dd.clauses
 }
 
+// Sets a timeout for executing the statement of this dataset. The context passed to ExecCtx etc. times out after
+// timeout, where supported by the dialect the timeout is also enforced by the database (e.g. SET LOCAL
+// statement_timeout inside of postgres transactions).
+//
+// A timeout less than or equal to zero removes the timeout.
+func (dd *DeleteDataset) WithTimeout(timeout time.Duration) *DeleteDataset {
+	return dd.copy(dd.clauses.SetTimeout(timeout))
+}
+
+// Adds optimizer hints to the statement, the hints are rendered in a single comment after the DELETE keyword. Calling
+// OptimizerHints multiple times appends the hints.
+//
+//	builder.Delete("test").OptimizerHints("NO_ICP(test)") -> DELETE /*+ NO_ICP(test) */ ...
+func (dd *DeleteDataset) OptimizerHints(hints ...string) *DeleteDataset {
+	return dd.copy(dd.clauses.OptimizerHintsAppend(hints...))
+}
+
 // used interally to copy the dataset
 func (dd *DeleteDataset) copy(clauses exp.DeleteClauses) *DeleteDataset {
 	return &DeleteDataset{
-		dialect:    dd.dialect,
-		clauses:    clauses,
-		isPrepared: dd.isPrepared,
-		executor:   dd.executor,
-		err:        dd.err,
+		dialect:        dd.dialect,
+		clauses:        clauses,
+		isPrepared:     dd.isPrepared,
+		executor:       dd.executor,
+		err:            dd.err,
+		allowFullTable: dd.allowFullTable,
 	}
 }
 
+// Allows the statement to be executed without a WHERE or LIMIT clause, deleting every row of the table. See
+// Database#AllowFullTable
+func (dd *DeleteDataset) AllowFullTable() *DeleteDataset {
+	ret := dd.copy(dd.clauses)
+	ret.allowFullTable = true
+	return ret
+}
+
 // Creates a WITH clause for a common table expression (CTE).
 //
 // The name will be available to SELECT from in the associated query; and can optionally
@@ -117,6 +146,18 @@ func (dd *DeleteDataset) WithRecursive(name string, subquery exp.Expression) *De
 	return dd.copy(dd.clauses.CommonTablesAppend(exp.NewCommonTableExpression(true, name, subquery)))
 }
 
+// Adds common table expressions (CTE) created with CTE to the WITH clause. Unlike With the column names,
+// materialization and the SEARCH and CYCLE clauses of the CTEs are typed.
+//
+//	WithCTE(builder.CTE("tree", sub).Columns("id", "parent_id").NotMaterialized())
+func (dd *DeleteDataset) WithCTE(ctes ...exp.CommonTableExpression) *DeleteDataset {
+	clauses := dd.clauses
+	for _, cte := range ctes {
+		clauses = clauses.CommonTablesAppend(cte)
+	}
+	return dd.copy(clauses)
+}
+
 // Adds a FROM clause. This return a new dataset with the original sources replaced. See examples.
 // You can pass in the following.
 //
@@ -186,7 +227,8 @@ func (dd *DeleteDataset) ClearLimit() *DeleteDataset {
 
 // Adds a RETURNING clause to the dataset if the adapter supports it.
 func (dd *DeleteDataset) Returning(returning ...any) *DeleteDataset {
-	return dd.copy(dd.clauses.SetReturning(exp.NewColumnListExpression(returning...)))
+	cols := exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(dd.dialect), returning...)
+	return dd.copy(dd.clauses.SetReturning(cols))
 }
 
 // Get any error that has been set or nil if no error has been set.
@@ -242,14 +284,11 @@ func (dd *DeleteDataset) Exec() (sql.Result, error) {
 }
 
 func (dd *DeleteDataset) ExecCtx(ctx context.Context) (sql.Result, error) {
-	if dd.executor == nil {
-		return nil, ErrExecutorNotFoundError
-	}
-	query, args, err := dd.deleteSQLBuilder().ToSQL()
+	query, args, err := dd.buildSQL()
 	if err != nil {
 		return nil, err
 	}
-	return dd.executor.ExecCtx(ctx, query, args...)
+	return dd.session(ctx).ExecCtx(ctx, query, args...)
 }
 
 func (dd *DeleteDataset) QueryRow(v any) error {
@@ -261,7 +300,7 @@ func (dd *DeleteDataset) QueryRowCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return dd.executor.QueryRowCtx(ctx, v, query, args...)
+	return dd.session(ctx).QueryRowCtx(ctx, v, query, args...)
 }
 
 func (dd *DeleteDataset) QueryRowPartial(v any) error {
@@ -273,7 +312,7 @@ func (dd *DeleteDataset) QueryRowPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return dd.executor.QueryRowPartialCtx(ctx, v, query, args...)
+	return dd.session(ctx).QueryRowPartialCtx(ctx, v, query, args...)
 }
 
 func (dd *DeleteDataset) QueryRows(v any) error {
@@ -285,7 +324,7 @@ func (dd *DeleteDataset) QueryRowsCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return dd.executor.QueryRowsCtx(ctx, v, query, args...)
+	return dd.session(ctx).QueryRowsCtx(ctx, v, query, args...)
 }
 
 func (dd *DeleteDataset) QueryRowsPartial(v any) error {
@@ -297,16 +336,36 @@ func (dd *DeleteDataset) QueryRowsPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return dd.executor.QueryRowsPartialCtx(ctx, v, query, args...)
+	return dd.session(ctx).QueryRowsPartialCtx(ctx, v, query, args...)
 }
 
 func (dd *DeleteDataset) buildSQL() (string, []any, error) {
 	if dd.executor == nil {
 		return "", nil, ErrExecutorNotFoundError
 	}
+	hasLimit := dd.clauses.HasLimit() && rendersLimit(dd.dialect, func(opts *SQLDialectOptions) bool {
+		return opts.SupportsLimitOnDelete
+	})
+	if !dd.allowFullTable && refusesFullTable(dd.executor) && isFullTable(dd.clauses.Where(), hasLimit) {
+		return "", nil, ErrFullTableNotAllowed
+	}
 	return dd.deleteSQLBuilder().ToSQL()
 }
 
+// Generates the fingerprint of the DELETE statement, the values of the dataset are replaced with placeholders so
+// datasets with the same shape have the same fingerprint. See Fingerprint.
+//
+// Errors:
+//   - There is an error generating the SQL
+func (dd *DeleteDataset) Fingerprint() (Fingerprint, error) {
+	if dd.err != nil {
+		return Fingerprint{}, dd.err
+	}
+	return fingerprint(func(b sb.SQLBuilder) {
+		dd.dialect.ToDeleteSQL(b, dd.clauses)
+	})
+}
+
 func (dd *DeleteDataset) deleteSQLBuilder() sb.SQLBuilder {
 	buf := sb.NewSQLBuilder(dd.isPrepared.Bool())
 	if dd.err != nil {
@@ -315,3 +374,9 @@ func (dd *DeleteDataset) deleteSQLBuilder() sb.SQLBuilder {
 	dd.dialect.ToDeleteSQL(buf, dd.clauses)
 	return buf
 }
+
+// returns the session to execute statements with, see datasetSession
+func (dd *DeleteDataset) session(ctx context.Context) sqlx.Session {
+	stmt := newStatement("DELETE", dd.clauses.From())
+	return datasetSession(ctx, dd.dialect, dd.executor, stmt, dd.clauses.Timeout())
+}
@@ -2,6 +2,7 @@ package builder_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Tooooommy/builder/v9"
@@ -105,6 +106,25 @@ func (dds *deleteDatasetSuite) TestGetClauses() {
 	dds.Equal(ce, ds.GetClauses())
 }
 
+func (dds *deleteDatasetSuite) TestWithTimeout() {
+	ds := builder.Delete("test")
+	ce := exp.NewDeleteClauses().SetFrom(builder.I("test"))
+	dds.Equal(ce.SetTimeout(time.Second), ds.WithTimeout(time.Second).GetClauses())
+	dds.Equal(ce, ds.WithTimeout(time.Second).WithTimeout(0).GetClauses())
+	dds.Equal(ce, ds.GetClauses())
+}
+
+func (dds *deleteDatasetSuite) TestOptimizerHints() {
+	ds := builder.Delete("test")
+	ce := exp.NewDeleteClauses().SetFrom(builder.I("test"))
+	dds.Equal(ce.OptimizerHintsAppend("NO_ICP(test)"), ds.OptimizerHints("NO_ICP(test)").GetClauses())
+	dds.Equal(
+		ce.OptimizerHintsAppend("NO_ICP(test)", "BKA(test)"),
+		ds.OptimizerHints("NO_ICP(test)").OptimizerHints("BKA(test)").GetClauses(),
+	)
+	dds.Equal(ce, ds.GetClauses())
+}
+
 func (dds *deleteDatasetSuite) TestWith() {
 	from := builder.From("cte")
 	bd := builder.Delete("items")
@@ -137,6 +157,22 @@ func (dds *deleteDatasetSuite) TestWithRecursive() {
 	)
 }
 
+func (dds *deleteDatasetSuite) TestWithCTE() {
+	cte := builder.CTE("test-cte", builder.From("cte")).Columns("id")
+	bd := builder.Delete("items")
+	dds.assertCases(
+		deleteTestCase{
+			ds: bd.WithCTE(cte),
+			clauses: exp.NewDeleteClauses().SetFrom(builder.C("items")).
+				CommonTablesAppend(cte),
+		},
+		deleteTestCase{
+			ds:      bd,
+			clauses: exp.NewDeleteClauses().SetFrom(builder.C("items")),
+		},
+	)
+}
+
 func (dds *deleteDatasetSuite) TestFrom_withIdentifier() {
 	bd := builder.Delete("items")
 	dds.assertCases(
@@ -18,9 +18,18 @@ func DialectOptions() *builder.SQLDialectOptions {
 	opts.SupportsConflictTarget = false
 	opts.SupportsWithCTE = false
 	opts.SupportsWithCTERecursive = false
+	opts.SupportsCTEMaterialized = false
+	opts.SupportsCTESearchCycle = false
+	opts.UseUnionAllForValuesList = true
 	opts.SupportsDistinctOn = false
 	opts.SupportsWindowFunction = false
+	opts.SupportsTableFunctions = false
+	opts.SupportsWithOrdinality = false
+	opts.SupportsTableFunctionColumnDefinitions = false
+	opts.SupportsArrays = false
 	opts.SupportsDeleteTableHint = true
+	opts.SupportsIndexHints = true
+	opts.SupportsOptimizerHints = true
 
 	opts.UseFromClauseForMultipleUpdateTables = false
 
@@ -72,12 +81,30 @@ func DialectOptions() *builder.SQLDialectOptions {
 	opts.ConflictFragment = []byte("")
 	opts.ConflictDoUpdateFragment = []byte(" ON DUPLICATE KEY UPDATE ")
 	opts.ConflictDoNothingFragment = []byte("")
+	// SET TRANSACTION only affects the next transaction in MySQL and is not allowed inside an active transaction
+	opts.SetTransactionFragment = []byte("")
+	opts.DeferrableFragment = []byte("")
+	opts.ForShareFragment = []byte(" LOCK IN SHARE MODE ")
+	opts.ForNoKeyUpdateFragment = nil
+	opts.ForKeyShareFragment = nil
+	opts.OfFragment = nil
+	opts.NowaitFragment = nil
+	opts.SkipLockedFragment = nil
+	opts.MaxExecutionTimeHintFormat = "MAX_EXECUTION_TIME(%d)"
 	return opts
 }
 
 func DialectOptionsV8() *builder.SQLDialectOptions {
 	opts := DialectOptions()
 	opts.SupportsWindowFunction = true
+	opts.UseUnionAllForValuesList = false
+	opts.ValuesListRowFragment = []byte("ROW")
+	// JSON_TABLE
+	opts.SupportsTableFunctions = true
+	opts.ForShareFragment = []byte(" FOR SHARE ")
+	opts.OfFragment = []byte("OF ")
+	opts.NowaitFragment = []byte("NOWAIT")
+	opts.SkipLockedFragment = []byte("SKIP LOCKED")
 	return opts
 }
 
@@ -85,6 +112,14 @@ func DialectOptionsMariaDB() *builder.SQLDialectOptions {
 	opts := DialectOptions()
 	opts.SupportsWindowFunction = true
 	opts.SupportsReturn = true
+	// JSON_TABLE
+	opts.SupportsTableFunctions = true
+	opts.NowaitFragment = []byte("NOWAIT")
+	opts.SkipLockedFragment = []byte("SKIP LOCKED")
+	// MariaDB uses max_statement_time instead of the MAX_EXECUTION_TIME optimizer hint and does not support the
+	// /*+ ... */ optimizer hint syntax
+	opts.SupportsOptimizerHints = false
+	opts.MaxExecutionTimeHintFormat = ""
 	return opts
 }
 
@@ -3,6 +3,7 @@ package mysql_test
 import (
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9"
 	"github.com/Tooooommy/builder/v9/exp"
@@ -138,6 +139,124 @@ func (mds *mysqlDialectSuite) TestUpdateSQL() {
 	)
 }
 
+func (mds *mysqlDialectSuite) TestWithTimeout() {
+	ds := mds.GetDs("test").WithTimeout(500 * time.Millisecond)
+	mds.assertSQL(
+		sqlTestCase{ds: ds, sql: "SELECT /*+ MAX_EXECUTION_TIME(500) */ * FROM `test`"},
+		sqlTestCase{ds: ds.Distinct(), sql: "SELECT /*+ MAX_EXECUTION_TIME(500) */ DISTINCT * FROM `test`"},
+		sqlTestCase{ds: ds.WithTimeout(0), sql: "SELECT * FROM `test`"},
+		sqlTestCase{ds: ds.WithDialect("mysql8"), sql: "SELECT /*+ MAX_EXECUTION_TIME(500) */ * FROM `test`"},
+		sqlTestCase{ds: ds.WithDialect("mariadb"), sql: "SELECT * FROM `test`"},
+	)
+}
+
+func (mds *mysqlDialectSuite) TestIndexHints() {
+	ds := mds.GetDs("test")
+	mds.assertSQL(
+		sqlTestCase{
+			ds:  builder.Dialect("mysql").From(builder.T("test").UseIndex("idx_a", "idx_b")),
+			sql: "SELECT * FROM `test` USE INDEX (`idx_a`, `idx_b`)",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mysql").From(builder.T("test").As("t").ForceIndex("idx_a").IgnoreIndex("idx_b")),
+			sql: "SELECT * FROM `test` AS `t` FORCE INDEX (`idx_a`) IGNORE INDEX (`idx_b`)",
+		},
+		sqlTestCase{
+			ds:  ds.Join(builder.T("test2").UseIndex("idx_c"), builder.On(builder.I("test.id").Eq(builder.I("test2.id")))),
+			sql: "SELECT * FROM `test` INNER JOIN `test2` USE INDEX (`idx_c`) ON (`test`.`id` = `test2`.`id`)",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mariadb").From(builder.T("test").UseIndex("idx_a")),
+			sql: "SELECT * FROM `test` USE INDEX (`idx_a`)",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mysql").From(builder.T("test").WithTableHints("NOLOCK")),
+			err: "builder: dialect does not support table hints [dialect=mysql]",
+		},
+	)
+}
+
+func (mds *mysqlDialectSuite) TestOptimizerHints() {
+	ds := mds.GetDs("test").OptimizerHints("NO_ICP(test)", "BKA(test)")
+	mds.assertSQL(
+		sqlTestCase{ds: ds, sql: "SELECT /*+ NO_ICP(test) BKA(test) */ * FROM `test`"},
+		sqlTestCase{
+			ds:  ds.WithTimeout(500 * time.Millisecond),
+			sql: "SELECT /*+ NO_ICP(test) BKA(test) MAX_EXECUTION_TIME(500) */ * FROM `test`",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mysql").Update("test").OptimizerHints("NO_ICP(test)").Set(builder.Record{"a": 1}),
+			sql: "UPDATE /*+ NO_ICP(test) */ `test` SET `a`=1",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mysql").Delete("test").OptimizerHints("BKA(test)").Where(builder.C("a").Eq(1)),
+			sql: "DELETE /*+ BKA(test) */ `test` FROM `test` WHERE (`a` = 1)",
+		},
+		sqlTestCase{
+			ds:  ds.WithDialect("mariadb"),
+			err: "builder: dialect does not support optimizer hints [dialect=mariadb]",
+		},
+	)
+}
+
+func (mds *mysqlDialectSuite) TestRowLocking() {
+	ds := mds.GetDs("test")
+	mds.assertSQL(
+		sqlTestCase{ds: ds.ForUpdate(builder.Wait), sql: "SELECT * FROM `test` FOR UPDATE "},
+		sqlTestCase{ds: ds.ForShare(builder.Wait), sql: "SELECT * FROM `test` LOCK IN SHARE MODE "},
+		sqlTestCase{
+			ds:  ds.ForNoKeyUpdate(builder.Wait),
+			err: "builder: dialect does not support FOR NO KEY UPDATE [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.ForKeyShare(builder.Wait),
+			err: "builder: dialect does not support FOR KEY SHARE [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.ForUpdate(builder.SkipLocked),
+			err: "builder: dialect does not support SKIP LOCKED [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.ForUpdate(builder.Wait, builder.T("test")),
+			err: "builder: dialect does not support OF in row locks [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.WithDialect("mysql8").ForShare(builder.SkipLocked, builder.T("test")),
+			sql: "SELECT * FROM `test` FOR SHARE OF `test` SKIP LOCKED",
+		},
+		sqlTestCase{
+			ds:  ds.WithDialect("mysql8").ForNoKeyUpdate(builder.Wait),
+			err: "builder: dialect does not support FOR NO KEY UPDATE [dialect=mysql8]",
+		},
+		sqlTestCase{
+			ds:  ds.WithDialect("mariadb").ForShare(builder.NoWait),
+			sql: "SELECT * FROM `test` LOCK IN SHARE MODE NOWAIT",
+		},
+	)
+}
+
 func TestDatasetAdapterSuite(t *testing.T) {
 	suite.Run(t, new(mysqlDialectSuite))
 }
+
+func (mds *mysqlDialectSuite) TestArrays() {
+	ds := mds.GetDs("test")
+	mds.assertSQL(
+		sqlTestCase{
+			ds:  ds.Where(builder.C("tags").Contains([]string{"a"})),
+			err: "builder: dialect does not support arrays [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.Where(builder.C("id").EqAny(builder.Array([]int{1, 2}))),
+			err: "builder: dialect does not support arrays [dialect=mysql]",
+		},
+		sqlTestCase{
+			ds:  ds.Select(builder.ArrayLength(builder.C("tags"), 1)).WithDialect("mysql8"),
+			err: "builder: dialect does not support arrays [dialect=mysql8]",
+		},
+		sqlTestCase{
+			ds:  builder.Dialect("mariadb").From(builder.Unnest(builder.C("tags"))),
+			err: "builder: dialect does not support arrays [dialect=mariadb]",
+		},
+	)
+}
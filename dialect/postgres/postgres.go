@@ -8,6 +8,9 @@ func DialectOptions() *builder.SQLDialectOptions {
 	do := builder.DefaultDialectOptions()
 	do.PlaceHolderFragment = []byte("$")
 	do.IncludePlaceholderNum = true
+	do.LowercaseUnquotedIdentifiers = true
+	do.StatementTimeoutFragment = []byte("SET LOCAL statement_timeout = ")
+	do.ResetStatementTimeoutFragment = []byte("SET LOCAL statement_timeout = DEFAULT")
 	return do
 }
 
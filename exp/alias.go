@@ -57,3 +57,19 @@ func (ae aliasExpression) Col(col any) IdentifierExpression {
 func (ae aliasExpression) All() IdentifierExpression {
 	return ae.alias.All()
 }
+
+func (ae aliasExpression) UseIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(ae).UseIndex(indexes...)
+}
+
+func (ae aliasExpression) ForceIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(ae).ForceIndex(indexes...)
+}
+
+func (ae aliasExpression) IgnoreIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(ae).IgnoreIndex(indexes...)
+}
+
+func (ae aliasExpression) WithTableHints(hints ...string) TableHintExpression {
+	return NewTableHintExpression(ae).WithTableHints(hints...)
+}
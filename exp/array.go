@@ -0,0 +1,187 @@
+package exp
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/internal/errors"
+)
+
+type array struct {
+	values any
+}
+
+// Creates an array value from a slice or array. When interpolated the array is rendered as an ARRAY[...] constructor,
+// when prepared it is passed as a single parameter in the text format of PostgreSQL arrays (see Value).
+//
+//	NewArrayExpression([]int{1, 2, 3}) -> ARRAY[1, 2, 3]
+func NewArrayExpression(values any) ArrayExpression {
+	return array{values: values}
+}
+
+func (a array) Clone() Expression {
+	return array{values: a.values}
+}
+
+func (a array) Expression() Expression { return a }
+func (a array) Values() any            { return a.values }
+
+func (a array) Elements() []any {
+	v := reflect.Indirect(reflect.ValueOf(a.values))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+	elems := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elems = append(elems, v.Index(i).Interface())
+	}
+	return elems
+}
+
+func (a array) HasExpressions() bool {
+	for _, elem := range a.Elements() {
+		if _, ok := elem.(Expression); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a array) Eq(val any) BooleanExpression  { return eq(a, val) }
+func (a array) Neq(val any) BooleanExpression { return neq(a, val) }
+func (a array) Gt(val any) BooleanExpression  { return gt(a, val) }
+func (a array) Gte(val any) BooleanExpression { return gte(a, val) }
+func (a array) Lt(val any) BooleanExpression  { return lt(a, val) }
+func (a array) Lte(val any) BooleanExpression { return lte(a, val) }
+
+func (a array) Contains(val any) BooleanExpression    { return arrayContains(a, val) }
+func (a array) ContainedBy(val any) BooleanExpression { return arrayContainedBy(a, val) }
+func (a array) Overlaps(val any) BooleanExpression    { return arrayOverlaps(a, val) }
+func (a array) EqAny(val any) BooleanExpression       { return eqAny(a, val) }
+
+// Returns the array in the text format of PostgreSQL arrays (e.g. {1,2,"a b"}) so it can be passed as a parameter
+// without a driver specific array type
+func (a array) Value() (driver.Value, error) {
+	var sb strings.Builder
+	if err := writeArrayText(&sb, a.values); err != nil {
+		return nil, err
+	}
+	return sb.String(), nil
+}
+
+func writeArrayText(sb *strings.Builder, values any) error {
+	v := reflect.Indirect(reflect.ValueOf(values))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return errors.New("array values must be a slice or array got %T", values)
+	}
+	sb.WriteByte('{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := writeArrayElement(sb, v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte('}')
+	return nil
+}
+
+func writeArrayElement(sb *strings.Builder, elem any) error {
+	if valuer, ok := elem.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(elem); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			elem = nil
+		} else {
+			val, err := valuer.Value()
+			if err != nil {
+				return err
+			}
+			elem = val
+		}
+	}
+	switch t := elem.(type) {
+	case nil:
+		sb.WriteString("NULL")
+		return nil
+	case string:
+		writeArrayString(sb, t)
+		return nil
+	case []byte:
+		writeArrayString(sb, `\x`+fmt.Sprintf("%x", t))
+		return nil
+	case time.Time:
+		writeArrayString(sb, t.Format(time.RFC3339Nano))
+		return nil
+	case Expression:
+		return errors.New("array elements can not be expressions when passed as a parameter got %T", elem)
+	}
+	v := reflect.Indirect(reflect.ValueOf(elem))
+	switch k := v.Kind(); {
+	case k == reflect.Invalid:
+		sb.WriteString("NULL")
+	case k == reflect.Slice || k == reflect.Array:
+		return writeArrayText(sb, v.Interface())
+	case k == reflect.Bool:
+		sb.WriteString(strconv.FormatBool(v.Bool()))
+	case k == reflect.String:
+		writeArrayString(sb, v.String())
+	case k >= reflect.Int && k <= reflect.Int64:
+		sb.WriteString(strconv.FormatInt(v.Int(), 10))
+	case k >= reflect.Uint && k <= reflect.Uint64:
+		sb.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case k == reflect.Float32 || k == reflect.Float64:
+		sb.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	default:
+		return errors.New("unsupported array element type %T", elem)
+	}
+	return nil
+}
+
+// writes a quoted array element escaping backslashes and double quotes
+func writeArrayString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+}
+
+// used internally to create an array containment (@>) BooleanExpression
+func arrayContains(lhs Expression, val any) BooleanExpression {
+	return NewBooleanExpression(ArrayContainsOp, lhs, arrayValue(val))
+}
+
+// used internally to create an array contained by (<@) BooleanExpression
+func arrayContainedBy(lhs Expression, val any) BooleanExpression {
+	return NewBooleanExpression(ArrayContainedByOp, lhs, arrayValue(val))
+}
+
+// used internally to create an array overlap (&&) BooleanExpression
+func arrayOverlaps(lhs Expression, val any) BooleanExpression {
+	return NewBooleanExpression(ArrayOverlapOp, lhs, arrayValue(val))
+}
+
+// used internally to create an = ANY(...) BooleanExpression
+func eqAny(lhs Expression, val any) BooleanExpression {
+	return NewBooleanExpression(EqAnyOp, lhs, arrayValue(val))
+}
+
+// the operands of array operators are arrays, slices (other than []byte) are passed as an ArrayExpression instead of
+// a list of values
+func arrayValue(val any) any {
+	switch val.(type) {
+	case Expression, []byte:
+		return val
+	}
+	if k := reflect.Indirect(reflect.ValueOf(val)).Kind(); k == reflect.Slice || k == reflect.Array {
+		return NewArrayExpression(val)
+	}
+	return val
+}
@@ -0,0 +1,97 @@
+package exp_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/stretchr/testify/suite"
+)
+
+type arrayExpressionSuite struct {
+	suite.Suite
+}
+
+func TestArrayExpressionSuite(t *testing.T) {
+	suite.Run(t, &arrayExpressionSuite{})
+}
+
+func (aes *arrayExpressionSuite) TestClone() {
+	a := exp.NewArrayExpression([]int{1, 2})
+	aes.Equal(a, a.Clone())
+}
+
+func (aes *arrayExpressionSuite) TestExpression() {
+	a := exp.NewArrayExpression([]int{1, 2})
+	aes.Equal(a, a.Expression())
+}
+
+func (aes *arrayExpressionSuite) TestElements() {
+	ident := exp.NewIdentifierExpression("", "", "a")
+	aes.Equal([]any{1, 2}, exp.NewArrayExpression([]int{1, 2}).Elements())
+	aes.Equal([]any{"a"}, exp.NewArrayExpression(&[1]string{"a"}).Elements())
+	aes.Nil(exp.NewArrayExpression(1).Elements())
+	aes.False(exp.NewArrayExpression([]int{1, 2}).HasExpressions())
+	aes.True(exp.NewArrayExpression([]any{1, ident}).HasExpressions())
+}
+
+func (aes *arrayExpressionSuite) TestValue() {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		values   any
+		expected driver.Value
+	}{
+		{values: []int{1, 2, 3}, expected: "{1,2,3}"},
+		{values: []string{}, expected: "{}"},
+		{values: []string{"a b", `say "hi"`, `c\d`}, expected: `{"a b","say \"hi\"","c\\d"}`},
+		{values: []any{nil, true, 1.5, uint(2)}, expected: "{NULL,true,1.5,2}"},
+		{values: [][]int{{1, 2}, {3, 4}}, expected: "{{1,2},{3,4}}"},
+		{values: [][]byte{{0xde, 0xad}}, expected: `{"\\xdead"}`},
+		{values: []time.Time{ts}, expected: `{"2024-01-02T03:04:05Z"}`},
+	}
+	for _, tc := range cases {
+		v, err := exp.NewArrayExpression(tc.values).Value()
+		aes.NoError(err)
+		aes.Equal(tc.expected, v)
+	}
+
+	_, err := exp.NewArrayExpression(1).Value()
+	aes.EqualError(err, "builder: array values must be a slice or array got int")
+	_, err = exp.NewArrayExpression([]any{exp.NewIdentifierExpression("", "", "a")}).Value()
+	aes.EqualError(err, "builder: array elements can not be expressions when passed as a parameter got exp.identifier")
+	_, err = exp.NewArrayExpression([]any{struct{}{}}).Value()
+	aes.EqualError(err, "builder: unsupported array element type struct {}")
+}
+
+func (aes *arrayExpressionSuite) TestAllOthers() {
+	a := exp.NewArrayExpression([]int{1, 2})
+	tags := []string{"a"}
+	ident := exp.NewIdentifierExpression("", "", "tags")
+
+	testCases := []struct {
+		Ex       exp.Expression
+		Expected exp.Expression
+	}{
+		{Ex: a.Eq(ident), Expected: exp.NewBooleanExpression(exp.EqOp, a, ident)},
+		{Ex: a.Neq(ident), Expected: exp.NewBooleanExpression(exp.NeqOp, a, ident)},
+		{Ex: a.Gt(ident), Expected: exp.NewBooleanExpression(exp.GtOp, a, ident)},
+		{Ex: a.Gte(ident), Expected: exp.NewBooleanExpression(exp.GteOp, a, ident)},
+		{Ex: a.Lt(ident), Expected: exp.NewBooleanExpression(exp.LtOp, a, ident)},
+		{Ex: a.Lte(ident), Expected: exp.NewBooleanExpression(exp.LteOp, a, ident)},
+		{Ex: a.Contains(ident), Expected: exp.NewBooleanExpression(exp.ArrayContainsOp, a, ident)},
+		{
+			Ex:       a.ContainedBy(tags),
+			Expected: exp.NewBooleanExpression(exp.ArrayContainedByOp, a, exp.NewArrayExpression(tags)),
+		},
+		{
+			Ex:       a.Overlaps(tags),
+			Expected: exp.NewBooleanExpression(exp.ArrayOverlapOp, a, exp.NewArrayExpression(tags)),
+		},
+		{Ex: a.EqAny([]byte("a")), Expected: exp.NewBooleanExpression(exp.EqAnyOp, a, []byte("a"))},
+	}
+
+	for _, tc := range testCases {
+		aes.Equal(tc.Expected, tc.Ex)
+	}
+}
@@ -12,6 +12,11 @@ type columnList struct {
 }
 
 func NewColumnListExpression(vals ...any) ColumnListExpression {
+	return NewColumnListExpressionWithOptions(nil, vals...)
+}
+
+// Creates a ColumnListExpression selecting the columns of structs mapped with opts, see NewRecordFromStructWithOptions
+func NewColumnListExpressionWithOptions(opts *ColumnMapOptions, vals ...any) ColumnListExpression {
 	cols := []Expression{}
 	for _, val := range vals {
 		switch t := val.(type) {
@@ -26,13 +31,20 @@ func NewColumnListExpression(vals ...any) ColumnListExpression {
 			_, valKind := util.GetTypeInfo(val, reflect.Indirect(reflect.ValueOf(val)))
 
 			if valKind == reflect.Struct {
-				cm, err := util.GetColumnMap(val)
+				cm, err := util.GetColumnMapWithOptions(val, opts)
 				if err != nil {
 					panic(err.Error())
 				}
 				structCols := cm.Cols()
 				for _, col := range structCols {
-					i := ParseIdentifier(col)
+					// columns of nested structs are selected from their table and aliased to the prefixed name
+					// e.g. "u"."id" AS "user.id"
+					var i IdentifierExpression
+					if data := cm[col]; data.Table != "" {
+						i = NewIdentifierExpression("", data.Table, data.Column)
+					} else {
+						i = ParseIdentifier(col)
+					}
 					var sc Expression = i
 					if i.IsQualified() {
 						sc = i.As(NewIdentifierExpression("", "", col))
@@ -0,0 +1,205 @@
+package exp
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/util"
+)
+
+// Converts the value of a struct field to the value stored in its column and back. A converter is used for fields with
+// a builder tag option of the name it is registered with (e.g. `builder:"json"`) or for fields of the type it is
+// registered for, when records are built from structs (see NewRecordFromStruct) and when rows are scanned into
+// structs.
+type Converter interface {
+	// Returns the value stored in the column for the field value v, v is never nil
+	ToDB(v any) (any, error)
+	// Sets the field dst points to from the value src scanned from the column, src is never nil
+	FromDB(dst, src any) error
+}
+
+type (
+	// Stores values as JSON, e.g. `builder:"json"`
+	JSONConverter struct{}
+	// Stores slices as a comma-separated list of their elements, e.g. `builder:"list"` for []string{"a", "b"} -> 'a,b'
+	ListConverter struct{}
+	// Stores values implementing encoding.TextMarshaler and encoding.TextUnmarshaler as text, e.g. `builder:"text"`
+	TextConverter struct{}
+)
+
+func init() {
+	RegisterConverter("json", JSONConverter{})
+	RegisterConverter("list", ListConverter{})
+	RegisterConverter("text", TextConverter{})
+}
+
+// Registers a converter for fields with a builder tag option of name
+//
+//	RegisterConverter("encrypted", encryptedConverter{})
+//	// Password string `db:"password" builder:"encrypted"`
+func RegisterConverter(name string, c Converter) {
+	util.RegisterConverter(name, c)
+}
+
+// Registers a converter for fields of type t (or pointers to t)
+//
+//	RegisterTypeConverter(reflect.TypeOf(Settings{}), JSONConverter{})
+func RegisterTypeConverter(t reflect.Type, c Converter) {
+	util.RegisterTypeConverter(t, c)
+}
+
+// Returns the converter of a column of a struct, or nil if the values of the column are stored as they are
+func ColumnConverter(f util.ColumnData) (Converter, error) {
+	c, ok := util.GetConverter(f.Converter, f.GoType)
+	if !ok {
+		if f.Converter != "" {
+			return nil, errors.New("converter %q of column %q is not registered", f.Converter, f.ColumnName)
+		}
+		return nil, nil
+	}
+	return c.(Converter), nil
+}
+
+func (JSONConverter) ToDB(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (JSONConverter) FromDB(dst, src any) error {
+	b, err := converterBytes(dst, src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func (ListConverter) ToDB(v any) (any, error) {
+	val := reflect.ValueOf(v)
+	if util.IsPointer(val.Kind()) {
+		val = val.Elem()
+	}
+	if !util.IsSlice(val.Kind()) && val.Kind() != reflect.Array {
+		return nil, errors.New("list converter can not convert %T", v)
+	}
+	elems := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		elems = append(elems, fmt.Sprint(val.Index(i).Interface()))
+	}
+	return strings.Join(elems, ","), nil
+}
+
+func (ListConverter) FromDB(dst, src any) error {
+	b, err := converterBytes(dst, src)
+	if err != nil {
+		return err
+	}
+	val := allocIndirect(reflect.ValueOf(dst))
+	if !util.IsSlice(val.Kind()) {
+		return errors.New("list converter can not convert to %T", dst)
+	}
+	var elems []string
+	if len(b) > 0 {
+		elems = strings.Split(string(b), ",")
+	}
+	list := reflect.MakeSlice(val.Type(), 0, len(elems))
+	for _, elem := range elems {
+		ev := reflect.New(val.Type().Elem()).Elem()
+		if err := setListElem(ev, elem); err != nil {
+			return err
+		}
+		list = reflect.Append(list, ev)
+	}
+	val.Set(list)
+	return nil
+}
+
+func setListElem(v reflect.Value, s string) error {
+	var err error
+	switch k := v.Kind(); {
+	case util.IsString(k):
+		v.SetString(s)
+	case util.IsInt(k):
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case util.IsUint(k):
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case util.IsFloat(k):
+		var f float64
+		if f, err = strconv.ParseFloat(s, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	case util.IsBool(k):
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+	default:
+		return errors.New("list converter can not convert to %s", v.Type())
+	}
+	return err
+}
+
+func (TextConverter) ToDB(v any) (any, error) {
+	m, ok := v.(encoding.TextMarshaler)
+	if !ok {
+		// the method may be declared on the pointer
+		ptr := reflect.New(reflect.TypeOf(v))
+		ptr.Elem().Set(reflect.ValueOf(v))
+		if m, ok = ptr.Interface().(encoding.TextMarshaler); !ok {
+			return nil, errors.New("text converter can not convert %T", v)
+		}
+	}
+	b, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (TextConverter) FromDB(dst, src any) error {
+	b, err := converterBytes(dst, src)
+	if err != nil {
+		return err
+	}
+	u, ok := allocIndirect(reflect.ValueOf(dst)).Addr().Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return errors.New("text converter can not convert to %T", dst)
+	}
+	return u.UnmarshalText(b)
+}
+
+// Returns the text or bytes drivers return for the column of a converted field
+func converterBytes(dst, src any) ([]byte, error) {
+	switch t := src.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	}
+	return nil, errors.New("can not convert %T to %T", src, dst)
+}
+
+// Returns the value ptr points to, allocating the values of nil pointers (e.g. when ptr is a **T)
+func allocIndirect(ptr reflect.Value) reflect.Value {
+	v := ptr.Elem()
+	for util.IsPointer(v.Kind()) {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package exp_test
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/stretchr/testify/suite"
+)
+
+type converterSettings struct {
+	Theme string `json:"theme"`
+}
+
+type converterLevel int
+
+type converterLevelConverter struct{}
+
+func (converterLevelConverter) ToDB(v any) (any, error) {
+	return []string{"low", "high"}[v.(converterLevel)], nil
+}
+
+func (converterLevelConverter) FromDB(dst, src any) error {
+	*dst.(*converterLevel) = map[string]converterLevel{"low": 0, "high": 1}[string(src.([]byte))]
+	return nil
+}
+
+type upperConverter struct{}
+
+func (upperConverter) ToDB(v any) (any, error) { return strings.ToUpper(v.(string)), nil }
+func (upperConverter) FromDB(dst, src any) error {
+	*dst.(*string) = strings.ToLower(string(src.([]byte)))
+	return nil
+}
+
+type converterTestSuite struct {
+	suite.Suite
+}
+
+func (cts *converterTestSuite) SetupSuite() {
+	exp.RegisterConverter("upper", upperConverter{})
+	exp.RegisterTypeConverter(reflect.TypeOf(converterLevel(0)), converterLevelConverter{})
+}
+
+func (cts *converterTestSuite) TestNewRecordFromStruct() {
+	type item struct {
+		Settings  converterSettings  `db:"settings" builder:"json"`
+		Optional  *converterSettings `db:"optional" builder:"json,skipupdate"`
+		Tags      []string           `db:"tags" builder:"list"`
+		IP        net.IP             `db:"ip" builder:"text"`
+		Code      string             `db:"code" builder:"upper"`
+		Level     converterLevel     `db:"level"`
+		Untouched string             `db:"untouched"`
+	}
+	r, err := exp.NewRecordFromStruct(item{
+		Settings:  converterSettings{Theme: "dark"},
+		Tags:      []string{"a", "b"},
+		IP:        net.IPv4(127, 0, 0, 1),
+		Code:      "abc",
+		Level:     1,
+		Untouched: "x",
+	}, true, false)
+	cts.NoError(err)
+	cts.Equal(exp.Record{
+		"settings":  `{"theme":"dark"}`,
+		"optional":  nil,
+		"tags":      "a,b",
+		"ip":        "127.0.0.1",
+		"code":      "ABC",
+		"level":     "high",
+		"untouched": "x",
+	}, r)
+
+	r, err = exp.NewRecordFromStruct(item{}, false, true)
+	cts.NoError(err)
+	cts.NotContains(r, "optional")
+	cts.Nil(r["tags"])
+	cts.Equal("low", r["level"])
+}
+
+func (cts *converterTestSuite) TestNewRecordFromStruct_notRegistered() {
+	type item struct {
+		Name string `db:"name" builder:"unknown"`
+	}
+	_, err := exp.NewRecordFromStruct(item{}, true, false)
+	cts.EqualError(err, `builder: converter "unknown" of column "name" is not registered`)
+}
+
+func (cts *converterTestSuite) TestJSONConverter() {
+	var s *converterSettings
+	cts.NoError(exp.JSONConverter{}.FromDB(&s, []byte(`{"theme":"light"}`)))
+	cts.Equal(&converterSettings{Theme: "light"}, s)
+	cts.EqualError(exp.JSONConverter{}.FromDB(&s, int64(1)), "builder: can not convert int64 to **exp_test.converterSettings")
+}
+
+func (cts *converterTestSuite) TestListConverter() {
+	v, err := exp.ListConverter{}.ToDB([]int{1, 2, 3})
+	cts.NoError(err)
+	cts.Equal("1,2,3", v)
+	_, err = exp.ListConverter{}.ToDB(1)
+	cts.EqualError(err, "builder: list converter can not convert int")
+
+	var ints []int
+	cts.NoError(exp.ListConverter{}.FromDB(&ints, "1,2,3"))
+	cts.Equal([]int{1, 2, 3}, ints)
+	cts.NoError(exp.ListConverter{}.FromDB(&ints, ""))
+	cts.Equal([]int{}, ints)
+	cts.Error(exp.ListConverter{}.FromDB(&ints, "a"))
+
+	var strs []string
+	cts.NoError(exp.ListConverter{}.FromDB(&strs, []byte("a,b")))
+	cts.Equal([]string{"a", "b"}, strs)
+}
+
+func (cts *converterTestSuite) TestTextConverter() {
+	var ip net.IP
+	cts.NoError(exp.TextConverter{}.FromDB(&ip, []byte("10.0.0.1")))
+	cts.Equal(net.IPv4(10, 0, 0, 1), ip)
+
+	_, err := exp.TextConverter{}.ToDB(1)
+	cts.EqualError(err, "builder: text converter can not convert int")
+}
+
+func TestConverterSuite(t *testing.T) {
+	suite.Run(t, new(converterTestSuite))
+}
@@ -1,10 +1,30 @@
 package exp
 
-type commonExpr struct {
-	recursive bool
-	name      LiteralExpression
-	subQuery  Expression
-}
+type (
+	commonExpr struct {
+		recursive       bool
+		name            LiteralExpression
+		ident           IdentifierExpression
+		cols            ColumnListExpression
+		materialization CTEMaterialization
+		search          CTESearch
+		cycle           CTECycle
+		subQuery        Expression
+	}
+	cteSearch struct {
+		searchType CTESearchType
+		by         ColumnListExpression
+		set        IdentifierExpression
+	}
+	cteCycle struct {
+		cols         ColumnListExpression
+		set          IdentifierExpression
+		mark         any
+		defaultValue any
+		hasMark      bool
+		using        IdentifierExpression
+	}
+)
 
 // Creates a new WITH common table expression for a SQLExpression, typically Datasets'. This function is used
 // internally by Dataset when a CTE is added to another Dataset
@@ -12,12 +32,113 @@ func NewCommonTableExpression(recursive bool, name string, subQuery Expression)
 	return commonExpr{recursive: recursive, name: NewLiteralExpression(name), subQuery: subQuery}
 }
 
+// Creates a new WITH common table expression with a quoted name, the column names, materialization and the SEARCH
+// and CYCLE clauses can be set with the methods of the returned CommonTableExpression.
+func NewNamedCommonTableExpression(name IdentifierExpression, subQuery Expression) CommonTableExpression {
+	return commonExpr{ident: name, subQuery: subQuery}
+}
+
 func (ce commonExpr) Expression() Expression { return ce }
 
 func (ce commonExpr) Clone() Expression {
-	return commonExpr{recursive: ce.recursive, name: ce.name, subQuery: ce.subQuery.Clone().(SQLExpression)}
+	ret := ce
+	ret.subQuery = ce.subQuery.Clone()
+	if ce.cols != nil {
+		ret.cols = ce.cols.Clone().(ColumnListExpression)
+	}
+	return ret
 }
 
-func (ce commonExpr) IsRecursive() bool       { return ce.recursive }
+func (ce commonExpr) IsRecursive() bool { return ce.recursive }
+
+// Returns the name of a CTE created with NewCommonTableExpression, the name may include the column names
 func (ce commonExpr) Name() LiteralExpression { return ce.name }
-func (ce commonExpr) SubQuery() Expression    { return ce.subQuery }
+
+// Returns the name of a CTE created with NewNamedCommonTableExpression
+func (ce commonExpr) Identifier() IdentifierExpression { return ce.ident }
+func (ce commonExpr) SubQuery() Expression             { return ce.subQuery }
+func (ce commonExpr) Cols() ColumnListExpression       { return ce.cols }
+func (ce commonExpr) HasCols() bool                    { return ce.cols != nil && !ce.cols.IsEmpty() }
+
+func (ce commonExpr) Materialization() CTEMaterialization { return ce.materialization }
+func (ce commonExpr) SearchClause() CTESearch             { return ce.search }
+func (ce commonExpr) CycleClause() CTECycle               { return ce.cycle }
+
+func (ce commonExpr) Recursive() CommonTableExpression {
+	ret := ce
+	ret.recursive = true
+	return ret
+}
+
+func (ce commonExpr) Columns(cols ...string) CommonTableExpression {
+	ret := ce
+	vals := make([]any, 0, len(cols))
+	for _, col := range cols {
+		vals = append(vals, NewIdentifierExpression("", "", col))
+	}
+	ret.cols = NewColumnListExpression(vals...)
+	return ret
+}
+
+func (ce commonExpr) Materialized() CommonTableExpression {
+	ret := ce
+	ret.materialization = MaterializedCTE
+	return ret
+}
+
+func (ce commonExpr) NotMaterialized() CommonTableExpression {
+	ret := ce
+	ret.materialization = NotMaterializedCTE
+	return ret
+}
+
+// Sets the SEARCH clause, a CTE with a SEARCH clause is recursive
+func (ce commonExpr) Search(search CTESearch) CommonTableExpression {
+	ret := ce
+	ret.search = search
+	ret.recursive = ret.recursive || search != nil
+	return ret
+}
+
+// Sets the CYCLE clause, a CTE with a CYCLE clause is recursive
+func (ce commonExpr) Cycle(cycle CTECycle) CommonTableExpression {
+	ret := ce
+	ret.cycle = cycle
+	ret.recursive = ret.recursive || cycle != nil
+	return ret
+}
+
+// Creates the SEARCH clause of a recursive CTE
+//
+//	NewCTESearch(DepthFirstSearch, NewColumnListExpression("id"), NewIdentifierExpression("", "", "ordercol"))
+//	// SEARCH DEPTH FIRST BY "id" SET "ordercol"
+func NewCTESearch(searchType CTESearchType, by ColumnListExpression, set IdentifierExpression) CTESearch {
+	return cteSearch{searchType: searchType, by: by, set: set}
+}
+
+func (cs cteSearch) Type() CTESearchType       { return cs.searchType }
+func (cs cteSearch) By() ColumnListExpression  { return cs.by }
+func (cs cteSearch) Set() IdentifierExpression { return cs.set }
+
+// Creates the CYCLE clause of a recursive CTE
+//
+//	NewCTECycle(NewColumnListExpression("id"), NewIdentifierExpression("", "", "is_cycle"),
+//		NewIdentifierExpression("", "", "path"))
+//	// CYCLE "id" SET "is_cycle" USING "path"
+func NewCTECycle(cols ColumnListExpression, set, using IdentifierExpression) CTECycle {
+	return cteCycle{cols: cols, set: set, using: using}
+}
+
+func (cc cteCycle) Cols() ColumnListExpression  { return cc.cols }
+func (cc cteCycle) Set() IdentifierExpression   { return cc.set }
+func (cc cteCycle) Using() IdentifierExpression { return cc.using }
+func (cc cteCycle) HasMark() bool               { return cc.hasMark }
+func (cc cteCycle) MarkValue() any              { return cc.mark }
+func (cc cteCycle) MarkDefault() any            { return cc.defaultValue }
+
+// Sets the values of the cycle mark column, TO mark DEFAULT defaultValue
+func (cc cteCycle) Mark(mark, defaultValue any) CTECycle {
+	ret := cc
+	ret.mark, ret.defaultValue, ret.hasMark = mark, defaultValue, true
+	return ret
+}
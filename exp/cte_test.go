@@ -0,0 +1,80 @@
+package exp_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/stretchr/testify/suite"
+)
+
+type commonTableExpressionSuite struct {
+	suite.Suite
+}
+
+func TestCommonTableExpressionSuite(t *testing.T) {
+	suite.Run(t, new(commonTableExpressionSuite))
+}
+
+func (ctes *commonTableExpressionSuite) TestNewCommonTableExpression() {
+	sub := exp.NewLiteralExpression("SELECT 1")
+	cte := exp.NewCommonTableExpression(true, "a(x)", sub)
+
+	ctes.True(cte.IsRecursive())
+	ctes.Equal(exp.NewLiteralExpression("a(x)"), cte.Name())
+	ctes.Nil(cte.Identifier())
+	ctes.False(cte.HasCols())
+	ctes.Equal(exp.DefaultMaterializationCTE, cte.Materialization())
+	ctes.Nil(cte.SearchClause())
+	ctes.Nil(cte.CycleClause())
+	ctes.Equal(cte, cte.Clone())
+}
+
+func (ctes *commonTableExpressionSuite) TestNewNamedCommonTableExpression() {
+	name := exp.NewIdentifierExpression("", "tree", "")
+	sub := exp.NewLiteralExpression("SELECT 1")
+	cte := exp.NewNamedCommonTableExpression(name, sub)
+
+	ctes.False(cte.IsRecursive())
+	ctes.Equal(name, cte.Identifier())
+	ctes.Equal(sub, cte.SubQuery())
+
+	withCols := cte.Columns("id", "parent_id")
+	ctes.False(cte.HasCols())
+	ctes.True(withCols.HasCols())
+	ctes.Equal(exp.NewColumnListExpression(
+		exp.NewIdentifierExpression("", "", "id"),
+		exp.NewIdentifierExpression("", "", "parent_id"),
+	), withCols.Cols())
+
+	ctes.Equal(exp.MaterializedCTE, cte.Materialized().Materialization())
+	ctes.Equal(exp.NotMaterializedCTE, cte.NotMaterialized().Materialization())
+	ctes.True(cte.Recursive().IsRecursive())
+}
+
+func (ctes *commonTableExpressionSuite) TestSearchAndCycle() {
+	cte := exp.NewNamedCommonTableExpression(exp.NewIdentifierExpression("", "tree", ""), exp.NewLiteralExpression("SELECT 1"))
+	by := exp.NewColumnListExpression("id")
+	set := exp.NewIdentifierExpression("", "", "ordercol")
+	search := exp.NewCTESearch(exp.BreadthFirstSearch, by, set)
+
+	withSearch := cte.Search(search)
+	ctes.True(withSearch.IsRecursive())
+	ctes.Equal(search, withSearch.SearchClause())
+	ctes.Equal(exp.BreadthFirstSearch, search.Type())
+	ctes.Equal(by, search.By())
+	ctes.Equal(set, search.Set())
+
+	using := exp.NewIdentifierExpression("", "", "path")
+	cycle := exp.NewCTECycle(by, set, using)
+	ctes.False(cycle.HasMark())
+	marked := cycle.Mark("Y", "N")
+	ctes.False(cycle.HasMark())
+	ctes.True(marked.HasMark())
+	ctes.Equal("Y", marked.MarkValue())
+	ctes.Equal("N", marked.MarkDefault())
+	ctes.Equal(using, marked.Using())
+
+	withCycle := cte.Cycle(marked)
+	ctes.True(withCycle.IsRecursive())
+	ctes.Equal(marked, withCycle.CycleClause())
+}
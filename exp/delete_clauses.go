@@ -1,5 +1,7 @@
 package exp
 
+import "time"
+
 type (
 	DeleteClauses interface {
 		HasFrom() bool
@@ -30,14 +32,22 @@ type (
 		Returning() ColumnListExpression
 		HasReturning() bool
 		SetReturning(cl ColumnListExpression) DeleteClauses
+
+		Timeout() time.Duration
+		SetTimeout(timeout time.Duration) DeleteClauses
+
+		OptimizerHints() []string
+		OptimizerHintsAppend(hints ...string) DeleteClauses
 	}
 	deleteClauses struct {
-		commonTables []CommonTableExpression
-		from         IdentifierExpression
-		where        ExpressionList
-		order        ColumnListExpression
-		limit        any
-		returning    ColumnListExpression
+		commonTables   []CommonTableExpression
+		from           IdentifierExpression
+		where          ExpressionList
+		order          ColumnListExpression
+		limit          any
+		returning      ColumnListExpression
+		timeout        time.Duration
+		optimizerHints []string
 	}
 )
 
@@ -54,10 +64,12 @@ func (dc *deleteClauses) clone() *deleteClauses {
 		commonTables: dc.commonTables,
 		from:         dc.from,
 
-		where:     dc.where,
-		order:     dc.order,
-		limit:     dc.limit,
-		returning: dc.returning,
+		where:          dc.where,
+		order:          dc.order,
+		limit:          dc.limit,
+		returning:      dc.returning,
+		timeout:        dc.timeout,
+		optimizerHints: dc.optimizerHints,
 	}
 }
 
@@ -175,3 +187,23 @@ func (dc *deleteClauses) SetReturning(cl ColumnListExpression) DeleteClauses {
 	ret.returning = cl
 	return ret
 }
+
+func (dc *deleteClauses) Timeout() time.Duration {
+	return dc.timeout
+}
+
+func (dc *deleteClauses) SetTimeout(timeout time.Duration) DeleteClauses {
+	ret := dc.clone()
+	ret.timeout = timeout
+	return ret
+}
+
+func (dc *deleteClauses) OptimizerHints() []string {
+	return dc.optimizerHints
+}
+
+func (dc *deleteClauses) OptimizerHintsAppend(hints ...string) DeleteClauses {
+	ret := dc.clone()
+	ret.optimizerHints = append(ret.optimizerHints[0:len(ret.optimizerHints):len(ret.optimizerHints)], hints...)
+	return ret
+}
@@ -2,6 +2,7 @@ package exp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/stretchr/testify/suite"
@@ -254,3 +255,42 @@ func (dcs *deleteClausesSuite) TestSetReturning() {
 
 	dcs.Equal(cl2, c2.Returning())
 }
+
+func (dcs *deleteClausesSuite) TestTimeout() {
+	c := exp.NewDeleteClauses()
+	c2 := c.SetTimeout(time.Second)
+
+	dcs.Zero(c.Timeout())
+
+	dcs.Equal(time.Second, c2.Timeout())
+}
+
+func (dcs *deleteClausesSuite) TestSetTimeout() {
+	c := exp.NewDeleteClauses().SetTimeout(time.Second)
+	c2 := c.SetTimeout(time.Minute)
+
+	dcs.Equal(time.Second, c.Timeout())
+
+	dcs.Equal(time.Minute, c2.Timeout())
+}
+
+func (dcs *deleteClausesSuite) TestOptimizerHints() {
+	c := exp.NewDeleteClauses()
+	c2 := c.OptimizerHintsAppend("NO_ICP(t1)")
+
+	dcs.Nil(c.OptimizerHints())
+
+	dcs.Equal([]string{"NO_ICP(t1)"}, c2.OptimizerHints())
+}
+
+func (dcs *deleteClausesSuite) TestOptimizerHintsAppend() {
+	c := exp.NewDeleteClauses().OptimizerHintsAppend("NO_ICP(t1)")
+	c2 := c.OptimizerHintsAppend("BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)")
+	c3 := c.OptimizerHintsAppend("MRR(t1)")
+
+	dcs.Equal([]string{"NO_ICP(t1)"}, c.OptimizerHints())
+
+	dcs.Equal([]string{"NO_ICP(t1)", "BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)"}, c2.OptimizerHints())
+
+	dcs.Equal([]string{"NO_ICP(t1)", "MRR(t1)"}, c3.OptimizerHints())
+}
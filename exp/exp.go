@@ -1,6 +1,7 @@
 package exp
 
 import (
+	"database/sql/driver"
 	"fmt"
 
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -139,6 +140,37 @@ type (
 		Set(any) UpdateExpression
 	}
 
+	// Interface that an expression should implement if it can be used as a table reference with hints.
+	TableHintable interface {
+		// Creates a USE INDEX table hint
+		//   I("a").UseIndex("idx_b") //`a` USE INDEX (`idx_b`)
+		UseIndex(indexes ...string) TableHintExpression
+		// Creates a FORCE INDEX table hint
+		//   I("a").ForceIndex("idx_b") //`a` FORCE INDEX (`idx_b`)
+		ForceIndex(indexes ...string) TableHintExpression
+		// Creates an IGNORE INDEX table hint
+		//   I("a").IgnoreIndex("idx_b") //`a` IGNORE INDEX (`idx_b`)
+		IgnoreIndex(indexes ...string) TableHintExpression
+		// Creates a WITH table hint, the hints are not escaped
+		//   I("a").WithTableHints("NOLOCK") //[a] WITH (NOLOCK)
+		WithTableHints(hints ...string) TableHintExpression
+	}
+
+	Arrayable interface {
+		// Creates a Boolean expression for array containment
+		//   I("tags").Contains(Array([]string{"a"})) //("tags" @> ARRAY['a'])
+		Contains(any) BooleanExpression
+		// Creates a Boolean expression for array containment by the value
+		//   I("tags").ContainedBy(Array([]string{"a", "b"})) //("tags" <@ ARRAY['a', 'b'])
+		ContainedBy(any) BooleanExpression
+		// Creates a Boolean expression for arrays with common elements
+		//   I("tags").Overlaps(Array([]string{"a", "b"})) //("tags" && ARRAY['a', 'b'])
+		Overlaps(any) BooleanExpression
+		// Creates a Boolean expression comparing equality with any element of an array
+		//   I("id").EqAny(Array([]int{1, 2})) //("id" = ANY(ARRAY[1, 2]))
+		EqAny(any) BooleanExpression
+	}
+
 	Bitwiseable interface {
 		// Creates a Bit Operation Expresion for sql ~
 		// I("col").BitiInversion() // (~ "col")
@@ -190,6 +222,7 @@ type (
 	//   SUM("a").As(I("a_sum")) -> SUM("a") AS "a_sum"
 	AliasedExpression interface {
 		Expression
+		TableHintable
 		// Returns the Epxression being aliased
 		Aliased() Expression
 		// Returns the alias value as an identiier expression
@@ -287,8 +320,71 @@ type (
 		IsRecursive() bool
 		// Returns the alias name for the extracted expression
 		Name() LiteralExpression
+		// Returns the quoted alias name for the extracted expression, nil if the name is a literal
+		Identifier() IdentifierExpression
 		// Returns the Expression being extracted
 		SubQuery() Expression
+		// Returns the column names of the CTE
+		Cols() ColumnListExpression
+		HasCols() bool
+		Materialization() CTEMaterialization
+		// Returns the SEARCH clause, nil if not set
+		SearchClause() CTESearch
+		// Returns the CYCLE clause, nil if not set
+		CycleClause() CTECycle
+
+		Recursive() CommonTableExpression
+		Columns(cols ...string) CommonTableExpression
+		Materialized() CommonTableExpression
+		NotMaterialized() CommonTableExpression
+		Search(search CTESearch) CommonTableExpression
+		Cycle(cycle CTECycle) CommonTableExpression
+	}
+	// A VALUES list used as a table source
+	//    (VALUES (1, 'a'), (2, 'b')) AS "v"("id", "name")
+	ValuesListExpression interface {
+		AppendableExpression
+		// Returns the rows of the VALUES list
+		Rows() [][]any
+		// Returns the names of the columns set with As
+		Cols() ColumnListExpression
+		HasCols() bool
+		// Returns a new VALUES list with the alias and column names
+		As(alias string, cols ...string) ValuesListExpression
+	}
+	// An array value, e.g. ARRAY[1, 2, 3]
+	ArrayExpression interface {
+		Expression
+		Comparable
+		Arrayable
+		// Returns the slice or array of the elements
+		Values() any
+		// Returns the elements
+		Elements() []any
+		// Returns true if any element is an expression, e.g. ARRAY["a", "b"]
+		HasExpressions() bool
+		// Returns the array in the text format of PostgreSQL arrays, used when the array is passed as an argument
+		driver.Valuer
+	}
+	CTEMaterialization int
+	CTESearchType      int
+	// The SEARCH clause of a recursive CTE
+	//    SEARCH DEPTH FIRST BY "id" SET "ordercol"
+	CTESearch interface {
+		Type() CTESearchType
+		By() ColumnListExpression
+		Set() IdentifierExpression
+	}
+	// The CYCLE clause of a recursive CTE
+	//    CYCLE "id" SET "is_cycle" TO TRUE DEFAULT FALSE USING "path"
+	CTECycle interface {
+		Cols() ColumnListExpression
+		Set() IdentifierExpression
+		Using() IdentifierExpression
+		HasMark() bool
+		MarkValue() any
+		MarkDefault() any
+		Mark(mark, defaultValue any) CTECycle
 	}
 	ExpressionListType int
 	// A list of expressions that should be joined together
@@ -319,6 +415,8 @@ type (
 		Distinctable
 		Castable
 		Bitwiseable
+		TableHintable
+		Arrayable
 		// returns true if this identifier has more more than on part (Schema, Table or Col)
 		//	"schema" -> true //cant qualify anymore
 		//	"schema.table" -> true
@@ -370,11 +468,55 @@ type (
 		Condition() JoinCondition
 		IsConditionEmpty() bool
 	}
+	TableHintType int
+	// A hint for a table reference (e.g. USE INDEX (`idx`), WITH (NOLOCK))
+	TableHint interface {
+		Type() TableHintType
+		// The indexes of an index hint or the hints of a WITH table hint
+		Values() []string
+	}
+	// Expression for a table reference with hints
+	//   I("a").UseIndex("idx_b") -> `a` USE INDEX (`idx_b`)
+	//   I("a").As("b").WithTableHints("NOLOCK") -> [a] AS [b] WITH (NOLOCK)
+	TableHintExpression interface {
+		Expression
+		TableHintable
+		// The table reference the hints apply to
+		Table() Expression
+		Hints() []TableHint
+	}
 	LateralExpression interface {
 		Expression
 		Aliaseable
 		Table() AppendableExpression
 	}
+	// A column of the alias of a table function, the type is only set for column definition lists
+	//   generate_series(1, 3) AS "s"("n")
+	//   jsonb_to_recordset('[...]') AS "r"("a" int, "b" text)
+	TableFunctionColumn interface {
+		Name() string
+		Type() string
+	}
+	// Expression for a function returning rows used as a table source in From, joins and Lateral
+	//   NewTableFunctionExpression("generate_series", 1, 3).As("s", "n") -> generate_series(1, 3) AS "s"("n")
+	TableFunctionExpression interface {
+		AppendableExpression
+		// The function name
+		Name() string
+		// The arguments passed to the function
+		Args() []any
+		// The columns of the alias
+		Columns() []TableFunctionColumn
+		// Returns true if any column has a type, e.g. jsonb_to_recordset(...) AS "r"("a" int)
+		HasColumnDefinitions() bool
+		IsWithOrdinality() bool
+		// Aliases the function result and names its columns
+		As(alias string, cols ...string) TableFunctionExpression
+		// Appends a column with a type to the column definition list
+		Column(name, sqlType string) TableFunctionExpression
+		// Adds WITH ORDINALITY, numbering the returned rows in an extra column
+		WithOrdinality() TableFunctionExpression
+	}
 
 	// Expression for representing "literal" sql.
 	//  L("col = 1") -> col = 1)
@@ -509,6 +651,17 @@ type (
 	}
 )
 
+const (
+	DefaultMaterializationCTE CTEMaterialization = iota
+	MaterializedCTE
+	NotMaterializedCTE
+)
+
+const (
+	DepthFirstSearch CTESearchType = iota
+	BreadthFirstSearch
+)
+
 const (
 	UnionCompoundType CompoundType = iota
 	UnionAllCompoundType
@@ -589,6 +742,14 @@ const (
 	RegexpILikeOp
 	// !~*, NOT REGEXP
 	RegexpNotILikeOp
+	// @>
+	ArrayContainsOp
+	// <@
+	ArrayContainedByOp
+	// &&
+	ArrayOverlapOp
+	// = ANY
+	EqAnyOp
 
 	betweenStr = "between"
 
@@ -671,6 +832,14 @@ func (bo BooleanOperation) String() string {
 		return "regexpilike"
 	case RegexpNotILikeOp:
 		return "regexpnotilike"
+	case ArrayContainsOp:
+		return "contains"
+	case ArrayContainedByOp:
+		return "containedby"
+	case ArrayOverlapOp:
+		return "overlaps"
+	case EqAnyOp:
+		return "eqany"
 	}
 	return fmt.Sprintf("%d", bo)
 }
@@ -147,6 +147,14 @@ func createExpressionFromOp(lhs IdentifierExpression, opKey string, op Op) (exp
 		exp = lhs.RegexpILike(op[opKey])
 	case RegexpNotILikeOp.String():
 		exp = lhs.RegexpNotILike(op[opKey])
+	case ArrayContainsOp.String():
+		exp = lhs.Contains(op[opKey])
+	case ArrayContainedByOp.String():
+		exp = lhs.ContainedBy(op[opKey])
+	case ArrayOverlapOp.String():
+		exp = lhs.Overlaps(op[opKey])
+	case EqAnyOp.String():
+		exp = lhs.EqAny(op[opKey])
 	case betweenStr:
 		rangeVal, ok := op[opKey].(RangeVal)
 		if ok {
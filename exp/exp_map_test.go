@@ -121,6 +121,25 @@ func (ets *exTestSuite) TestToExpression() {
 			ExMap: exp.Ex{"a": exp.Op{"regexpNotILike": "b"}},
 			El:    exp.NewExpressionList(exp.AndType, exp.NewExpressionList(exp.OrType, ident.RegexpNotILike("b"))),
 		},
+		{
+			ExMap: exp.Ex{"a": exp.Op{"contains": []string{"b"}}},
+			El: exp.NewExpressionList(
+				exp.AndType,
+				exp.NewExpressionList(exp.OrType, ident.Contains(exp.NewArrayExpression([]string{"b"}))),
+			),
+		},
+		{
+			ExMap: exp.Ex{"a": exp.Op{"containedBy": []string{"b"}}},
+			El:    exp.NewExpressionList(exp.AndType, exp.NewExpressionList(exp.OrType, ident.ContainedBy([]string{"b"}))),
+		},
+		{
+			ExMap: exp.Ex{"a": exp.Op{"overlaps": []string{"b"}}},
+			El:    exp.NewExpressionList(exp.AndType, exp.NewExpressionList(exp.OrType, ident.Overlaps([]string{"b"}))),
+		},
+		{
+			ExMap: exp.Ex{"a": exp.Op{"eqAny": []int{1, 2}}},
+			El:    exp.NewExpressionList(exp.AndType, exp.NewExpressionList(exp.OrType, ident.EqAny([]int{1, 2}))),
+		},
 		{
 			ExMap: exp.Ex{"a": exp.Op{"between": exp.NewRangeVal("a", "z")}},
 			El:    exp.NewExpressionList(exp.AndType, exp.NewExpressionList(exp.OrType, ident.Between(exp.NewRangeVal("a", "z")))),
@@ -206,6 +206,27 @@ func (i identifier) Desc() OrderedExpression                  { return desc(i) }
 func (i identifier) Distinct() SQLFunctionExpression          { return NewSQLFunctionExpression("DISTINCT", i) }
 func (i identifier) Cast(t string) CastExpression             { return NewCastExpression(i, t) }
 
+func (i identifier) UseIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(i).UseIndex(indexes...)
+}
+
+func (i identifier) ForceIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(i).ForceIndex(indexes...)
+}
+
+func (i identifier) IgnoreIndex(indexes ...string) TableHintExpression {
+	return NewTableHintExpression(i).IgnoreIndex(indexes...)
+}
+
+func (i identifier) WithTableHints(hints ...string) TableHintExpression {
+	return NewTableHintExpression(i).WithTableHints(hints...)
+}
+
+func (i identifier) Contains(val any) BooleanExpression    { return arrayContains(i, val) }
+func (i identifier) ContainedBy(val any) BooleanExpression { return arrayContainedBy(i, val) }
+func (i identifier) Overlaps(val any) BooleanExpression    { return arrayOverlaps(i, val) }
+func (i identifier) EqAny(val any) BooleanExpression       { return eqAny(i, val) }
+
 // Returns a RangeExpression for checking that a identifier is between two values (e.g "my_col" BETWEEN 1 AND 10)
 func (i identifier) Between(val RangeVal) RangeExpression { return between(i, val) }
 
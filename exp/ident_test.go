@@ -222,6 +222,13 @@ func (ies *identifierExpressionSuite) TestAllOthers() {
 		{Ex: ident.RegexpNotLike(pattern), Expected: exp.NewBooleanExpression(exp.RegexpNotLikeOp, ident, pattern)},
 		{Ex: ident.RegexpILike(pattern), Expected: exp.NewBooleanExpression(exp.RegexpILikeOp, ident, pattern)},
 		{Ex: ident.RegexpNotILike(pattern), Expected: exp.NewBooleanExpression(exp.RegexpNotILikeOp, ident, pattern)},
+		{Ex: ident.Contains(ident), Expected: exp.NewBooleanExpression(exp.ArrayContainsOp, ident, ident)},
+		{Ex: ident.ContainedBy(ident), Expected: exp.NewBooleanExpression(exp.ArrayContainedByOp, ident, ident)},
+		{Ex: ident.Overlaps(ident), Expected: exp.NewBooleanExpression(exp.ArrayOverlapOp, ident, ident)},
+		{
+			Ex:       ident.EqAny(inVals),
+			Expected: exp.NewBooleanExpression(exp.EqAnyOp, ident, exp.NewArrayExpression(inVals)),
+		},
 		{Ex: ident.In(inVals), Expected: exp.NewBooleanExpression(exp.InOp, ident, inVals)},
 		{Ex: ident.NotIn(inVals), Expected: exp.NewBooleanExpression(exp.NotInOp, ident, inVals)},
 		{Ex: ident.Is(true), Expected: exp.NewBooleanExpression(exp.IsOp, ident, true)},
@@ -17,6 +17,13 @@ type (
 )
 
 func NewInsertExpression(rows ...any) (insertExpression InsertExpression, err error) {
+	return NewInsertExpressionWithOptions(nil, rows...)
+}
+
+// Creates an InsertExpression mapping struct rows to columns with opts, see NewRecordFromStructWithOptions
+func NewInsertExpressionWithOptions(
+	opts *ColumnMapOptions, rows ...any,
+) (insertExpression InsertExpression, err error) {
 	switch len(rows) {
 	case 0:
 		return new(insert), nil
@@ -27,13 +34,13 @@ func NewInsertExpression(rows ...any) (insertExpression InsertExpression, err er
 			for i := 0; i < val.Len(); i++ {
 				vals = append(vals, val.Index(i).Interface())
 			}
-			return NewInsertExpression(vals...)
+			return NewInsertExpressionWithOptions(opts, vals...)
 		}
 		if ae, ok := rows[0].(AppendableExpression); ok {
 			return &insert{from: ae}, nil
 		}
 	}
-	return newInsert(rows...)
+	return newInsert(opts, rows...)
 }
 
 func (i *insert) Expression() Expression {
@@ -81,13 +88,13 @@ func (i *insert) SetVals(vals [][]any) InsertExpression {
 }
 
 // parses the rows gathering and sorting unique columns and values for each record
-func newInsert(rows ...any) (insertExp InsertExpression, err error) {
+func newInsert(opts *ColumnMapOptions, rows ...any) (insertExp InsertExpression, err error) {
 	var mapKeys util.ValueSlice
 	rowValue := reflect.Indirect(reflect.ValueOf(rows[0]))
 	rowType := rowValue.Type()
 	rowKind := rowValue.Kind()
 	if rowKind == reflect.Struct {
-		return createStructSliceInsert(rows...)
+		return createStructSliceInsert(opts, rows...)
 	}
 	vals := make([][]any, 0, len(rows))
 	var columns ColumnListExpression
@@ -133,7 +140,7 @@ func newInsert(rows ...any) (insertExp InsertExpression, err error) {
 	return &insert{cols: columns, vals: vals}, nil
 }
 
-func createStructSliceInsert(rows ...any) (insertExp InsertExpression, err error) {
+func createStructSliceInsert(opts *ColumnMapOptions, rows ...any) (insertExp InsertExpression, err error) {
 	rowValue := reflect.Indirect(reflect.ValueOf(rows[0]))
 	rowType := rowValue.Type()
 	recordRows := make([]any, 0, len(rows))
@@ -146,18 +153,18 @@ func createStructSliceInsert(rows ...any) (insertExp InsertExpression, err error
 			)
 		}
 		newRowValue := reflect.Indirect(reflect.ValueOf(row))
-		record, err := getFieldsValuesFromStruct(newRowValue)
+		record, err := getFieldsValuesFromStruct(newRowValue, opts)
 		if err != nil {
 			return nil, err
 		}
 		recordRows = append(recordRows, record)
 	}
-	return newInsert(recordRows...)
+	return newInsert(opts, recordRows...)
 }
 
-func getFieldsValuesFromStruct(value reflect.Value) (row Record, err error) {
+func getFieldsValuesFromStruct(value reflect.Value, opts *ColumnMapOptions) (row Record, err error) {
 	if value.IsValid() {
-		return NewRecordFromStruct(value.Interface(), true, false)
+		return NewRecordFromStructWithOptions(value.Interface(), true, false, opts)
 	}
 	return
 }
@@ -1,5 +1,7 @@
 package exp
 
+import "time"
+
 type (
 	InsertClauses interface {
 		CommonTables() []CommonTableExpression
@@ -39,6 +41,9 @@ type (
 
 		OnConflict() ConflictExpression
 		SetOnConflict(expression ConflictExpression) InsertClauses
+
+		Timeout() time.Duration
+		SetTimeout(timeout time.Duration) InsertClauses
 	}
 	insertClauses struct {
 		commonTables []CommonTableExpression
@@ -50,6 +55,7 @@ type (
 		values       [][]any
 		from         AppendableExpression
 		conflict     ConflictExpression
+		timeout      time.Duration
 	}
 )
 
@@ -72,6 +78,7 @@ func (ic *insertClauses) clone() *insertClauses {
 		values:       ic.values,
 		from:         ic.from,
 		conflict:     ic.conflict,
+		timeout:      ic.timeout,
 	}
 }
 
@@ -203,3 +210,13 @@ func (ic *insertClauses) SetOnConflict(expression ConflictExpression) InsertClau
 	ret.conflict = expression
 	return ret
 }
+
+func (ic *insertClauses) Timeout() time.Duration {
+	return ic.timeout
+}
+
+func (ic *insertClauses) SetTimeout(timeout time.Duration) InsertClauses {
+	ret := ic.clone()
+	ret.timeout = timeout
+	return ret
+}
@@ -2,6 +2,7 @@ package exp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/stretchr/testify/suite"
@@ -240,3 +241,21 @@ func (ics *insertClausesSuite) TestSetReturning() {
 
 	ics.Equal(cl2, c2.Returning())
 }
+
+func (ics *insertClausesSuite) TestTimeout() {
+	c := exp.NewInsertClauses()
+	c2 := c.SetTimeout(time.Second)
+
+	ics.Zero(c.Timeout())
+
+	ics.Equal(time.Second, c2.Timeout())
+}
+
+func (ics *insertClausesSuite) TestSetTimeout() {
+	c := exp.NewInsertClauses().SetTimeout(time.Second)
+	c2 := c.SetTimeout(time.Minute)
+
+	ics.Equal(time.Second, c.Timeout())
+
+	ics.Equal(time.Minute, c2.Timeout())
+}
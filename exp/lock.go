@@ -1,5 +1,7 @@
 package exp
 
+import "fmt"
+
 type (
 	LockStrength int
 	WaitOption   int
@@ -27,6 +29,34 @@ const (
 	SkipLocked
 )
 
+func (ls LockStrength) String() string {
+	switch ls {
+	case ForNolock:
+		return "NO LOCK"
+	case ForUpdate:
+		return "FOR UPDATE"
+	case ForNoKeyUpdate:
+		return "FOR NO KEY UPDATE"
+	case ForShare:
+		return "FOR SHARE"
+	case ForKeyShare:
+		return "FOR KEY SHARE"
+	}
+	return fmt.Sprintf("%d", ls)
+}
+
+func (wo WaitOption) String() string {
+	switch wo {
+	case Wait:
+		return "WAIT"
+	case NoWait:
+		return "NOWAIT"
+	case SkipLocked:
+		return "SKIP LOCKED"
+	}
+	return fmt.Sprintf("%d", wo)
+}
+
 func NewLock(strength LockStrength, option WaitOption, of ...IdentifierExpression) Lock {
 	return lock{
 		strength:   strength,
@@ -10,6 +10,9 @@ import (
 // Alternative to writing map[string]interface{}. Can be used for Inserts, Updates or Deletes
 type Record map[string]any
 
+// Options used to map the fields of a struct to columns, see NewRecordFromStructWithOptions
+type ColumnMapOptions = util.ColumnMapOptions
+
 func (r Record) Cols() []string {
 	cols := make([]string, 0, len(r))
 	for col := range r {
@@ -20,9 +23,14 @@ func (r Record) Cols() []string {
 }
 
 func NewRecordFromStruct(i any, forInsert, forUpdate bool) (r Record, err error) {
+	return NewRecordFromStructWithOptions(i, forInsert, forUpdate, nil)
+}
+
+// Creates a Record from a struct mapped with opts, the package level settings are used when opts is nil
+func NewRecordFromStructWithOptions(i any, forInsert, forUpdate bool, opts *ColumnMapOptions) (r Record, err error) {
 	value := reflect.ValueOf(i)
 	if value.IsValid() {
-		cm, err := util.GetColumnMap(value.Interface())
+		cm, err := util.GetColumnMapWithOptions(value.Interface(), opts)
 		if err != nil {
 			return nil, err
 		}
@@ -32,6 +40,9 @@ func NewRecordFromStruct(i any, forInsert, forUpdate bool) (r Record, err error)
 			f := cm[col]
 			if !shouldSkipField(f, forInsert, forUpdate) {
 				if ok, fieldVal := getFieldValue(value, f); ok {
+					if fieldVal, err = convertFieldValue(f, fieldVal); err != nil {
+						return nil, err
+					}
 					r[f.ColumnName] = fieldVal
 				}
 			}
@@ -57,3 +68,23 @@ func getFieldValue(val reflect.Value, f util.ColumnData) (ok bool, fieldVal any)
 		return true, reflect.Zero(f.GoType).Interface()
 	}
 }
+
+// Converts the value of a field with a converter, nil pointers, maps and slices are stored as NULL
+func convertFieldValue(f util.ColumnData, fieldVal any) (any, error) {
+	if _, ok := fieldVal.(Expression); ok {
+		return fieldVal, nil
+	}
+	c, err := ColumnConverter(f)
+	if c == nil || err != nil {
+		return fieldVal, err
+	}
+	switch v := reflect.ValueOf(fieldVal); v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	return c.ToDB(fieldVal)
+}
@@ -1,5 +1,7 @@
 package exp
 
+import "time"
+
 type (
 	SelectClauses interface {
 		HasSources() bool
@@ -64,23 +66,31 @@ type (
 		SetWindows(ws []WindowExpression) SelectClauses
 		WindowsAppend(ws ...WindowExpression) SelectClauses
 		ClearWindows() SelectClauses
+
+		Timeout() time.Duration
+		SetTimeout(timeout time.Duration) SelectClauses
+
+		OptimizerHints() []string
+		OptimizerHintsAppend(hints ...string) SelectClauses
 	}
 	selectClauses struct {
-		commonTables  []CommonTableExpression
-		selectColumns ColumnListExpression
-		distinct      ColumnListExpression
-		from          ColumnListExpression
-		joins         JoinExpressions
-		where         ExpressionList
-		alias         IdentifierExpression
-		groupBy       ColumnListExpression
-		having        ExpressionList
-		order         ColumnListExpression
-		limit         any
-		offset        uint
-		compounds     []CompoundExpression
-		lock          Lock
-		windows       []WindowExpression
+		commonTables   []CommonTableExpression
+		selectColumns  ColumnListExpression
+		distinct       ColumnListExpression
+		from           ColumnListExpression
+		joins          JoinExpressions
+		where          ExpressionList
+		alias          IdentifierExpression
+		groupBy        ColumnListExpression
+		having         ExpressionList
+		order          ColumnListExpression
+		limit          any
+		offset         uint
+		compounds      []CompoundExpression
+		lock           Lock
+		windows        []WindowExpression
+		timeout        time.Duration
+		optimizerHints []string
 	}
 )
 
@@ -109,21 +119,23 @@ func (c *selectClauses) IsDefaultSelect() bool {
 
 func (c *selectClauses) clone() *selectClauses {
 	return &selectClauses{
-		commonTables:  c.commonTables,
-		selectColumns: c.selectColumns,
-		distinct:      c.distinct,
-		from:          c.from,
-		joins:         c.joins[0:len(c.joins):len(c.joins)],
-		where:         c.where,
-		alias:         c.alias,
-		groupBy:       c.groupBy,
-		having:        c.having,
-		order:         c.order,
-		limit:         c.limit,
-		offset:        c.offset,
-		compounds:     c.compounds,
-		lock:          c.lock,
-		windows:       c.windows,
+		commonTables:   c.commonTables,
+		selectColumns:  c.selectColumns,
+		distinct:       c.distinct,
+		from:           c.from,
+		joins:          c.joins[0:len(c.joins):len(c.joins)],
+		where:          c.where,
+		alias:          c.alias,
+		groupBy:        c.groupBy,
+		having:         c.having,
+		order:          c.order,
+		limit:          c.limit,
+		offset:         c.offset,
+		compounds:      c.compounds,
+		lock:           c.lock,
+		windows:        c.windows,
+		timeout:        c.timeout,
+		optimizerHints: c.optimizerHints,
 	}
 }
 
@@ -377,3 +389,23 @@ func (c *selectClauses) ClearWindows() SelectClauses {
 	ret.windows = nil
 	return ret
 }
+
+func (c *selectClauses) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c *selectClauses) SetTimeout(timeout time.Duration) SelectClauses {
+	ret := c.clone()
+	ret.timeout = timeout
+	return ret
+}
+
+func (c *selectClauses) OptimizerHints() []string {
+	return c.optimizerHints
+}
+
+func (c *selectClauses) OptimizerHintsAppend(hints ...string) SelectClauses {
+	ret := c.clone()
+	ret.optimizerHints = append(ret.optimizerHints[0:len(ret.optimizerHints):len(ret.optimizerHints)], hints...)
+	return ret
+}
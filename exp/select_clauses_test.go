@@ -2,6 +2,7 @@ package exp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/stretchr/testify/suite"
@@ -576,3 +577,42 @@ func (scs *selectClausesSuite) TestAddCommonTablesAppend() {
 
 	scs.Equal([]exp.CommonTableExpression{cte, cte2}, c2.CommonTables())
 }
+
+func (scs *selectClausesSuite) TestTimeout() {
+	c := exp.NewSelectClauses()
+	c2 := c.SetTimeout(time.Second)
+
+	scs.Zero(c.Timeout())
+
+	scs.Equal(time.Second, c2.Timeout())
+}
+
+func (scs *selectClausesSuite) TestSetTimeout() {
+	c := exp.NewSelectClauses().SetTimeout(time.Second)
+	c2 := c.SetTimeout(time.Minute)
+
+	scs.Equal(time.Second, c.Timeout())
+
+	scs.Equal(time.Minute, c2.Timeout())
+}
+
+func (scs *selectClausesSuite) TestOptimizerHints() {
+	c := exp.NewSelectClauses()
+	c2 := c.OptimizerHintsAppend("NO_ICP(t1)")
+
+	scs.Nil(c.OptimizerHints())
+
+	scs.Equal([]string{"NO_ICP(t1)"}, c2.OptimizerHints())
+}
+
+func (scs *selectClausesSuite) TestOptimizerHintsAppend() {
+	c := exp.NewSelectClauses().OptimizerHintsAppend("NO_ICP(t1)")
+	c2 := c.OptimizerHintsAppend("BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)")
+	c3 := c.OptimizerHintsAppend("MRR(t1)")
+
+	scs.Equal([]string{"NO_ICP(t1)"}, c.OptimizerHints())
+
+	scs.Equal([]string{"NO_ICP(t1)", "BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)"}, c2.OptimizerHints())
+
+	scs.Equal([]string{"NO_ICP(t1)", "MRR(t1)"}, c3.OptimizerHints())
+}
@@ -0,0 +1,95 @@
+package exp
+
+import (
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+)
+
+type (
+	tableFunc struct {
+		name           string
+		args           []any
+		alias          IdentifierExpression
+		cols           []TableFunctionColumn
+		withOrdinality bool
+	}
+	tableFuncColumn struct {
+		name    string
+		sqlType string
+	}
+)
+
+// The error set when a table function is appended without a dialect, table functions are generated by the SQL
+// generator of the statement they are used in
+var ErrTableFunctionWithoutDialect = errors.New("a table function can only be generated as part of a statement")
+
+// Creates a function returning rows that can be used as a table source (e.g. in From, joins and Lateral)
+//
+//	NewTableFunctionExpression("generate_series", 1, 3) -> generate_series(1, 3)
+//	NewTableFunctionExpression("unnest", I("tags")).WithOrdinality().As("t", "tag", "n")
+//	// unnest("tags") WITH ORDINALITY AS "t"("tag", "n")
+func NewTableFunctionExpression(name string, args ...any) TableFunctionExpression {
+	return tableFunc{name: name, args: args}
+}
+
+func (tf tableFunc) Clone() Expression {
+	ret := tf
+	ret.args = append([]any(nil), tf.args...)
+	ret.cols = append([]TableFunctionColumn(nil), tf.cols...)
+	return ret
+}
+
+func (tf tableFunc) Expression() Expression { return tf }
+
+// Table functions are rendered by the SQL generator of the statement, appending one directly sets
+// ErrTableFunctionWithoutDialect on the builder
+func (tf tableFunc) AppendSQL(b sb.SQLBuilder) { b.SetError(ErrTableFunctionWithoutDialect) }
+
+func (tf tableFunc) GetAs() IdentifierExpression    { return tf.alias }
+func (tf tableFunc) ReturnsColumns() bool           { return true }
+func (tf tableFunc) Name() string                   { return tf.name }
+func (tf tableFunc) Args() []any                    { return tf.args }
+func (tf tableFunc) Columns() []TableFunctionColumn { return tf.cols }
+func (tf tableFunc) IsWithOrdinality() bool         { return tf.withOrdinality }
+
+func (tf tableFunc) HasColumnDefinitions() bool {
+	for _, col := range tf.cols {
+		if col.Type() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Aliases the function result and names its columns, replacing any previously defined columns
+//
+//	NewTableFunctionExpression("generate_series", 1, 3).As("s", "n") -> generate_series(1, 3) AS "s"("n")
+func (tf tableFunc) As(alias string, cols ...string) TableFunctionExpression {
+	ret := tf
+	ret.alias = NewIdentifierExpression("", alias, "")
+	ret.cols = make([]TableFunctionColumn, 0, len(cols))
+	for _, col := range cols {
+		ret.cols = append(ret.cols, tableFuncColumn{name: col})
+	}
+	return ret
+}
+
+// Appends a column with a type to the column definition list
+//
+//	NewTableFunctionExpression("jsonb_to_recordset", doc).As("r").Column("a", "int").Column("b", "text")
+//	// jsonb_to_recordset(...) AS "r"("a" int, "b" text)
+func (tf tableFunc) Column(name, sqlType string) TableFunctionExpression {
+	ret := tf
+	ret.cols = append(append(make([]TableFunctionColumn, 0, len(tf.cols)+1), tf.cols...),
+		tableFuncColumn{name: name, sqlType: sqlType})
+	return ret
+}
+
+func (tf tableFunc) WithOrdinality() TableFunctionExpression {
+	ret := tf
+	ret.withOrdinality = true
+	return ret
+}
+
+func (tfc tableFuncColumn) Name() string { return tfc.name }
+func (tfc tableFuncColumn) Type() string { return tfc.sqlType }
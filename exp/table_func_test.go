@@ -0,0 +1,68 @@
+package exp_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+	"github.com/stretchr/testify/suite"
+)
+
+type tableFunctionExpressionSuite struct {
+	suite.Suite
+}
+
+func TestTableFunctionExpressionSuite(t *testing.T) {
+	suite.Run(t, &tableFunctionExpressionSuite{})
+}
+
+func (tfs *tableFunctionExpressionSuite) TestClone() {
+	tf := exp.NewTableFunctionExpression("generate_series", 1, 3).WithOrdinality().As("s", "n", "i")
+	tfs.Equal(tf, tf.Clone())
+}
+
+func (tfs *tableFunctionExpressionSuite) TestExpression() {
+	tf := exp.NewTableFunctionExpression("generate_series", 1, 3)
+	tfs.Equal(tf, tf.Expression())
+}
+
+func (tfs *tableFunctionExpressionSuite) TestAs() {
+	tf := exp.NewTableFunctionExpression("generate_series", 1, 3)
+	tfs.Equal("generate_series", tf.Name())
+	tfs.Equal([]any{1, 3}, tf.Args())
+	tfs.Nil(tf.GetAs())
+	tfs.Empty(tf.Columns())
+	tfs.True(tf.ReturnsColumns())
+
+	aliased := tf.As("s", "n")
+	tfs.Nil(tf.GetAs())
+	tfs.Equal(exp.NewIdentifierExpression("", "s", ""), aliased.GetAs())
+	tfs.Len(aliased.Columns(), 1)
+	tfs.Equal("n", aliased.Columns()[0].Name())
+	tfs.Equal("", aliased.Columns()[0].Type())
+	tfs.False(aliased.HasColumnDefinitions())
+	tfs.Empty(aliased.As("t").Columns())
+}
+
+func (tfs *tableFunctionExpressionSuite) TestColumn() {
+	tf := exp.NewTableFunctionExpression("jsonb_to_recordset", "[]").As("r")
+	withCols := tf.Column("a", "int").Column("b", "text")
+	tfs.Empty(tf.Columns())
+	tfs.True(withCols.HasColumnDefinitions())
+	tfs.Len(withCols.Columns(), 2)
+	tfs.Equal("b", withCols.Columns()[1].Name())
+	tfs.Equal("text", withCols.Columns()[1].Type())
+}
+
+func (tfs *tableFunctionExpressionSuite) TestWithOrdinality() {
+	tf := exp.NewTableFunctionExpression("unnest", exp.NewIdentifierExpression("", "", "tags"))
+	tfs.False(tf.IsWithOrdinality())
+	tfs.True(tf.WithOrdinality().IsWithOrdinality())
+}
+
+func (tfs *tableFunctionExpressionSuite) TestAppendSQL() {
+	b := sb.NewSQLBuilder(false)
+	exp.NewTableFunctionExpression("generate_series", 1, 3).AppendSQL(b)
+	_, _, err := b.ToSQL()
+	tfs.Equal(exp.ErrTableFunctionWithoutDialect, err)
+}
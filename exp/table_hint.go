@@ -0,0 +1,93 @@
+package exp
+
+import "fmt"
+
+type (
+	tableHint struct {
+		hintType TableHintType
+		values   []string
+	}
+	tableHintExpression struct {
+		table Expression
+		hints []TableHint
+	}
+)
+
+const (
+	UseIndexHint TableHintType = iota
+	ForceIndexHint
+	IgnoreIndexHint
+	WithTableHint
+)
+
+func (tht TableHintType) String() string {
+	switch tht {
+	case UseIndexHint:
+		return "USE INDEX"
+	case ForceIndexHint:
+		return "FORCE INDEX"
+	case IgnoreIndexHint:
+		return "IGNORE INDEX"
+	case WithTableHint:
+		return "WITH"
+	}
+	return fmt.Sprintf("%d", tht)
+}
+
+// Creates a new TableHint, for index hints values are the index names, for a WithTableHint values are the hints.
+func NewTableHint(hintType TableHintType, values ...string) TableHint {
+	return tableHint{hintType: hintType, values: values}
+}
+
+func (th tableHint) Type() TableHintType {
+	return th.hintType
+}
+
+func (th tableHint) Values() []string {
+	return th.values
+}
+
+// Creates a new TableHintExpression for the table reference and hints
+//
+//	NewTableHintExpression(I("a"), NewTableHint(UseIndexHint, "idx_b")) -> `a` USE INDEX (`idx_b`)
+func NewTableHintExpression(table Expression, hints ...TableHint) TableHintExpression {
+	return tableHintExpression{table: table, hints: hints}
+}
+
+func (the tableHintExpression) Clone() Expression {
+	return NewTableHintExpression(the.table.Clone(), the.hints...)
+}
+
+func (the tableHintExpression) Expression() Expression {
+	return the
+}
+
+func (the tableHintExpression) Table() Expression {
+	return the.table
+}
+
+func (the tableHintExpression) Hints() []TableHint {
+	return the.hints
+}
+
+func (the tableHintExpression) UseIndex(indexes ...string) TableHintExpression {
+	return the.hintsAppend(NewTableHint(UseIndexHint, indexes...))
+}
+
+func (the tableHintExpression) ForceIndex(indexes ...string) TableHintExpression {
+	return the.hintsAppend(NewTableHint(ForceIndexHint, indexes...))
+}
+
+func (the tableHintExpression) IgnoreIndex(indexes ...string) TableHintExpression {
+	return the.hintsAppend(NewTableHint(IgnoreIndexHint, indexes...))
+}
+
+func (the tableHintExpression) WithTableHints(hints ...string) TableHintExpression {
+	return the.hintsAppend(NewTableHint(WithTableHint, hints...))
+}
+
+func (the tableHintExpression) hintsAppend(hint TableHint) TableHintExpression {
+	hints := make([]TableHint, 0, len(the.hints)+1)
+	hints = append(hints, the.hints...)
+	return NewTableHintExpression(the.table, append(hints, hint)...)
+}
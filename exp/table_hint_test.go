@@ -0,0 +1,73 @@
+package exp_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/stretchr/testify/suite"
+)
+
+type tableHintExpressionSuite struct {
+	suite.Suite
+}
+
+func TestTableHintExpressionSuite(t *testing.T) {
+	suite.Run(t, &tableHintExpressionSuite{})
+}
+
+func (thes *tableHintExpressionSuite) TestClone() {
+	the := exp.NewTableHintExpression(exp.NewIdentifierExpression("", "a", ""), exp.NewTableHint(exp.UseIndexHint, "b"))
+	thes.Equal(
+		exp.NewTableHintExpression(exp.NewIdentifierExpression("", "a", ""), exp.NewTableHint(exp.UseIndexHint, "b")),
+		the.Clone(),
+	)
+}
+
+func (thes *tableHintExpressionSuite) TestExpression() {
+	the := exp.NewTableHintExpression(exp.NewIdentifierExpression("", "a", ""))
+	thes.Equal(the, the.Expression())
+}
+
+func (thes *tableHintExpressionSuite) TestTable() {
+	ident := exp.NewIdentifierExpression("", "a", "")
+	thes.Equal(ident, exp.NewTableHintExpression(ident).Table())
+
+	aliased := ident.As("b")
+	thes.Equal(aliased, aliased.UseIndex("idx").Table())
+}
+
+func (thes *tableHintExpressionSuite) TestHints() {
+	ident := exp.NewIdentifierExpression("", "a", "")
+	the := ident.UseIndex("idx_a", "idx_b")
+	the2 := the.IgnoreIndex("idx_c")
+	the3 := the.ForceIndex("idx_d").WithTableHints("NOLOCK")
+
+	thes.Equal([]exp.TableHint{exp.NewTableHint(exp.UseIndexHint, "idx_a", "idx_b")}, the.Hints())
+	thes.Equal([]exp.TableHint{
+		exp.NewTableHint(exp.UseIndexHint, "idx_a", "idx_b"),
+		exp.NewTableHint(exp.IgnoreIndexHint, "idx_c"),
+	}, the2.Hints())
+	thes.Equal([]exp.TableHint{
+		exp.NewTableHint(exp.UseIndexHint, "idx_a", "idx_b"),
+		exp.NewTableHint(exp.ForceIndexHint, "idx_d"),
+		exp.NewTableHint(exp.WithTableHint, "NOLOCK"),
+	}, the3.Hints())
+
+	thes.Equal([]exp.TableHint{exp.NewTableHint(exp.ForceIndexHint, "idx")}, ident.ForceIndex("idx").Hints())
+	thes.Equal([]exp.TableHint{exp.NewTableHint(exp.IgnoreIndexHint, "idx")}, ident.IgnoreIndex("idx").Hints())
+	thes.Equal([]exp.TableHint{exp.NewTableHint(exp.WithTableHint, "NOLOCK")}, ident.WithTableHints("NOLOCK").Hints())
+}
+
+func (thes *tableHintExpressionSuite) TestTableHint() {
+	th := exp.NewTableHint(exp.UseIndexHint, "idx_a", "idx_b")
+	thes.Equal(exp.UseIndexHint, th.Type())
+	thes.Equal([]string{"idx_a", "idx_b"}, th.Values())
+}
+
+func (thes *tableHintExpressionSuite) TestTableHintType_String() {
+	thes.Equal("USE INDEX", exp.UseIndexHint.String())
+	thes.Equal("FORCE INDEX", exp.ForceIndexHint.String())
+	thes.Equal("IGNORE INDEX", exp.IgnoreIndexHint.String())
+	thes.Equal("WITH", exp.WithTableHint.String())
+	thes.Equal("10", exp.TableHintType(10).String())
+}
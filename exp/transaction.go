@@ -0,0 +1,18 @@
+package exp
+
+import "database/sql"
+
+// Options to use when starting a transaction
+type TransactionOptions struct {
+	// The isolation level of the transaction, sql.LevelDefault keeps the default of the database
+	Isolation sql.IsolationLevel
+	// Set to true to start a READ ONLY transaction
+	ReadOnly bool
+	// Set to true to start a DEFERRABLE transaction (e.g. postgres SERIALIZABLE READ ONLY DEFERRABLE)
+	Deferrable bool
+}
+
+// Returns true if no options have been set and the transaction can be started with the defaults of the database
+func (to TransactionOptions) IsEmpty() bool {
+	return to.Isolation == sql.LevelDefault && !to.ReadOnly && !to.Deferrable
+}
@@ -1,5 +1,7 @@
 package exp
 
+import "time"
+
 type (
 	TruncateClauses interface {
 		HasTable() bool
@@ -10,10 +12,14 @@ type (
 
 		Options() TruncateOptions
 		SetOptions(opts TruncateOptions) TruncateClauses
+
+		Timeout() time.Duration
+		SetTimeout(timeout time.Duration) TruncateClauses
 	}
 	truncateClauses struct {
 		tables  ColumnListExpression
 		options TruncateOptions
+		timeout time.Duration
 	}
 )
 
@@ -27,7 +33,9 @@ func (tc *truncateClauses) HasTable() bool {
 
 func (tc *truncateClauses) clone() *truncateClauses {
 	return &truncateClauses{
-		tables: tc.tables,
+		tables:  tc.tables,
+		options: tc.options,
+		timeout: tc.timeout,
 	}
 }
 
@@ -48,3 +56,13 @@ func (tc *truncateClauses) SetOptions(opts TruncateOptions) TruncateClauses {
 	ret.options = opts
 	return ret
 }
+
+func (tc *truncateClauses) Timeout() time.Duration {
+	return tc.timeout
+}
+
+func (tc *truncateClauses) SetTimeout(timeout time.Duration) TruncateClauses {
+	ret := tc.clone()
+	ret.timeout = timeout
+	return ret
+}
@@ -2,6 +2,7 @@ package exp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/stretchr/testify/suite"
@@ -66,3 +67,28 @@ func (tcs *truncateClausesSuite) TestSetOptions() {
 
 	tcs.Equal(opts2, c2.Options())
 }
+
+func (tcs *truncateClausesSuite) TestSetTable_keepsOptions() {
+	opts := exp.TruncateOptions{Cascade: true}
+	c := exp.NewTruncateClauses().SetOptions(opts).SetTable(exp.NewColumnListExpression("a"))
+
+	tcs.Equal(opts, c.Options())
+}
+
+func (tcs *truncateClausesSuite) TestTimeout() {
+	c := exp.NewTruncateClauses()
+	c2 := c.SetTimeout(time.Second)
+
+	tcs.Zero(c.Timeout())
+
+	tcs.Equal(time.Second, c2.Timeout())
+}
+
+func (tcs *truncateClausesSuite) TestSetTimeout() {
+	c := exp.NewTruncateClauses().SetTimeout(time.Second)
+	c2 := c.SetTimeout(time.Minute)
+
+	tcs.Equal(time.Second, c.Timeout())
+
+	tcs.Equal(time.Minute, c2.Timeout())
+}
@@ -20,6 +20,11 @@ func set(col IdentifierExpression, val any) UpdateExpression {
 }
 
 func NewUpdateExpressions(update any) (updates []UpdateExpression, err error) {
+	return NewUpdateExpressionsWithOptions(nil, update)
+}
+
+// Creates the UpdateExpressions of update mapping structs to columns with opts, see NewRecordFromStructWithOptions
+func NewUpdateExpressionsWithOptions(opts *ColumnMapOptions, update any) (updates []UpdateExpression, err error) {
 	if u, ok := update.(UpdateExpression); ok {
 		updates = append(updates, u)
 		return updates, nil
@@ -33,15 +38,15 @@ func NewUpdateExpressions(update any) (updates []UpdateExpression, err error) {
 			updates = append(updates, ParseIdentifier(key.String()).Set(updateValue.MapIndex(key).Interface()))
 		}
 	case reflect.Struct:
-		return getUpdateExpressionsStruct(updateValue)
+		return getUpdateExpressionsStruct(updateValue, opts)
 	default:
 		return nil, errors.New("unsupported update interface type %+v", updateValue.Type())
 	}
 	return updates, nil
 }
 
-func getUpdateExpressionsStruct(value reflect.Value) (updates []UpdateExpression, err error) {
-	r, err := NewRecordFromStruct(value.Interface(), false, true)
+func getUpdateExpressionsStruct(value reflect.Value, opts *ColumnMapOptions) (updates []UpdateExpression, err error) {
+	r, err := NewRecordFromStructWithOptions(value.Interface(), false, true, opts)
 	if err != nil {
 		return updates, err
 	}
@@ -1,5 +1,7 @@
 package exp
 
+import "time"
+
 type (
 	UpdateClauses interface {
 		HasTable() bool
@@ -38,16 +40,24 @@ type (
 		Returning() ColumnListExpression
 		HasReturning() bool
 		SetReturning(cl ColumnListExpression) UpdateClauses
+
+		Timeout() time.Duration
+		SetTimeout(timeout time.Duration) UpdateClauses
+
+		OptimizerHints() []string
+		OptimizerHintsAppend(hints ...string) UpdateClauses
 	}
 	updateClauses struct {
-		commonTables []CommonTableExpression
-		table        Expression
-		setValues    any
-		from         ColumnListExpression
-		where        ExpressionList
-		order        ColumnListExpression
-		limit        any
-		returning    ColumnListExpression
+		commonTables   []CommonTableExpression
+		table          Expression
+		setValues      any
+		from           ColumnListExpression
+		where          ExpressionList
+		order          ColumnListExpression
+		limit          any
+		returning      ColumnListExpression
+		timeout        time.Duration
+		optimizerHints []string
 	}
 )
 
@@ -61,14 +71,16 @@ func (uc *updateClauses) HasTable() bool {
 
 func (uc *updateClauses) clone() *updateClauses {
 	return &updateClauses{
-		commonTables: uc.commonTables,
-		table:        uc.table,
-		setValues:    uc.setValues,
-		from:         uc.from,
-		where:        uc.where,
-		order:        uc.order,
-		limit:        uc.limit,
-		returning:    uc.returning,
+		commonTables:   uc.commonTables,
+		table:          uc.table,
+		setValues:      uc.setValues,
+		from:           uc.from,
+		where:          uc.where,
+		order:          uc.order,
+		limit:          uc.limit,
+		returning:      uc.returning,
+		timeout:        uc.timeout,
+		optimizerHints: uc.optimizerHints,
 	}
 }
 
@@ -214,3 +226,23 @@ func (uc *updateClauses) SetReturning(cl ColumnListExpression) UpdateClauses {
 	ret.returning = cl
 	return ret
 }
+
+func (uc *updateClauses) Timeout() time.Duration {
+	return uc.timeout
+}
+
+func (uc *updateClauses) SetTimeout(timeout time.Duration) UpdateClauses {
+	ret := uc.clone()
+	ret.timeout = timeout
+	return ret
+}
+
+func (uc *updateClauses) OptimizerHints() []string {
+	return uc.optimizerHints
+}
+
+func (uc *updateClauses) OptimizerHintsAppend(hints ...string) UpdateClauses {
+	ret := uc.clone()
+	ret.optimizerHints = append(ret.optimizerHints[0:len(ret.optimizerHints):len(ret.optimizerHints)], hints...)
+	return ret
+}
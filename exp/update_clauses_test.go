@@ -2,6 +2,7 @@ package exp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/stretchr/testify/suite"
@@ -296,3 +297,42 @@ func (ucs *updateClausesSuite) TestSetReturning() {
 
 	ucs.Equal(cl2, c2.Returning())
 }
+
+func (ucs *updateClausesSuite) TestTimeout() {
+	c := exp.NewUpdateClauses()
+	c2 := c.SetTimeout(time.Second)
+
+	ucs.Zero(c.Timeout())
+
+	ucs.Equal(time.Second, c2.Timeout())
+}
+
+func (ucs *updateClausesSuite) TestSetTimeout() {
+	c := exp.NewUpdateClauses().SetTimeout(time.Second)
+	c2 := c.SetTimeout(time.Minute)
+
+	ucs.Equal(time.Second, c.Timeout())
+
+	ucs.Equal(time.Minute, c2.Timeout())
+}
+
+func (ucs *updateClausesSuite) TestOptimizerHints() {
+	c := exp.NewUpdateClauses()
+	c2 := c.OptimizerHintsAppend("NO_ICP(t1)")
+
+	ucs.Nil(c.OptimizerHints())
+
+	ucs.Equal([]string{"NO_ICP(t1)"}, c2.OptimizerHints())
+}
+
+func (ucs *updateClausesSuite) TestOptimizerHintsAppend() {
+	c := exp.NewUpdateClauses().OptimizerHintsAppend("NO_ICP(t1)")
+	c2 := c.OptimizerHintsAppend("BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)")
+	c3 := c.OptimizerHintsAppend("MRR(t1)")
+
+	ucs.Equal([]string{"NO_ICP(t1)"}, c.OptimizerHints())
+
+	ucs.Equal([]string{"NO_ICP(t1)", "BKA(t1)", "NO_RANGE_OPTIMIZATION(t1)"}, c2.OptimizerHints())
+
+	ucs.Equal([]string{"NO_ICP(t1)", "MRR(t1)"}, c3.OptimizerHints())
+}
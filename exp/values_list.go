@@ -0,0 +1,63 @@
+package exp
+
+import (
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+)
+
+type valuesList struct {
+	rows  [][]any
+	alias IdentifierExpression
+	cols  ColumnListExpression
+}
+
+// The error set when a VALUES list is appended without a dialect, VALUES lists are generated by the SQL generator of
+// the statement they are used in
+var ErrValuesListWithoutDialect = errors.New("a VALUES list can only be generated as part of a statement")
+
+// Creates a VALUES list that can be used as a table source (e.g. in From, joins, CTEs and InsertDataset#FromQuery)
+//
+//	NewValuesListExpression([][]any{{1, "a"}, {2, "b"}}) -> (VALUES (1, 'a'), (2, 'b'))
+func NewValuesListExpression(rows [][]any) ValuesListExpression {
+	return valuesList{rows: rows}
+}
+
+func (vl valuesList) Clone() Expression {
+	rows := make([][]any, 0, len(vl.rows))
+	for _, row := range vl.rows {
+		rows = append(rows, append([]any(nil), row...))
+	}
+	ret := valuesList{rows: rows, alias: vl.alias}
+	if vl.cols != nil {
+		ret.cols = vl.cols.Clone().(ColumnListExpression)
+	}
+	return ret
+}
+
+func (vl valuesList) Expression() Expression { return vl }
+
+// VALUES lists are rendered by the SQL generator of the statement, appending one directly sets
+// ErrValuesListWithoutDialect on the builder
+func (vl valuesList) AppendSQL(b sb.SQLBuilder)   { b.SetError(ErrValuesListWithoutDialect) }
+func (vl valuesList) GetAs() IdentifierExpression { return vl.alias }
+func (vl valuesList) ReturnsColumns() bool        { return true }
+func (vl valuesList) Rows() [][]any               { return vl.rows }
+func (vl valuesList) Cols() ColumnListExpression  { return vl.cols }
+func (vl valuesList) HasCols() bool               { return vl.cols != nil && !vl.cols.IsEmpty() }
+
+// Aliases the VALUES list and names its columns
+//
+//	NewValuesListExpression([][]any{{1, "a"}}).As("v", "id", "name") -> (VALUES (1, 'a')) AS "v"("id", "name")
+func (vl valuesList) As(alias string, cols ...string) ValuesListExpression {
+	ret := vl
+	ret.alias = NewIdentifierExpression("", alias, "")
+	ret.cols = nil
+	if len(cols) > 0 {
+		vals := make([]any, 0, len(cols))
+		for _, col := range cols {
+			vals = append(vals, NewIdentifierExpression("", "", col))
+		}
+		ret.cols = NewColumnListExpression(vals...)
+	}
+	return ret
+}
@@ -0,0 +1,52 @@
+package exp_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+	"github.com/stretchr/testify/suite"
+)
+
+type valuesListExpressionSuite struct {
+	suite.Suite
+}
+
+func TestValuesListExpressionSuite(t *testing.T) {
+	suite.Run(t, &valuesListExpressionSuite{})
+}
+
+func (vls *valuesListExpressionSuite) TestClone() {
+	vl := exp.NewValuesListExpression([][]any{{1, "a"}, {2, "b"}}).As("v", "id", "name")
+	vls.Equal(vl, vl.Clone())
+}
+
+func (vls *valuesListExpressionSuite) TestExpression() {
+	vl := exp.NewValuesListExpression([][]any{{1, "a"}})
+	vls.Equal(vl, vl.Expression())
+}
+
+func (vls *valuesListExpressionSuite) TestAs() {
+	vl := exp.NewValuesListExpression([][]any{{1, "a"}})
+	vls.Nil(vl.GetAs())
+	vls.False(vl.HasCols())
+	vls.True(vl.ReturnsColumns())
+
+	aliased := vl.As("v", "id", "name")
+	vls.Nil(vl.GetAs())
+	vls.Equal(exp.NewIdentifierExpression("", "v", ""), aliased.GetAs())
+	vls.True(aliased.HasCols())
+	vls.Equal(exp.NewColumnListExpression(
+		exp.NewIdentifierExpression("", "", "id"),
+		exp.NewIdentifierExpression("", "", "name"),
+	), aliased.Cols())
+	vls.Equal([][]any{{1, "a"}}, aliased.Rows())
+	vls.False(aliased.As("w").HasCols())
+}
+
+func (vls *valuesListExpressionSuite) TestAppendSQL() {
+	b := sb.NewSQLBuilder(false)
+	exp.NewValuesListExpression([][]any{{1}}).AppendSQL(b)
+	_, _, err := b.ToSQL()
+	vls.Equal(exp.ErrValuesListWithoutDialect, err)
+}
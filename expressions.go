@@ -13,6 +13,10 @@ type (
 	Vals       = exp.Vals
 	// Options to use when generating a TRUNCATE statement
 	TruncateOptions = exp.TruncateOptions
+	// Options to use when starting a transaction
+	TransactionOptions = exp.TransactionOptions
+	// Converts the values of struct fields to the values stored in their columns and back
+	Converter = exp.Converter
 )
 
 // emptyWindow is an empty WINDOW clause without name
@@ -257,6 +261,35 @@ func W(ws ...string) exp.WindowExpression {
 	}
 }
 
+// Creates a common table expression to be passed to WithCTE, the name and column names are quoted.
+//
+//	CTE("tree", sub) -> "tree" AS (...)
+//	CTE("tree", sub).Columns("id", "parent_id") -> "tree"("id", "parent_id") AS (...)
+//	CTE("tree", sub).Materialized() -> "tree" AS MATERIALIZED (...)
+//	CTE("tree", sub).NotMaterialized() -> "tree" AS NOT MATERIALIZED (...)
+//	CTE("tree", sub).Search(SearchDepthFirst("ordercol", "id")) -> "tree" AS (...) SEARCH DEPTH FIRST BY "id" SET "ordercol"
+//	CTE("tree", sub).Cycle(Cycle("is_cycle", "path", "id")) -> "tree" AS (...) CYCLE "id" SET "is_cycle" USING "path"
+func CTE(name string, subquery exp.Expression) exp.CommonTableExpression {
+	return exp.NewNamedCommonTableExpression(exp.NewIdentifierExpression("", name, ""), subquery)
+}
+
+// Creates a SEARCH DEPTH FIRST clause for a recursive CTE, see CTE
+func SearchDepthFirst(set string, by ...any) exp.CTESearch {
+	return exp.NewCTESearch(exp.DepthFirstSearch, exp.NewColumnListExpression(by...), C(set))
+}
+
+// Creates a SEARCH BREADTH FIRST clause for a recursive CTE, see CTE
+func SearchBreadthFirst(set string, by ...any) exp.CTESearch {
+	return exp.NewCTESearch(exp.BreadthFirstSearch, exp.NewColumnListExpression(by...), C(set))
+}
+
+// Creates a CYCLE clause for a recursive CTE, see CTE. The values of the mark column can be set with Mark
+//
+//	Cycle("is_cycle", "path", "id").Mark("Y", "N") -> CYCLE "id" SET "is_cycle" TO 'Y' DEFAULT 'N' USING "path"
+func Cycle(set, using string, cols ...any) exp.CTECycle {
+	return exp.NewCTECycle(exp.NewColumnListExpression(cols...), C(set), C(using))
+}
+
 // Creates a new ON clause to be used within a join
 //
 //	ds.Join(builder.T("my_table"), builder.On(
@@ -318,6 +351,48 @@ func Lateral(table exp.AppendableExpression) exp.LateralExpression {
 	return exp.NewLateralExpression(table)
 }
 
+// Creates a function returning rows that can be used as a table in From, joins and Lateral
+//
+//	TableFunc("generate_series", 1, 3).As("s", "n") -> generate_series(1, 3) AS "s"("n")
+//	TableFunc("unnest", I("tags")).WithOrdinality().As("t", "tag", "n") -> unnest("tags") WITH ORDINALITY AS "t"("tag", "n")
+//	TableFunc("jsonb_to_recordset", doc).As("r").Column("a", "int") -> jsonb_to_recordset(...) AS "r"("a" int)
+func TableFunc(name string, args ...any) exp.TableFunctionExpression {
+	return exp.NewTableFunctionExpression(name, args...)
+}
+
+// Creates a VALUES list that can be used as a table source in From, joins, CTEs and InsertDataset#FromQuery
+//
+//	Values([][]any{{1, "a"}, {2, "b"}}).As("v", "id", "name")
+//	// postgres: (VALUES (1, 'a'), (2, 'b')) AS "v"("id", "name")
+//	// mysql8:   (VALUES ROW(1, 'a'), ROW(2, 'b')) AS `v`(`id`, `name`)
+//	// mysql:    (SELECT 1 AS `id`, 'a' AS `name` UNION ALL SELECT 2, 'b') AS `v`
+func Values(rows [][]any) exp.ValuesListExpression {
+	return exp.NewValuesListExpression(rows)
+}
+
+// Creates an array from a slice, rendered as an ARRAY constructor when interpolated and passed as a single argument
+// when prepared. Arrays are not supported by dialects without arrays (e.g. mysql)
+//
+//	Array([]int{1, 2, 3}) -> ARRAY[1, 2, 3]
+//	C("tags").Contains(Array([]string{"a"})) -> ("tags" @> ARRAY['a'])
+func Array(values any) exp.ArrayExpression {
+	return exp.NewArrayExpression(values)
+}
+
+// Creates an array_length function call, dim is the dimension of the array
+//
+//	ArrayLength(C("tags"), 1) -> array_length("tags", 1)
+func ArrayLength(arr any, dim int) exp.SQLFunctionExpression {
+	return Func("array_length", arr, dim)
+}
+
+// Creates an unnest function call expanding arrays into rows, it can be used as a table in From, joins and Lateral
+//
+//	Unnest(C("tags")).As("t", "tag") -> unnest("tags") AS "t"("tag")
+func Unnest(arrays ...any) exp.TableFunctionExpression {
+	return TableFunc("unnest", arrays...)
+}
+
 // Create a new ANY comparison
 func Any(val any) exp.SQLFunctionExpression {
 	return Func("ANY ", val)
@@ -0,0 +1,33 @@
+package builder
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/Tooooommy/builder/v9/internal/sb"
+)
+
+// The fingerprint of a statement, statements with the same shape have the same fingerprint regardless of their
+// values. Fingerprints can be used to group statements in metrics and slow query dashboards.
+//
+//	SELECT * FROM "user" WHERE (("name" = ?) AND ("id" IN (...))) LIMIT ?
+type Fingerprint struct {
+	// The normalized SQL of the statement. Values are replaced with a ? placeholder, IN lists and the rows of
+	// multi-row INSERTs are collapsed to a single (...) marker.
+	SQL string
+	// The 64-bit FNV-1a hash of the normalized SQL as 16 hex characters
+	Hash string
+}
+
+// generates the fingerprint of the statement generated by gen
+func fingerprint(gen func(b sb.SQLBuilder)) (Fingerprint, error) {
+	b := sb.NewFingerprintSQLBuilder()
+	gen(b)
+	query, _, err := b.ToSQL()
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(query))
+	return Fingerprint{SQL: query, Hash: fmt.Sprintf("%016x", h.Sum64())}, nil
+}
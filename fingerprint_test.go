@@ -0,0 +1,100 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type fingerprintSuite struct {
+	suite.Suite
+}
+
+func (fs *fingerprintSuite) TestSelectDataset() {
+	ds := builder.From("items").Select("id", builder.COUNT("*").As("c"))
+
+	fp1, err := ds.Where(builder.C("name").Eq("Bob"), builder.C("id").In(1, 2)).Limit(10).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(
+		`SELECT "id", COUNT(*) AS "c" FROM "items" WHERE (("name" = ?) AND ("id" IN (...))) LIMIT ?`,
+		fp1.SQL,
+	)
+	fs.Len(fp1.Hash, 16)
+
+	fp2, err := ds.Where(builder.C("name").Eq("Sally"), builder.C("id").In(3, 4, 5, 6)).Limit(1).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(fp1, fp2)
+
+	// prepared datasets have the same fingerprint
+	fp3, err := ds.Where(builder.C("name").Eq("Jim"), builder.C("id").In(7)).Limit(2).Prepared(true).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(fp1, fp3)
+
+	fp4, err := ds.Where(builder.C("name").Neq("Bob"), builder.C("id").In(1, 2)).Limit(10).Fingerprint()
+	fs.NoError(err)
+	fs.NotEqual(fp1.Hash, fp4.Hash)
+}
+
+func (fs *fingerprintSuite) TestSelectDataset_subSelect() {
+	fp, err := builder.From("items").
+		Where(builder.C("user_id").In(builder.From("users").Select("id").Where(builder.C("age").Gt(18)))).
+		Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`SELECT * FROM "items" WHERE ("user_id" IN ((SELECT "id" FROM "users" WHERE ("age" > ?))))`, fp.SQL)
+}
+
+func (fs *fingerprintSuite) TestSelectDataset_dialect() {
+	fp, err := builder.Dialect("mysql").From("items").Where(builder.C("id").Eq(1)).Fingerprint()
+	fs.NoError(err)
+	fs.Equal("SELECT * FROM `items` WHERE (`id` = ?)", fp.SQL)
+
+	fp, err = builder.Dialect("postgres").From("items").Where(builder.C("id").Eq(1)).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`SELECT * FROM "items" WHERE ("id" = ?)`, fp.SQL)
+}
+
+func (fs *fingerprintSuite) TestInsertDataset() {
+	fp1, err := builder.Insert("items").Rows(
+		builder.Record{"name": "Bob", "address": "111 Test Addr"},
+	).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`INSERT INTO "items" ("address", "name") VALUES (...)`, fp1.SQL)
+
+	fp2, err := builder.Insert("items").Rows(
+		builder.Record{"name": "Sally", "address": "211 Test Addr"},
+		builder.Record{"name": "Jim", "address": "311 Test Addr"},
+	).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(fp1, fp2)
+}
+
+func (fs *fingerprintSuite) TestUpdateDataset() {
+	fp, err := builder.Update("items").
+		Set(builder.Record{"name": "Bob"}).
+		Where(builder.C("id").NotIn(1, 2, 3)).
+		Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`UPDATE "items" SET "name"=? WHERE ("id" NOT IN (...))`, fp.SQL)
+}
+
+func (fs *fingerprintSuite) TestDeleteDataset() {
+	fp, err := builder.Delete("items").Where(builder.C("created").Lt("2020-01-01")).Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`DELETE FROM "items" WHERE ("created" < ?)`, fp.SQL)
+}
+
+func (fs *fingerprintSuite) TestTruncateDataset() {
+	fp, err := builder.Truncate("items").Fingerprint()
+	fs.NoError(err)
+	fs.Equal(`TRUNCATE "items"`, fp.SQL)
+}
+
+func (fs *fingerprintSuite) TestError() {
+	_, err := builder.Update("items").Fingerprint()
+	fs.EqualError(err, "builder: no set values found when generating UPDATE sql")
+}
+
+func TestFingerprintSuite(t *testing.T) {
+	suite.Run(t, new(fingerprintSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
@@ -22,18 +23,19 @@ type InsertDataset struct {
 var ErrUnsupportedIntoType = errors.New("unsupported table type, a string or identifier expression is required")
 
 // used internally by database to create a database with a specific adapter
-func newInsertDataset(d string, executor sqlx.Session) *InsertDataset {
+func newInsertDataset(d string, executor sqlx.Session, s *settings) *InsertDataset {
 	return &InsertDataset{
-		clauses:  exp.NewInsertClauses(),
-		dialect:  GetDialect(d),
-		executor: executor,
+		clauses:    exp.NewInsertClauses(),
+		dialect:    s.dialect(d),
+		executor:   executor,
+		isPrepared: s.isPrepared(),
 	}
 }
 
 // Creates a new InsertDataset for the provided table. Using this method will only allow you
 // to create SQL user Database#From to create an InsertDataset with query capabilities
 func Insert(table any) *InsertDataset {
-	return newInsertDataset("default", nil).Into(table)
+	return newInsertDataset("default", nil, nil).Into(table)
 }
 
 // Set the parameter interpolation behavior. See examples
@@ -52,7 +54,7 @@ func (id *InsertDataset) IsPrepared() bool {
 // Sets the adapter used to serialize values and create the SQL statement
 func (id *InsertDataset) WithDialect(dl string) *InsertDataset {
 	ds := id.copy(id.GetClauses())
-	ds.dialect = GetDialect(dl)
+	ds.dialect = inheritSettings(GetDialect(dl), ds.dialect)
 	return ds
 }
 
@@ -82,6 +84,15 @@ func (id *InsertDataset) GetClauses() exp.InsertClauses {
 	return id.clauses
 }
 
+// Sets a timeout for executing the statement of this dataset. The context passed to ExecCtx etc. times out after
+// timeout, where supported by the dialect the timeout is also enforced by the database (e.g. SET LOCAL
+// statement_timeout inside of postgres transactions).
+//
+// A timeout less than or equal to zero removes the timeout.
+func (id *InsertDataset) WithTimeout(timeout time.Duration) *InsertDataset {
+	return id.copy(id.clauses.SetTimeout(timeout))
+}
+
 // used interally to copy the dataset
 func (id *InsertDataset) copy(clauses exp.InsertClauses) *InsertDataset {
 	return &InsertDataset{
@@ -115,6 +126,18 @@ func (id *InsertDataset) WithRecursive(name string, subquery exp.Expression) *In
 	return id.copy(id.clauses.CommonTablesAppend(exp.NewCommonTableExpression(true, name, subquery)))
 }
 
+// Adds common table expressions (CTE) created with CTE to the WITH clause. Unlike With the column names,
+// materialization and the SEARCH and CYCLE clauses of the CTEs are typed.
+//
+//	WithCTE(builder.CTE("tree", sub).Columns("id", "parent_id").NotMaterialized())
+func (id *InsertDataset) WithCTE(ctes ...exp.CommonTableExpression) *InsertDataset {
+	clauses := id.clauses
+	for _, cte := range ctes {
+		clauses = clauses.CommonTablesAppend(cte)
+	}
+	return id.copy(clauses)
+}
+
 // Sets the table to insert INTO. This return a new dataset with the original table replaced. See examples.
 // You can pass in the following.
 //
@@ -133,7 +156,7 @@ func (id *InsertDataset) Into(into any) *InsertDataset {
 
 // Sets the Columns to insert into
 func (id *InsertDataset) Cols(cols ...any) *InsertDataset {
-	return id.copy(id.clauses.SetCols(exp.NewColumnListExpression(cols...)))
+	return id.copy(id.clauses.SetCols(exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(id.dialect), cols...)))
 }
 
 // Clears the Columns to insert into
@@ -143,13 +166,13 @@ func (id *InsertDataset) ClearCols() *InsertDataset {
 
 // Adds columns to the current list of columns clause. See examples
 func (id *InsertDataset) ColsAppend(cols ...any) *InsertDataset {
-	return id.copy(id.clauses.ColsAppend(exp.NewColumnListExpression(cols...)))
+	return id.copy(id.clauses.ColsAppend(exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(id.dialect), cols...)))
 }
 
 // Adds a subquery to the insert. See examples.
 func (id *InsertDataset) FromQuery(from exp.AppendableExpression) *InsertDataset {
 	if sds, ok := from.(*SelectDataset); ok {
-		if sds.dialect != GetDialect("default") && id.Dialect() != sds.dialect {
+		if sds.dialect.Dialect() != "default" && id.dialect.Dialect() != sds.dialect.Dialect() {
 			panic(
 				fmt.Errorf(
 					"incompatible dialects for INSERT (%q) and SELECT (%q)",
@@ -184,7 +207,8 @@ func (id *InsertDataset) ClearRows() *InsertDataset {
 
 // Adds a RETURNING clause to the dataset if the adapter supports it See examples.
 func (id *InsertDataset) Returning(returning ...any) *InsertDataset {
-	return id.copy(id.clauses.SetReturning(exp.NewColumnListExpression(returning...)))
+	cols := exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(id.dialect), returning...)
+	return id.copy(id.clauses.SetReturning(cols))
 }
 
 // Adds an (ON CONFLICT/ON DUPLICATE KEY) clause to the dataset if the dialect supports it. See examples.
@@ -271,7 +295,7 @@ func (id *InsertDataset) ExecCtx(ctx context.Context) (sql.Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	return id.executor.ExecCtx(ctx, query, args...)
+	return id.session(ctx).ExecCtx(ctx, query, args...)
 }
 
 func (id *InsertDataset) QueryRow(v any) error {
@@ -283,7 +307,7 @@ func (id *InsertDataset) QueryRowCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return id.executor.QueryRowCtx(ctx, v, query, args...)
+	return id.session(ctx).QueryRowCtx(ctx, v, query, args...)
 }
 
 func (id *InsertDataset) QueryRowPartial(v any) error {
@@ -295,7 +319,7 @@ func (id *InsertDataset) QueryRowPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return id.executor.QueryRowPartialCtx(ctx, v, query, args...)
+	return id.session(ctx).QueryRowPartialCtx(ctx, v, query, args...)
 }
 
 func (id *InsertDataset) QueryRows(v any) error {
@@ -307,7 +331,7 @@ func (id *InsertDataset) QueryRowsCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return id.executor.QueryRowsCtx(ctx, v, query, args...)
+	return id.session(ctx).QueryRowsCtx(ctx, v, query, args...)
 }
 
 func (id *InsertDataset) QueryRowsPartial(v any) error {
@@ -319,7 +343,7 @@ func (id *InsertDataset) QueryRowsPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return id.executor.QueryRowsPartialCtx(ctx, v, query, args...)
+	return id.session(ctx).QueryRowsPartialCtx(ctx, v, query, args...)
 }
 
 func (id *InsertDataset) buildSQL() (string, []any, error) {
@@ -329,6 +353,20 @@ func (id *InsertDataset) buildSQL() (string, []any, error) {
 	return id.insertSQLBuilder().ToSQL()
 }
 
+// Generates the fingerprint of the INSERT statement, the values of the dataset are replaced with placeholders so
+// datasets with the same shape have the same fingerprint. See Fingerprint.
+//
+// Errors:
+//   - There is an error generating the SQL
+func (id *InsertDataset) Fingerprint() (Fingerprint, error) {
+	if id.err != nil {
+		return Fingerprint{}, id.err
+	}
+	return fingerprint(func(b sb.SQLBuilder) {
+		id.dialect.ToInsertSQL(b, id.clauses)
+	})
+}
+
 func (id *InsertDataset) insertSQLBuilder() sb.SQLBuilder {
 	buf := sb.NewSQLBuilder(id.isPrepared.Bool())
 	if id.err != nil {
@@ -337,3 +375,9 @@ func (id *InsertDataset) insertSQLBuilder() sb.SQLBuilder {
 	id.dialect.ToInsertSQL(buf, id.clauses)
 	return buf
 }
+
+// returns the session to execute statements with, see datasetSession
+func (id *InsertDataset) session(ctx context.Context) sqlx.Session {
+	stmt := newStatement("INSERT", id.clauses.Into())
+	return datasetSession(ctx, id.dialect, id.executor, stmt, id.clauses.Timeout())
+}
@@ -86,6 +86,14 @@ func (ids *insertDatasetSuite) TestGetClauses() {
 	ids.Equal(ce, ds.GetClauses())
 }
 
+func (ids *insertDatasetSuite) TestWithTimeout() {
+	ds := builder.Insert("test")
+	ce := exp.NewInsertClauses().SetInto(builder.I("test"))
+	ids.Equal(ce.SetTimeout(time.Second), ds.WithTimeout(time.Second).GetClauses())
+	ids.Equal(ce, ds.WithTimeout(time.Second).WithTimeout(0).GetClauses())
+	ids.Equal(ce, ds.GetClauses())
+}
+
 func (ids *insertDatasetSuite) TestWith() {
 	from := builder.From("cte")
 	bd := builder.Insert("items")
@@ -120,6 +128,23 @@ func (ids *insertDatasetSuite) TestWithRecursive() {
 	)
 }
 
+func (ids *insertDatasetSuite) TestWithCTE() {
+	cte := builder.CTE("test-cte", builder.From("cte")).Materialized()
+	bd := builder.Insert("items")
+	ids.assertCases(
+		insertTestCase{
+			ds: bd.WithCTE(cte),
+			clauses: exp.NewInsertClauses().
+				SetInto(builder.C("items")).
+				CommonTablesAppend(cte),
+		},
+		insertTestCase{
+			ds:      bd,
+			clauses: exp.NewInsertClauses().SetInto(builder.C("items")),
+		},
+	)
+}
+
 func (ids *insertDatasetSuite) TestInto() {
 	bd := builder.Insert("items")
 	ids.assertCases(
@@ -215,6 +240,18 @@ func (ids *insertDatasetSuite) TestFromQuery() {
 	)
 }
 
+func (ids *insertDatasetSuite) TestFromQuery_values() {
+	v := builder.Values([][]any{{1, "a"}, {2, "b"}})
+	sql, args, err := builder.Insert("items").Cols("id", "name").FromQuery(v).Prepared(true).ToSQL()
+	ids.NoError(err)
+	ids.Equal(`INSERT INTO "items" ("id", "name") VALUES (?, ?), (?, ?)`, sql)
+	ids.Equal([]any{int64(1), "a", int64(2), "b"}, args)
+
+	sql, _, err = builder.Dialect("mysql").Insert("items").Cols("id", "name").FromQuery(v).ToSQL()
+	ids.NoError(err)
+	ids.Equal("INSERT INTO `items` (`id`, `name`) SELECT 1, 'a' UNION ALL SELECT 2, 'b'", sql)
+}
+
 func (ids *insertDatasetSuite) TestFromQueryDialectInheritance() {
 	md := new(mocks.SQLDialect)
 	md.On("Dialect").Return("dialect")
@@ -2,6 +2,9 @@ package sb
 
 import (
 	"bytes"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/internal/util"
 )
 
 // Builder that is composed of a bytes.Buffer. It is used internally and by adapters to build SQL statements
@@ -14,17 +17,30 @@ type (
 		WriteStrings(ss ...string) SQLBuilder
 		WriteRunes(r ...rune) SQLBuilder
 		IsPrepared() bool
+		IsFingerprint() bool
 		CurrentArgPosition() int
+		Settings() *Settings
+		SetSettings(s *Settings) SQLBuilder
 		ToSQL() (sql string, args []any, err error)
 	}
+	// Settings of a statement that are not part of the options of its dialect, e.g. the Options of a builder.Database
+	Settings struct {
+		// The location time.Time values are interpolated in, the package level location is used when nil
+		TimeLocation *time.Location
+		// The options used to map the fields of structs to columns, the package level settings are used when nil
+		ColumnMapOptions *util.ColumnMapOptions
+	}
 	sqlBuilder struct {
 		buf *bytes.Buffer
 		// True if the sql should not be interpolated
 		isPrepared bool
+		// True if the sql is generated for a fingerprint, values are replaced with placeholders and lists are collapsed
+		isFingerprint bool
 		// Current Number of arguments, used by adapters that need positional placeholders
 		currentArgPosition int
 		args               []any
 		err                error
+		settings           *Settings
 	}
 )
 
@@ -37,6 +53,14 @@ func NewSQLBuilder(isPrepared bool) SQLBuilder {
 	}
 }
 
+// Creates a builder for the fingerprint of a statement. The builder is prepared so values are replaced with
+// placeholders, generators also collapse lists of values so statements with the same shape generate the same SQL.
+func NewFingerprintSQLBuilder() SQLBuilder {
+	b := NewSQLBuilder(true).(*sqlBuilder)
+	b.isFingerprint = true
+	return b
+}
+
 func (b *sqlBuilder) Error() error {
 	return b.err
 }
@@ -78,11 +102,27 @@ func (b *sqlBuilder) IsPrepared() bool {
 	return b.isPrepared
 }
 
+// Returns true if the sql is generated for a fingerprint
+func (b *sqlBuilder) IsFingerprint() bool {
+	return b.isFingerprint
+}
+
 // Returns true if the sql is a prepared statement
 func (b *sqlBuilder) CurrentArgPosition() int {
 	return b.currentArgPosition
 }
 
+// Returns the settings of the statement, nil for the package level settings
+func (b *sqlBuilder) Settings() *Settings {
+	return b.settings
+}
+
+// Sets the settings used by generators while generating the statement
+func (b *sqlBuilder) SetSettings(s *Settings) SQLBuilder {
+	b.settings = s
+	return b
+}
+
 // Adds an argument to the builder, used when IsPrepared is false
 func (b *sqlBuilder) WriteArg(i ...any) SQLBuilder {
 	if b.err == nil {
@@ -49,3 +49,13 @@ func (o Options) Equals(val string) bool {
 func (o Options) IsEmpty() bool {
 	return len(o) == 0
 }
+
+// Value returns the value of a name=value option and reports whether the option is present.
+func (o Options) Value(optionName string) (string, bool) {
+	for _, s := range o.Values() {
+		if name, value, ok := strings.Cut(s, "="); ok && name == optionName {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/Tooooommy/builder/v9/internal/tag"
 )
@@ -16,11 +17,26 @@ type (
 		ShouldUpdate   bool
 		DefaultIfEmpty bool
 		GoType         reflect.Type
+		// The name of the converter set with a builder tag option (e.g. `builder:"json"`)
+		Converter string
+		// The table (or alias) and column a column of a nested struct is selected from, e.g. "user" and "id" for
+		// "user.id". Both are empty for the columns of the struct itself.
+		Table  string
+		Column string
 	}
 	ColumnMap map[string]ColumnData
+	// Options used to map the fields of a struct to columns, see GetColumnMapWithOptions
+	ColumnMapOptions struct {
+		// Set to true to ignore fields without a db tag
+		IgnoreUntaggedFields bool
+		// Names the columns of fields without a db tag, the function set with SetColumnRenameFunction is used when nil
+		ColumnRenameFunction func(string) string
+		// The column maps created with the options by struct type, see GetColumnMapWithOptions
+		cache sync.Map
+	}
 )
 
-func newColumnMap(t reflect.Type, fieldIndex []int, prefixes []string) ColumnMap {
+func newColumnMap(t reflect.Type, fieldIndex []int, prefixes []string, table string, opts *ColumnMapOptions) ColumnMap {
 	cm, n := ColumnMap{}, t.NumField()
 	var subColMaps []ColumnMap
 	for i := 0; i < n; i++ {
@@ -28,23 +44,26 @@ func newColumnMap(t reflect.Type, fieldIndex []int, prefixes []string) ColumnMap
 		if f.Anonymous && (f.Type.Kind() == reflect.Struct || f.Type.Kind() == reflect.Ptr) {
 			builderTag := tag.New("db", f.Tag)
 			if !builderTag.Contains("-") {
-				subColMaps = append(subColMaps, getStructColumnMap(&f, fieldIndex, builderTag.Values(), prefixes))
+				subColMaps = append(subColMaps, getStructColumnMap(&f, fieldIndex, builderTag.Values(), prefixes, table, opts))
 			}
 		} else if f.PkgPath == "" {
 			dbTag := tag.New("db", f.Tag)
 			// if PkgPath is empty then it is an exported field
-			columnName := getColumnName(&f, dbTag)
-			if !shouldIgnoreField(dbTag) {
-				if !implementsScanner(f.Type) {
-					subCm := getStructColumnMap(&f, fieldIndex, []string{columnName}, prefixes)
+			columnName := getColumnName(&f, dbTag, opts)
+			if !shouldIgnoreField(dbTag, opts) {
+				builderTag := tag.New("builder", f.Tag)
+				if !implementsScanner(f.Type) && !hasConverter(&f, builderTag) {
+					subCm := getStructColumnMap(&f, fieldIndex, []string{columnName}, prefixes, table, opts)
 					if len(subCm) != 0 {
 						subColMaps = append(subColMaps, subCm)
 						continue
 					}
 				}
-				builderTag := tag.New("builder", f.Tag)
-				columnName = strings.Join(append(prefixes, columnName), ".")
-				cm[columnName] = newColumnData(&f, columnName, fieldIndex, builderTag)
+				data := newColumnData(&f, strings.Join(append(prefixes, columnName), "."), fieldIndex, builderTag)
+				if len(prefixes) > 0 {
+					data.Table, data.Column = table, columnName
+				}
+				cm[data.ColumnName] = data
 			}
 		}
 	}
@@ -91,31 +110,57 @@ func newColumnData(f *reflect.StructField, columnName string, fieldIndex []int,
 		ShouldInsert:   !builderTag.Contains(skipInsertTagName),
 		ShouldUpdate:   !builderTag.Contains(skipUpdateTagName),
 		DefaultIfEmpty: builderTag.Contains(defaultIfEmptyTagName),
+		Converter:      converterName(builderTag),
 		FieldIndex:     concatFieldIndexes(fieldIndex, f.Index),
 		GoType:         f.Type,
 	}
 }
 
-func getStructColumnMap(f *reflect.StructField, fieldIndex []int, fieldNames, prefixes []string) ColumnMap {
+// The columns of a nested struct are prefixed with the field names and selected from the table set with the
+// builder:"table=..." option, defaulting to the last field name.
+func getStructColumnMap(
+	f *reflect.StructField, fieldIndex []int, fieldNames, prefixes []string, table string, opts *ColumnMapOptions,
+) ColumnMap {
 	subFieldIndexes := concatFieldIndexes(fieldIndex, f.Index)
-	subPrefixes := append(prefixes, fieldNames...)
+	subPrefixes := append(prefixes[:len(prefixes):len(prefixes)], fieldNames...)
+	if t, ok := tag.New("builder", f.Tag).Value(tableTagName); ok {
+		table = t
+	} else if len(fieldNames) > 0 {
+		table = fieldNames[len(fieldNames)-1]
+	}
 	if f.Type.Kind() == reflect.Ptr {
-		return newColumnMap(f.Type.Elem(), subFieldIndexes, subPrefixes)
+		return newColumnMap(f.Type.Elem(), subFieldIndexes, subPrefixes, table, opts)
 	}
-	return newColumnMap(f.Type, subFieldIndexes, subPrefixes)
+	return newColumnMap(f.Type, subFieldIndexes, subPrefixes, table, opts)
 }
 
-func getColumnName(f *reflect.StructField, dbTag tag.Options) string {
+// Returns true if opts map the fields of structs like the package level settings, e.g. the default options of a
+// Database that only sets Options.TimeLocation. Nil options always use the package level settings.
+func (opts *ColumnMapOptions) IsDefault() bool {
+	if opts == nil {
+		return true
+	}
+	if opts.IgnoreUntaggedFields != ignoreUntaggedFields {
+		return false
+	}
+	return opts.ColumnRenameFunction == nil ||
+		reflect.ValueOf(opts.ColumnRenameFunction).Pointer() == reflect.ValueOf(columnRenameFunction).Pointer()
+}
+
+func getColumnName(f *reflect.StructField, dbTag tag.Options, opts *ColumnMapOptions) string {
 	if dbTag.IsEmpty() {
+		if opts.ColumnRenameFunction != nil {
+			return opts.ColumnRenameFunction(f.Name)
+		}
 		return columnRenameFunction(f.Name)
 	}
 	return dbTag.Values()[0]
 }
 
-func shouldIgnoreField(dbTag tag.Options) bool {
+func shouldIgnoreField(dbTag tag.Options, opts *ColumnMapOptions) bool {
 	if dbTag.Equals("-") {
 		return true
-	} else if dbTag.IsEmpty() && ignoreUntaggedFields {
+	} else if dbTag.IsEmpty() && opts.IgnoreUntaggedFields {
 		return true
 	}
 
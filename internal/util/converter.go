@@ -0,0 +1,73 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Tooooommy/builder/v9/internal/tag"
+)
+
+// The converters registered by name (used with a builder tag option, e.g. `builder:"json"`) and by Go type. The
+// converters are stored untyped, exp.Converter is the interface they implement.
+var (
+	namedConverters = make(map[string]any)
+	typeConverters  = make(map[reflect.Type]any)
+	convertersLock  = sync.RWMutex{}
+)
+
+func RegisterConverter(name string, converter any) {
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+	namedConverters[name] = converter
+}
+
+// Registers the converter for fields of type t. Fields of a struct type with a converter are a single column instead
+// of a nested struct so the struct map cache is reset.
+func RegisterTypeConverter(t reflect.Type, converter any) {
+	convertersLock.Lock()
+	typeConverters[t] = converter
+	convertersLock.Unlock()
+
+	structMapCacheLock.Lock()
+	defer structMapCacheLock.Unlock()
+	structMapCache = make(map[reflect.Type]ColumnMap)
+	structMapGeneration.Add(1)
+}
+
+// Returns the converter registered with name or, if name is empty, the converter registered for t or the type t
+// points to.
+func GetConverter(name string, t reflect.Type) (converter any, ok bool) {
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+	if name != "" {
+		converter, ok = namedConverters[name]
+		return converter, ok
+	}
+	if converter, ok = typeConverters[t]; !ok && t != nil && IsPointer(t.Kind()) {
+		converter, ok = typeConverters[t.Elem()]
+	}
+	return converter, ok
+}
+
+// Returns the first option of a builder tag that is not a known option, e.g. "json" for `builder:"json,skipupdate"`
+func converterName(builderTag tag.Options) string {
+	for _, opt := range builderTag.Values() {
+		switch opt {
+		case skipInsertTagName, skipUpdateTagName, defaultIfEmptyTagName:
+		default:
+			if !strings.Contains(opt, "=") {
+				return opt
+			}
+		}
+	}
+	return ""
+}
+
+func hasConverter(f *reflect.StructField, builderTag tag.Options) bool {
+	if converterName(builderTag) != "" {
+		return true
+	}
+	_, ok := GetConverter("", f.Type)
+	return ok
+}
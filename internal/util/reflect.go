@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Tooooommy/builder/v9/internal/errors"
 )
@@ -13,6 +14,7 @@ const (
 	skipUpdateTagName     = "skipupdate"
 	skipInsertTagName     = "skipinsert"
 	defaultIfEmptyTagName = "defaultifempty"
+	tableTagName          = "table"
 )
 
 var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
@@ -84,10 +86,19 @@ func IsEmptyValue(v reflect.Value) bool {
 }
 
 var (
-	structMapCache     = make(map[any]ColumnMap)
+	structMapCache     = make(map[reflect.Type]ColumnMap)
 	structMapCacheLock = sync.Mutex{}
+	// Incremented when the struct map cache is reset, the column maps cached on ColumnMapOptions of an older
+	// generation are recreated
+	structMapGeneration atomic.Uint64
 )
 
+// A column map cached on ColumnMapOptions
+type cachedColumnMap struct {
+	generation uint64
+	cm         ColumnMap
+}
+
 var (
 	DefaultColumnRenameFunction = strings.ToLower
 	columnRenameFunction        = DefaultColumnRenameFunction
@@ -102,10 +113,14 @@ func SetIgnoreUntaggedFields(ignore bool) {
 		structMapCacheLock.Lock()
 		defer structMapCacheLock.Unlock()
 
-		structMapCache = make(map[any]ColumnMap)
+		structMapCache = make(map[reflect.Type]ColumnMap)
 	}
 }
 
+func GetIgnoreUntaggedFields() bool {
+	return ignoreUntaggedFields
+}
+
 func SetColumnRenameFunction(newFunction func(string) string) {
 	columnRenameFunction = newFunction
 }
@@ -205,16 +220,33 @@ func AssignStructVals(i any, rd rowData, cm ColumnMap) {
 }
 
 func GetColumnMap(i any) (ColumnMap, error) {
+	return GetColumnMapWithOptions(i, nil)
+}
+
+// Returns the column map of a struct mapped with opts, the package level settings (see SetIgnoreUntaggedFields and
+// SetColumnRenameFunction) are used when opts is nil. The column maps are cached by the type of the struct,
+// on opts when set so they are released with the options.
+func GetColumnMapWithOptions(i any, opts *ColumnMapOptions) (ColumnMap, error) {
 	val := reflect.Indirect(reflect.ValueOf(i))
 	t, valKind := GetTypeInfo(i, val)
 	if valKind != reflect.Struct {
 		return nil, errors.New("cannot scan into this type: %v", t) // #nosec
 	}
 
+	if opts != nil {
+		gen := structMapGeneration.Load()
+		if c, ok := opts.cache.Load(t); ok && c.(cachedColumnMap).generation == gen {
+			return c.(cachedColumnMap).cm, nil
+		}
+		cm := newColumnMap(t, []int{}, []string{}, "", opts)
+		opts.cache.Store(t, cachedColumnMap{generation: gen, cm: cm})
+		return cm, nil
+	}
 	structMapCacheLock.Lock()
 	defer structMapCacheLock.Unlock()
 	if _, ok := structMapCache[t]; !ok {
-		structMapCache[t] = newColumnMap(t, []int{}, []string{})
+		opts = &ColumnMapOptions{IgnoreUntaggedFields: ignoreUntaggedFields}
+		structMapCache[t] = newColumnMap(t, []int{}, []string{}, "", opts)
 	}
 	return structMapCache[t], nil
 }
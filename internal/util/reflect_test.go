@@ -731,6 +731,42 @@ func (rt *reflectTest) TestGetColumnMap_withStructWithIgnoreUntagged() {
 	}, cm)
 }
 
+func (rt *reflectTest) TestGetColumnMapWithOptions() {
+	type TestStruct struct {
+		Str  string `db:"s"`
+		Bool bool
+	}
+	var ts TestStruct
+	ignore := &util.ColumnMapOptions{IgnoreUntaggedFields: true}
+	upper := &util.ColumnMapOptions{ColumnRenameFunction: strings.ToUpper}
+
+	cm, err := util.GetColumnMapWithOptions(&ts, ignore)
+	rt.NoError(err)
+	rt.Equal([]string{"s"}, cm.Cols())
+
+	cm, err = util.GetColumnMapWithOptions(&ts, upper)
+	rt.NoError(err)
+	rt.Equal([]string{"BOOL", "s"}, cm.Cols())
+
+	// the package level settings are not changed by the options
+	cm, err = util.GetColumnMap(&ts)
+	rt.NoError(err)
+	rt.Equal([]string{"bool", "s"}, cm.Cols())
+}
+
+func (rt *reflectTest) TestColumnMapOptions_IsDefault() {
+	var opts *util.ColumnMapOptions
+	rt.True(opts.IsDefault())
+	rt.True((&util.ColumnMapOptions{}).IsDefault())
+	rt.True((&util.ColumnMapOptions{ColumnRenameFunction: strings.ToLower}).IsDefault())
+	rt.False((&util.ColumnMapOptions{ColumnRenameFunction: strings.ToUpper}).IsDefault())
+	rt.False((&util.ColumnMapOptions{IgnoreUntaggedFields: true}).IsDefault())
+
+	util.SetIgnoreUntaggedFields(true)
+	defer util.SetIgnoreUntaggedFields(false)
+	rt.True((&util.ColumnMapOptions{IgnoreUntaggedFields: true}).IsDefault())
+}
+
 func (rt *reflectTest) TestGetColumnMap_withStructWithTag() {
 	type TestStruct struct {
 		Str     string          `db:"s"`
@@ -946,6 +982,8 @@ func (rt *reflectTest) TestGetColumnMap_withEmbeddedTaggedStruct() {
 	rt.Equal(util.ColumnMap{
 		"test_embedded.bool": {
 			ColumnName:   "test_embedded.bool",
+			Table:        "test_embedded",
+			Column:       "bool",
 			FieldIndex:   []int{0, 0},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -953,6 +991,8 @@ func (rt *reflectTest) TestGetColumnMap_withEmbeddedTaggedStruct() {
 		},
 		"test_embedded.valuer": {
 			ColumnName:   "test_embedded.valuer",
+			Table:        "test_embedded",
+			Column:       "valuer",
 			FieldIndex:   []int{0, 1},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -992,6 +1032,8 @@ func (rt *reflectTest) TestGetColumnMap_withEmbeddedTaggedStructPointer() {
 	rt.Equal(util.ColumnMap{
 		"test_embedded.bool": {
 			ColumnName:   "test_embedded.bool",
+			Table:        "test_embedded",
+			Column:       "bool",
 			FieldIndex:   []int{0, 0},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -999,6 +1041,8 @@ func (rt *reflectTest) TestGetColumnMap_withEmbeddedTaggedStructPointer() {
 		},
 		"test_embedded.valuer": {
 			ColumnName:   "test_embedded.valuer",
+			Table:        "test_embedded",
+			Column:       "valuer",
 			FieldIndex:   []int{0, 1},
 			ShouldInsert: true, ShouldUpdate: true,
 			GoType: reflect.TypeOf(&sql.NullString{}),
@@ -1037,6 +1081,8 @@ func (rt *reflectTest) TestGetColumnMap_withTaggedStructField() {
 	rt.Equal(util.ColumnMap{
 		"test_embedded.bool": {
 			ColumnName:   "test_embedded.bool",
+			Table:        "test_embedded",
+			Column:       "bool",
 			FieldIndex:   []int{0, 0},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -1044,6 +1090,8 @@ func (rt *reflectTest) TestGetColumnMap_withTaggedStructField() {
 		},
 		"test_embedded.valuer": {
 			ColumnName:   "test_embedded.valuer",
+			Table:        "test_embedded",
+			Column:       "valuer",
 			FieldIndex:   []int{0, 1},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -1083,6 +1131,8 @@ func (rt *reflectTest) TestGetColumnMap_withTaggedStructPointerField() {
 	rt.Equal(util.ColumnMap{
 		"test_embedded.bool": {
 			ColumnName:   "test_embedded.bool",
+			Table:        "test_embedded",
+			Column:       "bool",
 			FieldIndex:   []int{0, 0},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -1090,6 +1140,8 @@ func (rt *reflectTest) TestGetColumnMap_withTaggedStructPointerField() {
 		},
 		"test_embedded.valuer": {
 			ColumnName:   "test_embedded.valuer",
+			Table:        "test_embedded",
+			Column:       "valuer",
 			FieldIndex:   []int{0, 1},
 			ShouldInsert: true,
 			ShouldUpdate: true,
@@ -1112,6 +1164,66 @@ func (rt *reflectTest) TestGetColumnMap_withTaggedStructPointerField() {
 	}, cm)
 }
 
+func (rt *reflectTest) TestGetColumnMap_withNestedStructTable() {
+	type TestUser struct {
+		ID int64 `db:"id"`
+	}
+	type TestOrder struct {
+		ID   int64    `db:"id"`
+		User TestUser `db:"user"`
+	}
+
+	type TestStruct struct {
+		Order TestOrder `db:"order" builder:"table=o"`
+	}
+	var ts TestStruct
+	cm, err := util.GetColumnMap(&ts)
+	rt.NoError(err)
+	rt.Equal(util.ColumnMap{
+		"order.id": {
+			ColumnName:   "order.id",
+			Table:        "o",
+			Column:       "id",
+			FieldIndex:   []int{0, 0},
+			ShouldInsert: true,
+			ShouldUpdate: true,
+			GoType:       reflect.TypeOf(int64(1)),
+		},
+		"order.user.id": {
+			ColumnName:   "order.user.id",
+			Table:        "user",
+			Column:       "id",
+			FieldIndex:   []int{0, 1, 0},
+			ShouldInsert: true,
+			ShouldUpdate: true,
+			GoType:       reflect.TypeOf(int64(1)),
+		},
+	}, cm)
+}
+
+func (rt *reflectTest) TestGetColumnMap_withConverter() {
+	type TestSettings struct {
+		Theme string
+	}
+
+	type TestStruct struct {
+		Settings TestSettings `db:"settings" builder:"json,skipupdate"`
+	}
+	var ts TestStruct
+	cm, err := util.GetColumnMap(&ts)
+	rt.NoError(err)
+	rt.Equal(util.ColumnMap{
+		"settings": {
+			ColumnName:   "settings",
+			FieldIndex:   []int{0},
+			ShouldInsert: true,
+			ShouldUpdate: false,
+			GoType:       reflect.TypeOf(TestSettings{}),
+			Converter:    "json",
+		},
+	}, cm)
+}
+
 func (rt *reflectTest) TestGetTypeInfo() {
 	var a int64
 	var b []int64
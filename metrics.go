@@ -0,0 +1,149 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	builderErrors "github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type (
+	// Options used to create the collectors of Metrics. See NewMetrics
+	MetricsOptions struct {
+		// The namespace of the metrics (DEFAULT="builder")
+		Namespace string
+		// The subsystem of the metrics (DEFAULT="")
+		Subsystem string
+		// The buckets of the query and transaction duration histograms, in seconds (DEFAULT=prometheus.DefBuckets)
+		Buckets []float64
+		// Labels added to all metrics, e.g. the name of the database
+		ConstLabels prometheus.Labels
+	}
+
+	// Prometheus collectors that measure the statements and transactions executed through a Database.
+	// See Database#Metrics
+	Metrics struct {
+		queryDuration       *prometheus.HistogramVec
+		queryErrors         *prometheus.CounterVec
+		queriesInFlight     *prometheus.GaugeVec
+		transactionDuration *prometheus.HistogramVec
+	}
+)
+
+var errMetricsAlreadyRegistered = builderErrors.New("metrics are already registered with a different type")
+
+// Creates the collectors of Metrics and registers them with reg. Collectors that are already registered with reg
+// (e.g. by a Metrics for another Database) are reused. Only the first MetricsOptions are used.
+//
+//	metrics, err := builder.NewMetrics(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    panic(err.Error())
+//	}
+//	db.Metrics(metrics)
+//
+// The following metrics are collected
+//
+//	builder_query_duration_seconds{dialect, operation, table}: histogram of the duration of statements
+//	builder_query_errors_total{dialect, operation, table}: counter of the statements that failed
+//	builder_queries_in_flight{dialect, operation}: gauge of the statements being executed
+//	builder_transaction_duration_seconds{dialect, outcome}: histogram of the duration of transactions, the outcome is
+//	commit or rollback
+//
+// The operation is the kind of statement (select, insert, update, delete or truncate) and the table is the primary table
+// of the dataset, the table is empty for raw statements.
+func NewMetrics(reg prometheus.Registerer, opts ...MetricsOptions) (*Metrics, error) {
+	o := MetricsOptions{Namespace: "builder"}
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Buckets == nil {
+		o.Buckets = prometheus.DefBuckets
+	}
+	m := &Metrics{
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.Namespace,
+			Subsystem:   o.Subsystem,
+			Name:        "query_duration_seconds",
+			Help:        "The duration of the statements executed through builder.",
+			Buckets:     o.Buckets,
+			ConstLabels: o.ConstLabels,
+		}, []string{"dialect", "operation", "table"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.Namespace,
+			Subsystem:   o.Subsystem,
+			Name:        "query_errors_total",
+			Help:        "The number of statements executed through builder that failed.",
+			ConstLabels: o.ConstLabels,
+		}, []string{"dialect", "operation", "table"}),
+		queriesInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   o.Namespace,
+			Subsystem:   o.Subsystem,
+			Name:        "queries_in_flight",
+			Help:        "The number of statements being executed through builder.",
+			ConstLabels: o.ConstLabels,
+		}, []string{"dialect", "operation"}),
+		transactionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.Namespace,
+			Subsystem:   o.Subsystem,
+			Name:        "transaction_duration_seconds",
+			Help:        "The duration of the transactions started through builder.",
+			Buckets:     o.Buckets,
+			ConstLabels: o.ConstLabels,
+		}, []string{"dialect", "outcome"}),
+	}
+	collectors := []prometheus.Collector{m.queryDuration, m.queryErrors, m.queriesInFlight, m.transactionDuration}
+	for i, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				return nil, err
+			}
+			collectors[i] = are.ExistingCollector
+		}
+	}
+	var ok [4]bool
+	m.queryDuration, ok[0] = collectors[0].(*prometheus.HistogramVec)
+	m.queryErrors, ok[1] = collectors[1].(*prometheus.CounterVec)
+	m.queriesInFlight, ok[2] = collectors[2].(*prometheus.GaugeVec)
+	m.transactionDuration, ok[3] = collectors[3].(*prometheus.HistogramVec)
+	if !ok[0] || !ok[1] || !ok[2] || !ok[3] {
+		return nil, errMetricsAlreadyRegistered
+	}
+	return m, nil
+}
+
+// Sets the Metrics used to measure every statement executed through this Database, including the statements of its
+// datasets, and the transactions started with Transact and TransactCtx. Passing nil disables the metrics.
+func (d *Database) Metrics(metrics *Metrics) {
+	d.metrics = metrics
+}
+
+// marks the statement as in flight, the returned function observes its duration and error
+func (m *Metrics) startStatement(dialect string, stmt statement) func(err error) {
+	operation := strings.ToLower(stmt.operation)
+	inFlight := m.queriesInFlight.WithLabelValues(dialect, operation)
+	inFlight.Inc()
+	start := time.Now()
+	return func(err error) {
+		inFlight.Dec()
+		m.queryDuration.WithLabelValues(dialect, operation, stmt.table).Observe(time.Since(start).Seconds())
+		if err != nil && !errors.Is(err, sqlx.ErrNotFound) {
+			m.queryErrors.WithLabelValues(dialect, operation, stmt.table).Inc()
+		}
+	}
+}
+
+// starts measuring a transaction, the returned function observes its duration and outcome
+func (m *Metrics) startTransaction(dialect string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		outcome := "commit"
+		if err != nil {
+			outcome = "rollback"
+		}
+		m.transactionDuration.WithLabelValues(dialect, outcome).Observe(time.Since(start).Seconds())
+	}
+}
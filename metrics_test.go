@@ -0,0 +1,170 @@
+package builder_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type metricsSuite struct {
+	suite.Suite
+}
+
+func (ms *metricsSuite) newDB() (*builder.Database, sqlmock.Sqlmock) {
+	mDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ms.Require().NoError(err)
+	return builder.New("postgres", sqlx.NewSqlConnFromDB(mDB)), mock
+}
+
+func (ms *metricsSuite) TestMetrics() {
+	reg := prometheus.NewPedanticRegistry()
+	metrics, err := builder.NewMetrics(reg)
+	ms.Require().NoError(err)
+
+	db, mock := ms.newDB()
+	db.Metrics(metrics)
+
+	mock.ExpectQuery(`SELECT * FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).AddRow("111 Test Addr", "Bob"))
+	mock.ExpectQuery(`SELECT * FROM "items" LIMIT 1`).WillReturnRows(sqlmock.NewRows([]string{"address", "name"}))
+	mock.ExpectExec(`UPDATE "items" SET "name"='Sally'`).WillReturnError(errors.New("mock error"))
+	mock.ExpectExec(`delete from items`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var items []testItem
+	ms.NoError(db.From("items").QueryRowsCtx(context.Background(), &items))
+	var item testItem
+	ms.ErrorIs(db.From("items").QueryRow(&item), sqlx.ErrNotFound)
+	_, err = db.Update("items").Set(builder.Record{"name": "Sally"}).AllowFullTable().Exec()
+	ms.EqualError(err, "builder: mock error")
+	_, err = db.Exec("delete from items")
+	ms.NoError(err)
+	ms.NoError(mock.ExpectationsWereMet())
+
+	ms.Equal(3, testutil.CollectAndCount(reg, "builder_query_duration_seconds"))
+	ms.NoError(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP builder_query_errors_total The number of statements executed through builder that failed.
+# TYPE builder_query_errors_total counter
+builder_query_errors_total{dialect="postgres",operation="update",table="items"} 1
+# HELP builder_queries_in_flight The number of statements being executed through builder.
+# TYPE builder_queries_in_flight gauge
+builder_queries_in_flight{dialect="postgres",operation="delete"} 0
+builder_queries_in_flight{dialect="postgres",operation="select"} 0
+builder_queries_in_flight{dialect="postgres",operation="update"} 0
+`), "builder_query_errors_total", "builder_queries_in_flight"))
+
+	count := func(operation, table string) uint64 {
+		c, err := histogramCount(reg, "builder_query_duration_seconds", map[string]string{
+			"dialect": "postgres", "operation": operation, "table": table,
+		})
+		ms.Require().NoError(err)
+		return c
+	}
+	ms.Equal(uint64(2), count("select", "items"))
+	ms.Equal(uint64(1), count("update", "items"))
+	ms.Equal(uint64(1), count("delete", ""))
+}
+
+func (ms *metricsSuite) TestMetrics_transaction() {
+	reg := prometheus.NewPedanticRegistry()
+	metrics, err := builder.NewMetrics(reg, builder.MetricsOptions{
+		Namespace:   "app",
+		Subsystem:   "db",
+		ConstLabels: prometheus.Labels{"database": "users"},
+	})
+	ms.Require().NoError(err)
+
+	db, mock := ms.newDB()
+	db.Metrics(metrics)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ms.NoError(db.Transact(func(td *builder.TxDatabase) error {
+		return nil
+	}))
+	ms.EqualError(db.Transact(func(td *builder.TxDatabase) error {
+		return errors.New("mock error")
+	}), "builder: mock error")
+	ms.NoError(mock.ExpectationsWereMet())
+
+	for _, outcome := range []string{"commit", "rollback"} {
+		c, err := histogramCount(reg, "app_db_transaction_duration_seconds", map[string]string{
+			"database": "users", "dialect": "postgres", "outcome": outcome,
+		})
+		ms.Require().NoError(err)
+		ms.Equal(uint64(1), c, outcome)
+	}
+}
+
+func (ms *metricsSuite) TestNewMetrics_alreadyRegistered() {
+	reg := prometheus.NewRegistry()
+	m1, err := builder.NewMetrics(reg)
+	ms.Require().NoError(err)
+	m2, err := builder.NewMetrics(reg)
+	ms.Require().NoError(err)
+
+	db1, mock1 := ms.newDB()
+	db1.Metrics(m1)
+	db2, mock2 := ms.newDB()
+	db2.Metrics(m2)
+
+	mock1.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock2.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = db1.Delete("items").AllowFullTable().Exec()
+	ms.NoError(err)
+	_, err = db2.Delete("items").AllowFullTable().Exec()
+	ms.NoError(err)
+
+	c, err := histogramCount(reg, "builder_query_duration_seconds", map[string]string{
+		"dialect": "postgres", "operation": "delete", "table": "items",
+	})
+	ms.Require().NoError(err)
+	ms.Equal(uint64(2), c)
+
+	conflicting := prometheus.NewRegistry()
+	conflicting.MustRegister(prometheus.NewCounterVec(
+		prometheus.CounterOpts{Namespace: "builder", Name: "query_duration_seconds", Help: "The duration of the statements executed through builder."},
+		[]string{"dialect", "operation", "table"},
+	))
+	_, err = builder.NewMetrics(conflicting)
+	ms.Error(err)
+}
+
+// returns the sample count of the histogram with the labels
+func histogramCount(reg prometheus.Gatherer, name string, labels map[string]string) (uint64, error) {
+	families, err := reg.Gather()
+	if err != nil {
+		return 0, err
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matches := len(metric.GetLabel()) == len(labels)
+			for _, lp := range metric.GetLabel() {
+				if labels[lp.GetName()] != lp.GetValue() {
+					matches = false
+				}
+			}
+			if matches {
+				return metric.GetHistogram().GetSampleCount(), nil
+			}
+		}
+	}
+	return 0, errors.New("histogram %s%v not found", name, labels)
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(metricsSuite))
+}
@@ -6,6 +6,7 @@ import exp "github.com/Tooooommy/builder/v9/exp"
 
 import mock "github.com/stretchr/testify/mock"
 import sb "github.com/Tooooommy/builder/v9/internal/sb"
+import time "time"
 
 // SQLDialect is an autogenerated mock type for the SQLDialect type
 type SQLDialect struct {
@@ -36,11 +37,41 @@ func (_m *SQLDialect) ToInsertSQL(b sb.SQLBuilder, clauses exp.InsertClauses) {
 	_m.Called(b, clauses)
 }
 
+// ToReleaseSavepointSQL provides a mock function with given fields: b, name
+func (_m *SQLDialect) ToReleaseSavepointSQL(b sb.SQLBuilder, name string) {
+	_m.Called(b, name)
+}
+
+// ToRollbackToSavepointSQL provides a mock function with given fields: b, name
+func (_m *SQLDialect) ToRollbackToSavepointSQL(b sb.SQLBuilder, name string) {
+	_m.Called(b, name)
+}
+
+// ToSavepointSQL provides a mock function with given fields: b, name
+func (_m *SQLDialect) ToSavepointSQL(b sb.SQLBuilder, name string) {
+	_m.Called(b, name)
+}
+
+// ToResetStatementTimeoutSQL provides a mock function with given fields: b
+func (_m *SQLDialect) ToResetStatementTimeoutSQL(b sb.SQLBuilder) {
+	_m.Called(b)
+}
+
 // ToSelectSQL provides a mock function with given fields: b, clauses
 func (_m *SQLDialect) ToSelectSQL(b sb.SQLBuilder, clauses exp.SelectClauses) {
 	_m.Called(b, clauses)
 }
 
+// ToSetTransactionSQL provides a mock function with given fields: b, opts
+func (_m *SQLDialect) ToSetTransactionSQL(b sb.SQLBuilder, opts exp.TransactionOptions) {
+	_m.Called(b, opts)
+}
+
+// ToStatementTimeoutSQL provides a mock function with given fields: b, timeout
+func (_m *SQLDialect) ToStatementTimeoutSQL(b sb.SQLBuilder, timeout time.Duration) {
+	_m.Called(b, timeout)
+}
+
 // ToTruncateSQL provides a mock function with given fields: b, clauses
 func (_m *SQLDialect) ToTruncateSQL(b sb.SQLBuilder, clauses exp.TruncateClauses) {
 	_m.Called(b, clauses)
@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+	"github.com/Tooooommy/builder/v9/internal/util"
+	"github.com/Tooooommy/builder/v9/sqlgen"
+)
+
+type (
+	// The settings of a Database and of the transactions and datasets created with it, see New and Dialect. Datasets
+	// created without Options use the package level settings (SetDefaultPrepared, SetIgnoreUntaggedFields,
+	// SetColumnRenameFunction and SetTimeLocation). Use DefaultOptions to start from the package level settings.
+	//
+	//	opts := builder.DefaultOptions()
+	//	opts.TimeLocation = time.Local
+	//	db := builder.New("postgres", conn, opts)
+	Options struct {
+		// Set to true to use prepared statements by default, see SetDefaultPrepared
+		Prepared bool
+		// Set to true to ignore struct fields without a db tag, see SetIgnoreUntaggedFields
+		IgnoreUntaggedFields bool
+		// Names the columns of struct fields without a db tag, see SetColumnRenameFunction. The package level function
+		// is used when nil
+		ColumnRenameFunction func(string) string
+		// The location time.Time values are interpolated and scanned in, see SetTimeLocation. The package level
+		// location is used when nil
+		TimeLocation *time.Location
+	}
+	// The Options of a Database shared with its transactions and datasets
+	settings struct {
+		prepared prepared
+		sql      *sb.Settings
+	}
+	// A dialect generating statements with the settings of a Database, see settings.dialect
+	settingsDialect struct {
+		SQLDialect
+		settings *sb.Settings
+	}
+)
+
+// Returns Options with the current package level settings
+func DefaultOptions() Options {
+	return Options{
+		Prepared:             defaultPrepared,
+		IgnoreUntaggedFields: util.GetIgnoreUntaggedFields(),
+		TimeLocation:         sqlgen.GetTimeLocation(),
+	}
+}
+
+// Returns the settings of the first Options, or nil to use the package level settings if opts is empty
+func newSettings(opts []Options) *settings {
+	if len(opts) == 0 {
+		return nil
+	}
+	o := opts[0]
+	return &settings{
+		prepared: preparedFromBool(o.Prepared),
+		sql: &sb.Settings{
+			TimeLocation: o.TimeLocation,
+			ColumnMapOptions: &exp.ColumnMapOptions{
+				IgnoreUntaggedFields: o.IgnoreUntaggedFields,
+				ColumnRenameFunction: o.ColumnRenameFunction,
+			},
+		},
+	}
+}
+
+// Returns the prepared state of new datasets
+func (s *settings) isPrepared() prepared {
+	if s == nil {
+		return preparedNoPreference
+	}
+	return s.prepared
+}
+
+// Returns the options used to map the fields of structs to columns, nil for the package level settings
+func (s *settings) columnMapOptions() *exp.ColumnMapOptions {
+	if s == nil {
+		return nil
+	}
+	return s.sql.ColumnMapOptions
+}
+
+// Returns the registered dialect generating statements with the settings
+func (s *settings) dialect(name string) SQLDialect {
+	if s == nil {
+		return GetDialect(name)
+	}
+	return settingsDialect{SQLDialect: GetDialect(name), settings: s.sql}
+}
+
+func (sd settingsDialect) ToSelectSQL(b sb.SQLBuilder, clauses exp.SelectClauses) {
+	defer sd.apply(b)()
+	sd.SQLDialect.ToSelectSQL(b, clauses)
+}
+
+func (sd settingsDialect) ToUpdateSQL(b sb.SQLBuilder, clauses exp.UpdateClauses) {
+	defer sd.apply(b)()
+	sd.SQLDialect.ToUpdateSQL(b, clauses)
+}
+
+func (sd settingsDialect) ToInsertSQL(b sb.SQLBuilder, clauses exp.InsertClauses) {
+	defer sd.apply(b)()
+	sd.SQLDialect.ToInsertSQL(b, clauses)
+}
+
+func (sd settingsDialect) ToDeleteSQL(b sb.SQLBuilder, clauses exp.DeleteClauses) {
+	defer sd.apply(b)()
+	sd.SQLDialect.ToDeleteSQL(b, clauses)
+}
+
+func (sd settingsDialect) ToTruncateSQL(b sb.SQLBuilder, clauses exp.TruncateClauses) {
+	defer sd.apply(b)()
+	sd.SQLDialect.ToTruncateSQL(b, clauses)
+}
+
+// Returns the options of the wrapped dialect, see dialectOptionsOf
+func (sd settingsDialect) options() *SQLDialectOptions {
+	return dialectOptionsOf(sd.SQLDialect)
+}
+
+// Sets the settings on b while a statement is generated, the returned function restores the settings of the
+// enclosing statement (e.g. for sub queries)
+func (sd settingsDialect) apply(b sb.SQLBuilder) func() {
+	prev := b.Settings()
+	b.SetSettings(sd.settings)
+	return func() { b.SetSettings(prev) }
+}
+
+// Returns d with the settings of the dialect from, e.g. when the dialect of a dataset of a Database with Options is
+// changed
+func inheritSettings(d, from SQLDialect) SQLDialect {
+	if sd, ok := from.(settingsDialect); ok {
+		return settingsDialect{SQLDialect: d, settings: sd.settings}
+	}
+	return d
+}
+
+// Returns the settings d generates statements with, nil for the package level settings
+func settingsOf(d SQLDialect) *sb.Settings {
+	if sd, ok := d.(settingsDialect); ok {
+		return sd.settings
+	}
+	return nil
+}
+
+// Returns the options of d, nil for dialects that were not created with dialect options
+func dialectOptionsOf(d SQLDialect) *SQLDialectOptions {
+	if od, ok := d.(interface{ options() *SQLDialectOptions }); ok {
+		return od.options()
+	}
+	return nil
+}
+
+// Returns the options used to map the fields of structs to columns with d, nil for the package level settings
+func columnMapOptionsOf(d SQLDialect) *exp.ColumnMapOptions {
+	if s := settingsOf(d); s != nil {
+		return s.ColumnMapOptions
+	}
+	return nil
+}
@@ -42,7 +42,8 @@ func preparedFromBool(prepared bool) prepared {
 }
 
 // SetDefaultPrepared controls the default Prepared state of all datasets. If
-// set to true, any new dataset will use prepared queries by default.
+// set to true, any new dataset will use prepared queries by default. Datasets of
+// a Database created with Options use Options.Prepared instead.
 func SetDefaultPrepared(prepared bool) {
 	defaultPrepared = prepared
 }
@@ -0,0 +1,413 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/util"
+	"github.com/Tooooommy/builder/v9/sqlgen"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// The columnar result of a query whose columns are not known at compile time. Pass a *ResultSet to QueryRows to scan
+// every row of a query into it.
+type ResultSet struct {
+	// The names of the selected columns
+	Columns []string
+	// The database type names of the columns as reported by the driver (e.g. VARCHAR, INT4), empty if unknown
+	Types []string
+	// The values of every row in the order of Columns
+	Values [][]any
+}
+
+var ErrQueryRowsNotSupported = errors.New("the session of the dataset can not query rows into records")
+
+// Returns a function scanning rows into v if v is a *[]exp.Record, *[]map[string]any or *ResultSet, or a *exp.Record
+// or *map[string]any when only the first row is scanned (e.g. QueryRow). Returns nil for any other type, which is
+// scanned by sqlx.Session.
+func recordsScanner(dialect SQLDialect, v any, single bool) func(rows *sql.Rows) error {
+	switch t := v.(type) {
+	case *exp.Record:
+		if single {
+			return func(rows *sql.Rows) error {
+				return scanRecords(rows, dialect, true, func(r exp.Record) { *t = r })
+			}
+		}
+	case *map[string]any:
+		if single {
+			return func(rows *sql.Rows) error {
+				return scanRecords(rows, dialect, true, func(r exp.Record) { *t = r })
+			}
+		}
+	case *[]exp.Record:
+		if !single {
+			return func(rows *sql.Rows) error {
+				*t = (*t)[:0]
+				return scanRecords(rows, dialect, false, func(r exp.Record) { *t = append(*t, r) })
+			}
+		}
+	case *[]map[string]any:
+		if !single {
+			return func(rows *sql.Rows) error {
+				*t = (*t)[:0]
+				return scanRecords(rows, dialect, false, func(r exp.Record) { *t = append(*t, r) })
+			}
+		}
+	case *ResultSet:
+		if !single {
+			return func(rows *sql.Rows) error {
+				return scanResultSet(rows, dialect, t)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a function scanning rows into v if v is a pointer to a struct, or to a slice of structs when all rows are
+// scanned, that has nested structs or fields with a converter or is mapped with opts that differ from the package level
+// settings. The columns of nested structs are prefixed with the field name (e.g. "user.id"), converted fields are not
+// stored as they are and opts (e.g. Options.ColumnRenameFunction) are not known to sqlx.Session, the rows are assigned
+// with the column map of the struct instead. In strict mode every column of the struct must be selected. The struct is
+// mapped with opts, or the package level settings when nil. Returns nil for any other type.
+func structScanner(v any, single, strict bool, opts *exp.ColumnMapOptions) func(rows *sql.Rows) error {
+	val := reflect.ValueOf(v)
+	if !util.IsPointer(val.Kind()) || val.IsNil() {
+		return nil
+	}
+	if kind := val.Elem().Kind(); (single && !util.IsStruct(kind)) || (!single && !util.IsSlice(kind)) {
+		return nil
+	}
+	cm, err := util.GetColumnMapWithOptions(v, opts)
+	if err != nil {
+		return nil
+	}
+	convs, err := columnConverters(cm)
+	if err != nil {
+		return func(rows *sql.Rows) error { return err }
+	}
+	if len(convs) == 0 && !hasNestedColumns(cm) && opts.IsDefault() {
+		return nil
+	}
+	if single {
+		return func(rows *sql.Rows) error {
+			return scanStructs(rows, cm, convs, strict, true, func(row map[string]any) {
+				util.AssignStructVals(v, row, cm)
+			})
+		}
+	}
+	slice := val.Elem()
+	elemType := util.GetSliceElementType(slice)
+	return func(rows *sql.Rows) error {
+		return scanStructs(rows, cm, convs, strict, false, func(row map[string]any) {
+			item := reflect.New(elemType)
+			util.AssignStructVals(item.Interface(), row, cm)
+			util.AppendSliceElement(slice, item)
+		})
+	}
+}
+
+// Returns true if v is a struct, or a slice of structs, with nested structs, see structScanner
+func isNestedStructTarget(v any, opts *exp.ColumnMapOptions) bool {
+	if v == nil {
+		return false
+	}
+	_, kind := util.GetTypeInfo(v, reflect.Indirect(reflect.ValueOf(v)))
+	if !util.IsStruct(kind) {
+		return false
+	}
+	cm, err := util.GetColumnMapWithOptions(v, opts)
+	return err == nil && hasNestedColumns(cm)
+}
+
+// Returns the converters of the columns of cm by column name
+func columnConverters(cm util.ColumnMap) (map[string]exp.Converter, error) {
+	convs := make(map[string]exp.Converter)
+	for name, data := range cm {
+		c, err := exp.ColumnConverter(data)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			convs[name] = c
+		}
+	}
+	return convs, nil
+}
+
+func hasNestedColumns(cm util.ColumnMap) bool {
+	for _, data := range cm {
+		if data.Column != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Passes the rows of query to scan. sqlx.Session only scans into structs and primitives so the rows are queried with
+// the *sql.DB of a sqlx.SqlConn or the *sql.Tx of a transaction session.
+func queryRows(
+	ctx context.Context, s sqlx.Session, scan func(rows *sql.Rows) error, query string, args ...any,
+) (err error) {
+	var rows *sql.Rows
+	switch q := s.(type) {
+	case interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	}:
+		rows, err = q.QueryContext(ctx, query, args...)
+	case sqlx.SqlConn:
+		db, rawErr := q.RawDB()
+		if rawErr != nil {
+			return rawErr
+		}
+		rows, err = db.QueryContext(ctx, query, args...)
+	default:
+		return ErrQueryRowsNotSupported
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if err = scan(rows); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+func scanRecords(rows *sql.Rows, dialect SQLDialect, single bool, add func(r exp.Record)) error {
+	cols, types, err := rowsColumns(rows)
+	if err != nil {
+		return err
+	}
+	found := false
+	for rows.Next() {
+		vals, err := scanValues(rows, dialect, types)
+		if err != nil {
+			return err
+		}
+		record := make(exp.Record, len(cols))
+		for i, col := range cols {
+			record[col] = vals[i]
+		}
+		add(record)
+		found = true
+		if single {
+			break
+		}
+	}
+	if single && !found {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sqlx.ErrNotFound
+	}
+	return nil
+}
+
+// Scans every row (or only the first when single is true) into a map of the columns of cm to pointers to values of
+// their field type, the values of columns with a converter are converted to the field type. NULL values are left out
+// of the map so the fields keep their zero value and nested struct pointers are only allocated when one of their
+// columns is not NULL.
+func scanStructs(
+	rows *sql.Rows, cm util.ColumnMap, convs map[string]exp.Converter, strict, single bool, add func(row map[string]any),
+) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if strict {
+		selected := make(map[string]bool, len(cols))
+		for _, col := range cols {
+			selected[col] = true
+		}
+		for name := range cm {
+			if !selected[name] {
+				return sqlx.ErrNotMatchDestination
+			}
+		}
+	}
+	found := false
+	for rows.Next() {
+		dest := make([]any, len(cols))
+		for i, col := range cols {
+			if data, ok := cm[col]; ok && convs[col] == nil {
+				dest[i] = reflect.New(reflect.PtrTo(data.GoType)).Interface()
+			} else {
+				dest[i] = new(any)
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			data, ok := cm[col]
+			if !ok {
+				continue
+			}
+			if c := convs[col]; c != nil {
+				if src := *dest[i].(*any); src != nil {
+					field := reflect.New(data.GoType)
+					if err := c.FromDB(field.Interface(), src); err != nil {
+						return err
+					}
+					row[col] = field.Interface()
+				}
+			} else if ptr := reflect.ValueOf(dest[i]).Elem(); !ptr.IsNil() {
+				row[col] = ptr.Interface()
+			}
+		}
+		add(row)
+		found = true
+		if single {
+			break
+		}
+	}
+	if single && !found {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sqlx.ErrNotFound
+	}
+	return nil
+}
+
+func scanResultSet(rows *sql.Rows, dialect SQLDialect, rs *ResultSet) error {
+	cols, types, err := rowsColumns(rows)
+	if err != nil {
+		return err
+	}
+	*rs = ResultSet{Columns: cols, Types: types, Values: [][]any{}}
+	for rows.Next() {
+		vals, err := scanValues(rows, dialect, types)
+		if err != nil {
+			return err
+		}
+		rs.Values = append(rs.Values, vals)
+	}
+	return nil
+}
+
+func rowsColumns(rows *sql.Rows) (cols, types []string, err error) {
+	if cols, err = rows.Columns(); err != nil {
+		return nil, nil, err
+	}
+	types = make([]string, len(cols))
+	// drivers that do not report column types return an error, the values are then returned as they are scanned
+	if colTypes, typesErr := rows.ColumnTypes(); typesErr == nil {
+		for i, ct := range colTypes {
+			types[i] = ct.DatabaseTypeName()
+		}
+	}
+	return cols, types, nil
+}
+
+func scanValues(rows *sql.Rows, dialect SQLDialect, types []string) ([]any, error) {
+	vals := make([]any, len(types))
+	dest := make([]any, len(types))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	for i, val := range vals {
+		vals[i] = normalizeValue(dialect, types[i], val)
+	}
+	return vals, nil
+}
+
+// Converts the []byte values drivers return for text, numeric and time columns (e.g. mysql) into strings, numbers and
+// time.Time. Time values are parsed with the TimeFormat of the dialect in its TimeLocation, or the location set with
+// SetTimeLocation.
+// Values of binary and unknown columns are returned unchanged.
+func normalizeValue(dialect SQLDialect, dbType string, val any) any {
+	b, ok := val.([]byte)
+	if !ok {
+		return val
+	}
+	dbType = strings.ToUpper(dbType)
+	if i := strings.IndexRune(dbType, '('); i >= 0 {
+		dbType = strings.TrimSpace(dbType[:i])
+	}
+	s := string(b)
+	switch {
+	case isIntegerType(dbType):
+		if strings.HasPrefix(dbType, "UNSIGNED") {
+			if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return u
+			}
+		} else if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+		return s
+	case isFloatType(dbType):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+		return s
+	case isTimeType(dbType):
+		if t, ok := parseTime(dialect, s); ok {
+			return t
+		}
+		return s
+	case isTextType(dbType):
+		return s
+	}
+	return val
+}
+
+func isIntegerType(dbType string) bool {
+	switch strings.TrimPrefix(dbType, "UNSIGNED ") {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8", "YEAR":
+		return true
+	}
+	return false
+}
+
+func isFloatType(dbType string) bool {
+	switch dbType {
+	case "FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8", "DOUBLE PRECISION":
+		return true
+	}
+	return false
+}
+
+func isTimeType(dbType string) bool {
+	switch dbType {
+	case "DATE", "DATETIME", "DATETIME2", "SMALLDATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return true
+	}
+	return false
+}
+
+func isTextType(dbType string) bool {
+	switch dbType {
+	case "JSON", "JSONB", "ENUM", "SET", "UUID", "XML", "DECIMAL", "NUMERIC", "MONEY", "NAME", "CITEXT", "INET",
+		"CIDR", "TIME":
+		return true
+	}
+	return strings.Contains(dbType, "CHAR") || strings.Contains(dbType, "TEXT")
+}
+
+func parseTime(dialect SQLDialect, s string) (time.Time, bool) {
+	formats := []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+	if do := dialectOptionsOf(dialect); do != nil && do.TimeFormat != "" {
+		formats = append([]string{do.TimeFormat}, formats...)
+	}
+	loc := sqlgen.GetTimeLocation()
+	if s := settingsOf(dialect); s != nil && s.TimeLocation != nil {
+		loc = s.TimeLocation
+	}
+	for _, format := range formats {
+		if t, err := time.ParseInLocation(format, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
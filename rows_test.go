@@ -0,0 +1,285 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type rowsSuite struct {
+	suite.Suite
+}
+
+func (rs *rowsSuite) mockRows(mock sqlmock.Sqlmock) *sqlmock.Rows {
+	return mock.NewRowsWithColumnDefinition(
+		mock.NewColumn("id").OfType("BIGINT", []byte{}),
+		mock.NewColumn("name").OfType("VARCHAR", []byte{}),
+		mock.NewColumn("created").OfType("DATETIME", []byte{}),
+		mock.NewColumn("price").OfType("DOUBLE", []byte{}),
+		mock.NewColumn("data").OfType("BLOB", []byte{}),
+	).
+		AddRow([]byte("1"), []byte("Bob"), []byte("2023-01-02 03:04:05"), []byte("1.5"), []byte{0x01}).
+		AddRow([]byte("2"), nil, nil, []byte("2"), nil)
+}
+
+func (rs *rowsSuite) TestQueryRows_records() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery("SELECT \\* FROM `items`").WillReturnRows(rs.mockRows(mock))
+	mock.ExpectQuery("SELECT \\* FROM `items`").WillReturnRows(rs.mockRows(mock))
+
+	db := builder.New("mysql", sqlx.NewSqlConnFromDB(mDB))
+	var records []exp.Record
+	rs.NoError(db.From("items").QueryRows(&records))
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	rs.Equal([]exp.Record{
+		{"id": int64(1), "name": "Bob", "created": created, "price": 1.5, "data": []byte{0x01}},
+		{"id": int64(2), "name": nil, "created": nil, "price": float64(2), "data": nil},
+	}, records)
+
+	var maps []map[string]any
+	rs.NoError(db.From("items").QueryRows(&maps))
+	rs.Len(maps, 2)
+	rs.Equal("Bob", maps[0]["name"])
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func (rs *rowsSuite) TestQueryRows_timeLocation() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery("SELECT \\* FROM `items`").WillReturnRows(rs.mockRows(mock))
+
+	loc := time.FixedZone("test", 3600)
+	db := builder.New("mysql", sqlx.NewSqlConnFromDB(mDB), builder.Options{TimeLocation: loc})
+	var records []exp.Record
+	rs.NoError(db.From("items").QueryRows(&records))
+	rs.Len(records, 2)
+	rs.Equal(time.Date(2023, 1, 2, 3, 4, 5, 0, loc), records[0]["created"])
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+type rowsTestUntagged struct {
+	ID   int64 `db:"id"`
+	Name string
+}
+
+func (rs *rowsSuite) TestQueryRows_columnMapOptions() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery(`SELECT \* FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "NAME"}).AddRow(int64(1), "Bob").AddRow(int64(2), "Sally"))
+	mock.ExpectQuery(`SELECT \* FROM "items" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "NAME"}).AddRow(int64(1), "Bob"))
+	mock.ExpectQuery(`SELECT "id" FROM "items" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+	conn := sqlx.NewSqlConnFromDB(mDB)
+	renamed := builder.New("default", conn, builder.Options{ColumnRenameFunction: strings.ToUpper})
+	var items []rowsTestUntagged
+	rs.NoError(renamed.From("items").QueryRows(&items))
+	rs.Equal([]rowsTestUntagged{{ID: 1, Name: "Bob"}, {ID: 2, Name: "Sally"}}, items)
+	var item rowsTestUntagged
+	rs.NoError(renamed.From("items").QueryRow(&item))
+	rs.Equal(rowsTestUntagged{ID: 1, Name: "Bob"}, item)
+
+	// every column of the struct must be selected unless untagged fields are ignored
+	ignored := builder.New("default", conn, builder.Options{IgnoreUntaggedFields: true})
+	item = rowsTestUntagged{}
+	rs.NoError(ignored.From("items").Select("id").QueryRow(&item))
+	rs.Equal(rowsTestUntagged{ID: 1}, item)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+type rowsTestTagged struct {
+	ID   int64          `db:"id"`
+	Name sql.NullString `db:"name"`
+}
+
+func (rs *rowsSuite) TestQueryRows_flatStructWithOptions() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`SELECT \* FROM "items"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Bob").AddRow(int64(2), nil))
+	}
+
+	// options that do not change the column map still scan with go-zero, the connection has no raw *sql.DB
+	conn := noRawDBConn{SqlConn: sqlx.NewSqlConnFromDB(mDB)}
+	expected := []rowsTestTagged{{ID: 1, Name: sql.NullString{String: "Bob", Valid: true}}, {ID: 2}}
+	for _, opts := range []builder.Options{
+		{TimeLocation: time.FixedZone("test", 3600)},
+		{ColumnRenameFunction: strings.ToLower},
+	} {
+		var items []rowsTestTagged
+		rs.NoError(builder.New("default", conn, opts).From("items").QueryRows(&items))
+		rs.Equal(expected, items)
+	}
+
+	var items []rowsTestTagged
+	renamed := builder.New("default", conn, builder.Options{ColumnRenameFunction: strings.ToUpper})
+	rs.EqualError(renamed.From("items").QueryRows(&items), "builder: raw db not available")
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func (rs *rowsSuite) TestQueryRow_record() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery("SELECT \\* FROM `items` LIMIT 1").WillReturnRows(rs.mockRows(mock))
+	mock.ExpectQuery("SELECT \\* FROM `items` LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	db := builder.New("mysql", sqlx.NewSqlConnFromDB(mDB))
+	var record exp.Record
+	rs.NoError(db.From("items").QueryRow(&record))
+	rs.Equal(int64(1), record["id"])
+	rs.Equal("Bob", record["name"])
+
+	var m map[string]any
+	rs.ErrorIs(db.From("items").QueryRow(&m), sqlx.ErrNotFound)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func (rs *rowsSuite) TestQueryRows_resultSet() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery("SELECT \\* FROM `items`").WillReturnRows(rs.mockRows(mock))
+	mock.ExpectQuery("SELECT \\* FROM `items`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	db := builder.New("mysql", sqlx.NewSqlConnFromDB(mDB))
+	var result builder.ResultSet
+	rs.NoError(db.From("items").QueryRows(&result))
+	rs.Equal([]string{"id", "name", "created", "price", "data"}, result.Columns)
+	rs.Equal([]string{"BIGINT", "VARCHAR", "DATETIME", "DOUBLE", "BLOB"}, result.Types)
+	rs.Len(result.Values, 2)
+	rs.Equal([]any{int64(2), nil, nil, float64(2), nil}, result.Values[1])
+
+	rs.NoError(db.From("items").QueryRows(&result))
+	rs.Equal([]string{"id"}, result.Columns)
+	rs.Empty(result.Values)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func (rs *rowsSuite) TestQueryRows_inTransaction() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Bob"))
+	mock.ExpectCommit()
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	var records []exp.Record
+	rs.NoError(db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		return td.From("items").QueryRowsCtx(ctx, &records)
+	}))
+	rs.Equal([]exp.Record{{"id": int64(1), "name": "Bob"}}, records)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+type rowsTestUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type rowsTestOrder struct {
+	ID    int64   `db:"id"`
+	Total float64 `db:"total"`
+}
+
+type rowsTestUserOrder struct {
+	User  rowsTestUser   `db:"user" builder:"table=u"`
+	Order *rowsTestOrder `db:"order" builder:"table=o"`
+}
+
+func (rs *rowsSuite) TestQueryRows_nestedStructs() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery(
+		`SELECT "o"."id" AS "order.id", "o"."total" AS "order.total", "u"."id" AS "user.id", "u"."name" AS "user.name" ` +
+			`FROM "users" AS "u" LEFT JOIN "orders" AS "o" ON \("o"."user_id" = "u"."id"\)`,
+	).WillReturnRows(sqlmock.NewRows([]string{"order.id", "order.total", "user.id", "user.name"}).
+		AddRow(int64(10), 9.5, int64(1), "Bob").
+		AddRow(nil, nil, int64(2), "Sally"))
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	ds := db.From(builder.T("users").As("u")).
+		LeftJoin(builder.T("orders").As("o"), builder.On(builder.I("o.user_id").Eq(builder.I("u.id"))))
+	var userOrders []rowsTestUserOrder
+	rs.NoError(ds.QueryRows(&userOrders))
+	rs.Equal([]rowsTestUserOrder{
+		{User: rowsTestUser{ID: 1, Name: "Bob"}, Order: &rowsTestOrder{ID: 10, Total: 9.5}},
+		{User: rowsTestUser{ID: 2, Name: "Sally"}},
+	}, userOrders)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func (rs *rowsSuite) TestQueryRow_nestedStruct() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectQuery(`SELECT "u"."id" AS "user.id", "u"."name" AS "user.name" FROM "users" AS "u" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"user.id", "user.name"}).AddRow(int64(1), "Bob"))
+	mock.ExpectQuery(`SELECT "u"."id" AS "user.id", "u"."name" AS "user.name" FROM "users" AS "u" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"user.id", "user.name"}).AddRow(int64(1), "Bob"))
+	mock.ExpectQuery(`SELECT "u"."id" AS "user.id", "u"."name" AS "user.name" FROM "users" AS "u" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"user.id", "user.name"}))
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	ds := db.From(builder.T("users").As("u")).Select(
+		builder.I("u.id").As(exp.NewIdentifierExpression("", "", "user.id")),
+		builder.I("u.name").As(exp.NewIdentifierExpression("", "", "user.name")),
+	)
+	var userOrder rowsTestUserOrder
+	rs.ErrorIs(ds.QueryRow(&userOrder), sqlx.ErrNotMatchDestination)
+	rs.NoError(ds.QueryRowPartial(&userOrder))
+	rs.Equal(rowsTestUserOrder{User: rowsTestUser{ID: 1, Name: "Bob"}}, userOrder)
+	rs.ErrorIs(ds.QueryRowPartial(&userOrder), sqlx.ErrNotFound)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+type rowsTestSettings struct {
+	Theme string `json:"theme"`
+}
+
+type rowsTestConverted struct {
+	ID       int64             `db:"id"`
+	Settings *rowsTestSettings `db:"settings" builder:"json"`
+	Tags     []string          `db:"tags" builder:"list"`
+}
+
+func (rs *rowsSuite) TestQueryRows_converters() {
+	mDB, mock, err := sqlmock.New()
+	rs.NoError(err)
+	mock.ExpectExec(`INSERT INTO "items" \("id", "settings", "tags"\) VALUES \(1, '\{"theme":"dark"\}', 'a,b'\)`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT \* FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "settings", "tags"}).
+			AddRow(int64(1), []byte(`{"theme":"dark"}`), []byte("a,b")).
+			AddRow(int64(2), nil, []byte("")))
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	_, err = db.Insert("items").Rows(rowsTestConverted{
+		ID: 1, Settings: &rowsTestSettings{Theme: "dark"}, Tags: []string{"a", "b"},
+	}).Exec()
+	rs.NoError(err)
+
+	var items []rowsTestConverted
+	rs.NoError(db.From("items").QueryRows(&items))
+	rs.Equal([]rowsTestConverted{
+		{ID: 1, Settings: &rowsTestSettings{Theme: "dark"}, Tags: []string{"a", "b"}},
+		{ID: 2, Tags: []string{}},
+	}, items)
+	rs.NoError(mock.ExpectationsWereMet())
+}
+
+func TestRowsSuite(t *testing.T) {
+	suite.Run(t, new(rowsSuite))
+}
@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -19,26 +20,27 @@ type SelectDataset struct {
 }
 
 // used internally by database to create a database with a specific adapter
-func newDataset(d string, executor sqlx.Session) *SelectDataset {
+func newDataset(d string, executor sqlx.Session, s *settings) *SelectDataset {
 	return &SelectDataset{
-		clauses:  exp.NewSelectClauses(),
-		dialect:  GetDialect(d),
-		executor: executor,
+		clauses:    exp.NewSelectClauses(),
+		dialect:    s.dialect(d),
+		executor:   executor,
+		isPrepared: s.isPrepared(),
 	}
 }
 
 func From(table ...any) *SelectDataset {
-	return newDataset("default", nil).From(table...)
+	return newDataset("default", nil, nil).From(table...)
 }
 
 func Select(cols ...any) *SelectDataset {
-	return newDataset("default", nil).Select(cols...)
+	return newDataset("default", nil, nil).Select(cols...)
 }
 
 // Sets the adapter used to serialize values and create the SQL statement
 func (sd *SelectDataset) WithDialect(dl string) *SelectDataset {
 	ds := sd.copy(sd.GetClauses())
-	ds.dialect = GetDialect(dl)
+	ds.dialect = inheritSettings(GetDialect(dl), ds.dialect)
 	return ds
 }
 
@@ -81,6 +83,23 @@ func (sd *SelectDataset) GetClauses() exp.SelectClauses {
 	return sd.clauses
 }
 
+// Sets a timeout for executing the statement of this dataset. The context passed to QueryRowsCtx etc. times out after
+// timeout, where supported by the dialect the timeout is also enforced by the database (e.g. MAX_EXECUTION_TIME
+// for mysql or SET LOCAL statement_timeout inside of postgres transactions).
+//
+// A timeout less than or equal to zero removes the timeout.
+func (sd *SelectDataset) WithTimeout(timeout time.Duration) *SelectDataset {
+	return sd.copy(sd.clauses.SetTimeout(timeout))
+}
+
+// Adds optimizer hints to the statement, the hints are rendered in a single comment after the SELECT keyword. Calling
+// OptimizerHints multiple times appends the hints.
+//
+//	builder.From("test").OptimizerHints("NO_ICP(test)") -> SELECT /*+ NO_ICP(test) */ ...
+func (sd *SelectDataset) OptimizerHints(hints ...string) *SelectDataset {
+	return sd.copy(sd.clauses.OptimizerHintsAppend(hints...))
+}
+
 // used interally to copy the dataset
 func (sd *SelectDataset) copy(clauses exp.SelectClauses) *SelectDataset {
 	return &SelectDataset{
@@ -95,8 +114,9 @@ func (sd *SelectDataset) copy(clauses exp.SelectClauses) *SelectDataset {
 // Creates a new UpdateDataset using the FROM of this dataset. This method will also copy over the `WITH`, `WHERE`,
 // `ORDER , and `LIMIT`
 func (sd *SelectDataset) Update() *UpdateDataset {
-	u := newUpdateDataset(sd.dialect.Dialect(), sd.executor).
+	u := newUpdateDataset(sd.dialect.Dialect(), sd.executor, nil).
 		Prepared(sd.isPrepared.Bool())
+	u.dialect = sd.dialect
 	if sd.clauses.HasSources() {
 		u = u.Table(sd.GetClauses().From().Columns()[0])
 	}
@@ -122,8 +142,9 @@ func (sd *SelectDataset) Update() *UpdateDataset {
 // Creates a new InsertDataset using the FROM of this dataset. This method will also copy over the `WITH` clause to the
 // insert.
 func (sd *SelectDataset) Insert() *InsertDataset {
-	i := newInsertDataset(sd.dialect.Dialect(), sd.executor).
+	i := newInsertDataset(sd.dialect.Dialect(), sd.executor, nil).
 		Prepared(sd.isPrepared.Bool())
+	i.dialect = sd.dialect
 	if sd.clauses.HasSources() {
 		i = i.Into(sd.GetClauses().From().Columns()[0])
 	}
@@ -138,8 +159,9 @@ func (sd *SelectDataset) Insert() *InsertDataset {
 // Creates a new DeleteDataset using the FROM of this dataset. This method will also copy over the `WITH`, `WHERE`,
 // `ORDER , and `LIMIT`
 func (sd *SelectDataset) Delete() *DeleteDataset {
-	d := newDeleteDataset(sd.dialect.Dialect(), sd.executor).
+	d := newDeleteDataset(sd.dialect.Dialect(), sd.executor, nil).
 		Prepared(sd.isPrepared.Bool())
+	d.dialect = sd.dialect
 	if sd.clauses.HasSources() {
 		d = d.From(sd.clauses.From().Columns()[0])
 	}
@@ -164,7 +186,8 @@ func (sd *SelectDataset) Delete() *DeleteDataset {
 
 // Creates a new TruncateDataset using the FROM of this dataset.
 func (sd *SelectDataset) Truncate() *TruncateDataset {
-	td := newTruncateDataset(sd.dialect.Dialect(), sd.executor)
+	td := newTruncateDataset(sd.dialect.Dialect(), sd.executor, nil)
+	td.dialect = sd.dialect
 	if sd.clauses.HasSources() {
 		td = td.Table(sd.clauses.From())
 	}
@@ -193,6 +216,18 @@ func (sd *SelectDataset) WithRecursive(name string, subquery exp.Expression) *Se
 	return sd.copy(sd.clauses.CommonTablesAppend(exp.NewCommonTableExpression(true, name, subquery)))
 }
 
+// Adds common table expressions (CTE) created with CTE to the WITH clause. Unlike With the column names,
+// materialization and the SEARCH and CYCLE clauses of the CTEs are typed.
+//
+//	WithCTE(builder.CTE("tree", sub).Columns("id", "parent_id").NotMaterialized())
+func (sd *SelectDataset) WithCTE(ctes ...exp.CommonTableExpression) *SelectDataset {
+	clauses := sd.clauses
+	for _, cte := range ctes {
+		clauses = clauses.CommonTablesAppend(cte)
+	}
+	return sd.copy(clauses)
+}
+
 // Adds columns to the SELECT clause. See examples
 // You can pass in the following.
 //
@@ -207,7 +242,7 @@ func (sd *SelectDataset) Select(selects ...any) *SelectDataset {
 	if len(selects) == 0 {
 		return sd.ClearSelect()
 	}
-	return sd.copy(sd.clauses.SetSelect(exp.NewColumnListExpression(selects...)))
+	return sd.copy(sd.clauses.SetSelect(sd.columnList(selects)))
 }
 
 // Adds columns to the SELECT DISTINCT clause. See examples
@@ -227,7 +262,7 @@ func (sd *SelectDataset) SelectDistinct(selects ...any) *SelectDataset {
 		cleared := sd.ClearSelect()
 		return cleared.copy(cleared.clauses.SetDistinct(nil))
 	}
-	return sd.copy(sd.clauses.SetSelect(exp.NewColumnListExpression(selects...)).SetDistinct(exp.NewColumnListExpression()))
+	return sd.copy(sd.clauses.SetSelect(sd.columnList(selects)).SetDistinct(exp.NewColumnListExpression()))
 }
 
 // Resets to SELECT *. If the SelectDistinct or Distinct was used the returned Dataset will have the the dataset set to SELECT *.
@@ -245,7 +280,12 @@ func (sd *SelectDataset) ClearSelect() *SelectDataset {
 //	LiteralExpression: (See Literal) Will use the literal SQL
 //	SQLFunction: (See Func, MIN, MAX, COUNT....)
 func (sd *SelectDataset) SelectAppend(selects ...any) *SelectDataset {
-	return sd.copy(sd.clauses.SelectAppend(exp.NewColumnListExpression(selects...)))
+	return sd.copy(sd.clauses.SelectAppend(sd.columnList(selects)))
+}
+
+// Returns the column list of cols, structs are mapped with the options of the dialect
+func (sd *SelectDataset) columnList(cols []any) exp.ColumnListExpression {
+	return exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(sd.dialect), cols...)
 }
 
 func (sd *SelectDataset) Distinct(on ...any) *SelectDataset {
@@ -564,12 +604,22 @@ func (sd *SelectDataset) ReturnsColumns() bool {
 	return true
 }
 
+// Returns the dataset used to query v. The default SELECT * is replaced with the columns of v when v is a struct with
+// nested structs, the columns of a nested struct are selected from its table and aliased to the prefixed name the
+// struct is scanned from (e.g. "user"."id" AS "user.id")
+func (sd *SelectDataset) selectFor(v any) *SelectDataset {
+	if sd.GetClauses().IsDefaultSelect() && isNestedStructTarget(v, columnMapOptionsOf(sd.dialect)) {
+		return sd.Select(v)
+	}
+	return sd
+}
+
 // Generates the SELECT sql for this dataset and uses Exec#QueryRow to scan the result into a slice of structs
 //
 // QueryRow will only select the columns that can be scanned in to the struct unless you have explicitly selected
 // certain columns. See examples.
 //
-// i: A pointer to a structs
+// i: A pointer to a structs, or a *exp.Record or *map[string]any for columns that are not known at compile time
 func (sd *SelectDataset) QueryRow(v any) error {
 	return sd.QueryRowCtx(context.Background(), v)
 }
@@ -584,15 +634,12 @@ func (sd *SelectDataset) QueryRowCtx(ctx context.Context, v any) error {
 	if sd.executor == nil {
 		return ErrExecutorNotFoundError
 	}
-	ds := sd
-	if sd.GetClauses().IsDefaultSelect() {
-		ds = sd.Select(v)
-	}
-	query, args, err := sd.Limit(1).selectSQLBuilder().ToSQL()
+	ds := sd.selectFor(v)
+	query, args, err := ds.Limit(1).selectSQLBuilder().ToSQL()
 	if err != nil {
 		return err
 	}
-	return ds.executor.QueryRowCtx(ctx, v, query, args...)
+	return ds.session(ctx).QueryRowCtx(ctx, v, query, args...)
 }
 
 // Generates the SELECT sql for this dataset and uses Exec#QueryRow to scan the result into a slice of structs
@@ -615,15 +662,12 @@ func (sd *SelectDataset) QueryRowPartialCtx(ctx context.Context, v any) error {
 	if sd.executor == nil {
 		return ErrExecutorNotFoundError
 	}
-	ds := sd
-	if sd.GetClauses().IsDefaultSelect() {
-		ds = sd.Select(v)
-	}
-	query, args, err := sd.Limit(1).selectSQLBuilder().ToSQL()
+	ds := sd.selectFor(v)
+	query, args, err := ds.Limit(1).selectSQLBuilder().ToSQL()
 	if err != nil {
 		return err
 	}
-	return ds.executor.QueryRowPartialCtx(ctx, v, query, args...)
+	return ds.session(ctx).QueryRowPartialCtx(ctx, v, query, args...)
 }
 
 // Generates the SELECT sql for this dataset and uses Exec#QueryRows to scan the results into a slice of structs.
@@ -631,7 +675,8 @@ func (sd *SelectDataset) QueryRowPartialCtx(ctx context.Context, v any) error {
 // QueryRows will only select the columns that can be scanned in to the struct unless you have explicitly selected
 // certain columns. See examples.
 //
-// i: A pointer to a slice of structs
+// i: A pointer to a slice of structs, or a *[]exp.Record, *[]map[string]any or *ResultSet for columns that are not
+// known at compile time
 func (sd *SelectDataset) QueryRows(v any) error {
 	return sd.QueryRowsCtx(context.Background(), v)
 }
@@ -647,15 +692,12 @@ func (sd *SelectDataset) QueryRowsCtx(ctx context.Context, v any) error {
 	if sd.executor == nil {
 		return ErrExecutorNotFoundError
 	}
-	ds := sd
-	if sd.GetClauses().IsDefaultSelect() {
-		ds = sd.Select(v)
-	}
-	query, args, err := sd.selectSQLBuilder().ToSQL()
+	ds := sd.selectFor(v)
+	query, args, err := ds.selectSQLBuilder().ToSQL()
 	if err != nil {
 		return err
 	}
-	return ds.executor.QueryRowsCtx(ctx, v, query, args...)
+	return ds.session(ctx).QueryRowsCtx(ctx, v, query, args...)
 }
 
 // Generates the SELECT sql for this dataset and uses Exec#QueryRows to scan the results into a slice of structs.
@@ -679,15 +721,12 @@ func (sd *SelectDataset) QueryRowsPartialCtx(ctx context.Context, v any) error {
 	if sd.executor == nil {
 		return ErrExecutorNotFoundError
 	}
-	ds := sd
-	if sd.GetClauses().IsDefaultSelect() {
-		ds = sd.Select(v)
-	}
-	query, args, err := sd.selectSQLBuilder().ToSQL()
+	ds := sd.selectFor(v)
+	query, args, err := ds.selectSQLBuilder().ToSQL()
 	if err != nil {
 		return err
 	}
-	return ds.executor.QueryRowsPartialCtx(ctx, v, query, args...)
+	return ds.session(ctx).QueryRowsPartialCtx(ctx, v, query, args...)
 }
 
 // Generates the SELECT COUNT(*) sql for this dataset and uses Exec#QueryRow to scan the result into an int64.
@@ -702,7 +741,7 @@ func (sd *SelectDataset) CountContext(ctx context.Context) (int64, error) {
 	if err != nil {
 		return count, err
 	}
-	err = sd.executor.QueryRowCtx(ctx, &count, query, args...)
+	err = sd.session(ctx).QueryRowCtx(ctx, &count, query, args...)
 	return count, err
 }
 
@@ -730,7 +769,21 @@ func (sd *SelectDataset) PluckContext(ctx context.Context, v any, col string) er
 	if err != nil {
 		return err
 	}
-	return sd.executor.QueryRowsPartialCtx(ctx, v, query, args...)
+	return sd.session(ctx).QueryRowsPartialCtx(ctx, v, query, args...)
+}
+
+// Generates the fingerprint of the SELECT statement, the values of the dataset are replaced with placeholders so
+// datasets with the same shape have the same fingerprint. See Fingerprint.
+//
+// Errors:
+//   - There is an error generating the SQL
+func (sd *SelectDataset) Fingerprint() (Fingerprint, error) {
+	if sd.err != nil {
+		return Fingerprint{}, sd.err
+	}
+	return fingerprint(func(b sb.SQLBuilder) {
+		sd.dialect.ToSelectSQL(b, sd.GetClauses())
+	})
 }
 
 func (sd *SelectDataset) selectSQLBuilder() sb.SQLBuilder {
@@ -741,3 +794,9 @@ func (sd *SelectDataset) selectSQLBuilder() sb.SQLBuilder {
 	sd.dialect.ToSelectSQL(buf, sd.GetClauses())
 	return buf
 }
+
+// returns the session to execute statements with, see datasetSession
+func (sd *SelectDataset) session(ctx context.Context) sqlx.Session {
+	stmt := newStatement("SELECT", sd.clauses.From())
+	return datasetSession(ctx, sd.dialect, sd.executor, stmt, sd.clauses.Timeout())
+}
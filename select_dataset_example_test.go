@@ -1125,6 +1125,29 @@ func ExampleSelectDataset_Select_withStruct() {
 	// SELECT "address", "email_address", "name" FROM "test"
 }
 
+func ExampleSelectDataset_Select_withNestedStruct() {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	type order struct {
+		ID int64 `db:"id"`
+	}
+	type userOrder struct {
+		User  user  `db:"user" builder:"table=u"`
+		Order order `db:"order" builder:"table=o"`
+	}
+
+	sql, _, _ := builder.From(builder.T("users").As("u")).
+		Join(builder.T("orders").As("o"), builder.On(builder.I("o.user_id").Eq(builder.I("u.id")))).
+		Select(userOrder{}).
+		ToSQL()
+	fmt.Println(sql)
+
+	// Output:
+	// SELECT "o"."id" AS "order.id", "u"."id" AS "user.id", "u"."name" AS "user.name" FROM "users" AS "u" INNER JOIN "orders" AS "o" ON ("o"."user_id" = "u"."id")
+}
+
 func ExampleSelectDataset_Distinct() {
 	sql, _, _ := builder.From("test").Select("a", "b").Distinct().ToSQL()
 	fmt.Println(sql)
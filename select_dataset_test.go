@@ -2,6 +2,7 @@ package builder_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Tooooommy/builder/v9"
@@ -93,6 +94,25 @@ func (sds *selectDatasetSuite) TestGetClauses() {
 	sds.Equal(ce, ds.GetClauses())
 }
 
+func (sds *selectDatasetSuite) TestWithTimeout() {
+	ds := builder.From("test")
+	ce := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression(builder.I("test")))
+	sds.Equal(ce.SetTimeout(time.Second), ds.WithTimeout(time.Second).GetClauses())
+	sds.Equal(ce, ds.WithTimeout(time.Second).WithTimeout(0).GetClauses())
+	sds.Equal(ce, ds.GetClauses())
+}
+
+func (sds *selectDatasetSuite) TestOptimizerHints() {
+	ds := builder.From("test")
+	ce := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression(builder.I("test")))
+	sds.Equal(ce.OptimizerHintsAppend("NO_ICP(test)"), ds.OptimizerHints("NO_ICP(test)").GetClauses())
+	sds.Equal(
+		ce.OptimizerHintsAppend("NO_ICP(test)", "BKA(test)"),
+		ds.OptimizerHints("NO_ICP(test)").OptimizerHints("BKA(test)").GetClauses(),
+	)
+	sds.Equal(ce, ds.GetClauses())
+}
+
 func (sds *selectDatasetSuite) TestUpdate() {
 	where := builder.Ex{"a": 1}
 	from := builder.From("cte")
@@ -196,6 +216,136 @@ func (sds *selectDatasetSuite) TestWithRecursive() {
 	)
 }
 
+func (sds *selectDatasetSuite) TestWithCTE() {
+	from := builder.From("cte")
+	bd := builder.From("test")
+	cte := builder.CTE("test-cte", from).Columns("a").Materialized()
+	sds.assertCases(
+		selectTestCase{
+			ds: bd.WithCTE(cte),
+			clauses: exp.NewSelectClauses().
+				SetFrom(exp.NewColumnListExpression("test")).
+				CommonTablesAppend(cte),
+		},
+		selectTestCase{
+			ds:      bd,
+			clauses: exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression("test")),
+		},
+	)
+}
+
+func (sds *selectDatasetSuite) TestWithCTE_toSQL() {
+	tree := builder.From("node").Select("id", "parent_id").Where(builder.C("parent_id").IsNull()).
+		UnionAll(builder.From(builder.T("node").As("n")).
+			Join(builder.T("tree").As("t"), builder.On(builder.I("n.parent_id").Eq(builder.I("t.id")))).
+			Select("n.id", "n.parent_id"))
+	ds := builder.From("tree").WithCTE(
+		builder.CTE("tree", tree).Columns("id", "parent_id").
+			Search(builder.SearchDepthFirst("ordercol", "id")).
+			Cycle(builder.Cycle("is_cycle", "path", "id")),
+		builder.CTE("active", builder.From("user").Where(builder.C("active").IsTrue())).NotMaterialized(),
+	).Order(builder.C("ordercol").Asc())
+	sql, _, err := ds.ToSQL()
+	sds.NoError(err)
+	sds.Equal(`WITH RECURSIVE "tree"("id", "parent_id") AS (SELECT "id", "parent_id" FROM "node" WHERE ("parent_id" IS NULL) `+
+		`UNION ALL (SELECT "n"."id", "n"."parent_id" FROM "node" AS "n" INNER JOIN "tree" AS "t" ON ("n"."parent_id" = "t"."id"))) `+
+		`SEARCH DEPTH FIRST BY "id" SET "ordercol" CYCLE "id" SET "is_cycle" USING "path", `+
+		`"active" AS NOT MATERIALIZED (SELECT * FROM "user" WHERE ("active" IS TRUE)) `+
+		`SELECT * FROM "tree" ORDER BY "ordercol" ASC`, sql)
+
+	_, _, err = builder.Dialect("mysql").From("tree").WithCTE(builder.CTE("tree", tree)).ToSQL()
+	sds.EqualError(err, "builder: dialect does not support CTE WITH clause [dialect=mysql]")
+}
+
+func (sds *selectDatasetSuite) TestFromValues_toSQL() {
+	v := builder.Values([][]any{{1, "a"}, {2, "b"}}).As("v", "id", "name")
+
+	sql, args, err := builder.From(v).Prepared(true).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT * FROM (VALUES (?, ?), (?, ?)) AS "v"("id", "name")`, sql)
+	sds.Equal([]any{int64(1), "a", int64(2), "b"}, args)
+
+	sql, _, err = builder.From("item").
+		Join(v, builder.On(builder.I("v.id").Eq(builder.I("item.id")))).
+		Select("item.id", "v.name").ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT "item"."id", "v"."name" FROM "item" `+
+		`INNER JOIN (VALUES (1, 'a'), (2, 'b')) AS "v"("id", "name") ON ("v"."id" = "item"."id")`, sql)
+
+	sql, _, err = builder.From("v").WithCTE(builder.CTE("v", builder.Values([][]any{{1, "a"}})).Columns("id", "name")).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`WITH "v"("id", "name") AS (VALUES (1, 'a')) SELECT * FROM "v"`, sql)
+
+	sql, _, err = builder.Dialect("mysql8").From(v).ToSQL()
+	sds.NoError(err)
+	sds.Equal("SELECT * FROM (VALUES ROW(1, 'a'), ROW(2, 'b')) AS `v`(`id`, `name`)", sql)
+
+	sql, args, err = builder.Dialect("mysql").From(v).Prepared(true).ToSQL()
+	sds.NoError(err)
+	sds.Equal("SELECT * FROM (SELECT ? AS `id`, ? AS `name` UNION ALL SELECT ?, ?) AS `v`", sql)
+	sds.Equal([]any{int64(1), "a", int64(2), "b"}, args)
+
+	_, _, err = builder.From(builder.Values([][]any{{1}, {2, 3}})).ToSQL()
+	sds.EqualError(err, "builder: rows with different value length expected 1 got 2")
+}
+
+func (sds *selectDatasetSuite) TestFromTableFunc_toSQL() {
+	sql, args, err := builder.From(builder.TableFunc("generate_series", 1, 3).As("s", "n")).Prepared(true).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT * FROM generate_series(?, ?) AS "s"("n")`, sql)
+	sds.Equal([]any{int64(1), int64(3)}, args)
+
+	sql, _, err = builder.From("item").
+		CrossJoin(builder.Lateral(builder.TableFunc("unnest", builder.I("item.tags")).WithOrdinality().As("t", "tag", "n"))).
+		Select("item.id", "t.tag", "t.n").ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT "item"."id", "t"."tag", "t"."n" FROM "item" `+
+		`CROSS JOIN LATERAL unnest("item"."tags") WITH ORDINALITY AS "t"("tag", "n")`, sql)
+
+	sql, _, err = builder.From(builder.TableFunc("jsonb_to_recordset", `[{"a":1}]`).As("r").Column("a", "int")).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT * FROM jsonb_to_recordset('[{"a":1}]') AS "r"("a" int)`, sql)
+
+	sql, _, err = builder.Dialect("mysql8").
+		From(builder.TableFunc("JSON_TABLE", `[1, 2]`, builder.L(`'$[*]' COLUMNS (x INT PATH '$')`)).As("jt")).ToSQL()
+	sds.NoError(err)
+	sds.Equal("SELECT * FROM JSON_TABLE('[1, 2]', '$[*]' COLUMNS (x INT PATH '$')) AS `jt`", sql)
+
+	_, _, err = builder.Dialect("mysql8").From(builder.TableFunc("JSON_TABLE").WithOrdinality()).ToSQL()
+	sds.EqualError(err, "builder: dialect does not support WITH ORDINALITY [dialect=mysql8]")
+
+	_, _, err = builder.Dialect("mysql").From(builder.TableFunc("JSON_TABLE")).ToSQL()
+	sds.EqualError(err, "builder: dialect does not support table functions [dialect=mysql]")
+}
+
+func (sds *selectDatasetSuite) TestWhereArray_toSQL() {
+	ds := builder.From("item").Where(
+		builder.C("tags").Contains([]string{"a", "b"}),
+		builder.C("id").EqAny(builder.Array([]int{1, 2})),
+	)
+	sql, args, err := ds.ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT * FROM "item" WHERE (("tags" @> ARRAY['a', 'b']) AND ("id" = ANY(ARRAY[1, 2])))`, sql)
+	sds.Empty(args)
+
+	sql, args, err = ds.Prepared(true).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT * FROM "item" WHERE (("tags" @> ?) AND ("id" = ANY(?)))`, sql)
+	sds.Equal([]any{builder.Array([]string{"a", "b"}), builder.Array([]int{1, 2})}, args)
+
+	sql, _, err = builder.From("item").
+		CrossJoin(builder.Lateral(builder.From(builder.Unnest(builder.I("item.tags")).As("t", "tag")))).
+		Select("item.id", builder.ArrayLength(builder.I("item.tags"), 1)).
+		Where(builder.Ex{"item.tags": builder.Op{"overlaps": []string{"a"}}}).ToSQL()
+	sds.NoError(err)
+	sds.Equal(`SELECT "item"."id", array_length("item"."tags", 1) FROM "item" `+
+		`CROSS JOIN LATERAL (SELECT * FROM unnest("item"."tags") AS "t"("tag")) `+
+		`WHERE ("item"."tags" && ARRAY['a'])`, sql)
+
+	_, _, err = builder.Dialect("mysql").From("item").Where(builder.C("tags").Contains([]string{"a"})).ToSQL()
+	sds.EqualError(err, "builder: dialect does not support arrays [dialect=mysql]")
+}
+
 func (sds *selectDatasetSuite) TestSelect() {
 	bd := builder.From("test")
 	sds.assertCases(
@@ -1351,6 +1501,26 @@ func (sds *selectDatasetSuite) TestQueryRows() {
 	sds.Equal(builder.ErrExecutorNotFoundError, builder.From("items").QueryRows(items))
 }
 
+func (sds *selectDatasetSuite) TestQueryRows_withTimeout() {
+	mDB, sqlMock, err := sqlmock.New()
+	sds.NoError(err)
+	sqlMock.ExpectQuery(`SELECT "address", "name" FROM "items"`).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+	sqlMock.ExpectQuery(`SELECT "address", "name" FROM "items"`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).FromCSVString("111 Test Addr,Test1"))
+
+	db := builder.New("mock", sqlx.NewSqlConnFromDB(mDB))
+	bs := db.From("items").Select("address", "name").WithTimeout(10 * time.Millisecond)
+	var items []dsTestActionItem
+	sds.Error(bs.QueryRows(&items))
+	sds.Empty(items)
+
+	sds.NoError(bs.WithTimeout(time.Second).QueryRows(&items))
+	sds.Equal([]dsTestActionItem{{Address: "111 Test Addr", Name: "Test1"}}, items)
+	sds.NoError(sqlMock.ExpectationsWereMet())
+}
+
 func (sds *selectDatasetSuite) TestQueryRows_WithPreparedStatements() {
 	mDB, sqlMock, err := sqlmock.New()
 	sds.NoError(err)
@@ -0,0 +1,1324 @@
+package builder
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Tooooommy/builder/v9/exp"
+)
+
+type (
+	// The error returned when a query can not be parsed, see ParseSelect
+	ParseError struct {
+		// The description of the error
+		Msg string
+		// The byte offset of the error in the query
+		Offset int
+		// The line of the error, starting at 1
+		Line int
+		// The column of the error, starting at 1
+		Column int
+	}
+
+	tokenKind int
+
+	token struct {
+		kind tokenKind
+		// the text of the token, without quotes for strings and quoted identifiers
+		text string
+		pos  int
+	}
+
+	selectParser struct {
+		query  string
+		tokens []token
+		next   int
+		base   *SelectDataset
+		args   []any
+		// the index of the next argument of a ? placeholder
+		argIndex int
+		// the number of arguments bound to placeholders
+		bound int
+		// set to true to fold unquoted identifiers to lower case, see SQLDialectOptions.LowercaseUnquotedIdentifiers
+		lowercase bool
+	}
+)
+
+const (
+	eofToken tokenKind = iota
+	wordToken
+	quotedIdentToken
+	stringToken
+	numberToken
+	placeholderToken
+	symbolToken
+)
+
+// words that can not be used as unquoted identifiers or aliases
+var reservedWords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true,
+	"CROSS": true, "DESC": true, "DISTINCT": true, "ELSE": true, "END": true, "ESCAPE": true, "EXCEPT": true,
+	"EXISTS": true, "FALSE": true, "FETCH": true, "FILTER": true, "FOR": true, "FROM": true, "FULL": true,
+	"GROUP": true, "HAVING": true, "ILIKE": true, "IN": true, "INNER": true, "INTERSECT": true, "INTO": true,
+	"IS": true, "JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true, "LIMIT": true, "NATURAL": true,
+	"NOT": true, "NULL": true, "OFFSET": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+	"RETURNING": true, "RIGHT": true, "SELECT": true, "THEN": true, "TRUE": true, "UNION": true, "USING": true,
+	"WHEN": true, "WHERE": true, "WINDOW": true, "WITH": true,
+}
+
+// reserved words starting a construct the parser does not support
+var unsupportedWords = map[string]bool{
+	"EXCEPT": true, "ESCAPE": true, "FETCH": true, "FILTER": true, "FOR": true, "INTO": true, "LATERAL": true,
+	"OVER": true, "RETURNING": true, "WINDOW": true,
+}
+
+// a decimal number with an optional exponent (e.g. 1.5, .5 or 1e10)
+var numberRegexp = regexp.MustCompile(`^([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+var comparisonOperations = map[string]exp.BooleanOperation{
+	"=": exp.EqOp, "<>": exp.NeqOp, "!=": exp.NeqOp, "<": exp.LtOp, "<=": exp.LteOp, ">": exp.GtOp, ">=": exp.GteOp,
+}
+
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("builder: %s at line %d, column %d", pe.Msg, pe.Line, pe.Column)
+}
+
+// Parses a SELECT statement into a SelectDataset so it can be modified like any other dataset. The arguments are
+// bound to the ? and $1 placeholders of the query. Returns a *ParseError with the position of the first unsupported
+// construct.
+//
+// The parser supports a subset of SELECT: columns and aliases, FROM with joins and sub selects, WHERE with
+// comparisons, IN, BETWEEN, LIKE/ILIKE and IS, GROUP BY, HAVING, ORDER BY, LIMIT/OFFSET, UNION/INTERSECT, CTEs,
+// sub queries, functions, CASE and CAST.
+//
+//	ds, err := builder.ParseSelect(`SELECT id, name FROM "user" WHERE status = ? ORDER BY id`, "active")
+//	ds = ds.Where(builder.C("tenant_id").Eq(10)).Limit(100)
+func ParseSelect(query string, args ...any) (*SelectDataset, error) {
+	return parseSelect(newDataset("default", nil, nil), query, args)
+}
+
+// Parses a SELECT statement into a SelectDataset of the dialect. See ParseSelect
+func (dw DialectWrapper) ParseSelect(query string, args ...any) (*SelectDataset, error) {
+	return parseSelect(newDataset(dw.dialect, nil, dw.settings), query, args)
+}
+
+// Parses a SELECT statement into a SelectDataset executed by the Database. See ParseSelect
+func (d *Database) ParseSelect(query string, args ...any) (*SelectDataset, error) {
+	return parseSelect(newDataset(d.dialect, d.conn, d.settings), query, args)
+}
+
+// Parses a SELECT statement into a SelectDataset executed in the transaction. See ParseSelect
+func (td *TxDatabase) ParseSelect(query string, args ...any) (*SelectDataset, error) {
+	return parseSelect(newDataset(td.dialect, td.session, td.settings), query, args)
+}
+
+func parseSelect(base *SelectDataset, query string, args []any) (*SelectDataset, error) {
+	p := &selectParser{query: query, base: base, args: args}
+	if do := dialectOptionsOf(base.dialect); do != nil {
+		p.lowercase = do.LowercaseUnquotedIdentifiers
+	}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	c, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	p.acceptSymbol(";")
+	if t := p.peek(); t.kind != eofToken {
+		return nil, p.unexpected(t, "end of query")
+	}
+	if p.bound < len(args) {
+		return nil, p.errorAt(len(query), "%d arguments provided for %d placeholders", len(args), p.bound)
+	}
+	return base.copy(c), nil
+}
+
+// nolint:gocyclo // not complex just long
+func (p *selectParser) tokenize() error {
+	q := p.query
+	for i := 0; i < len(q); {
+		ch := q[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case strings.HasPrefix(q[i:], "--"):
+			for i < len(q) && q[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(q[i:], "/*"):
+			end := strings.Index(q[i+2:], "*/")
+			if end < 0 {
+				return p.errorAt(i, "unterminated comment")
+			}
+			i += end + 4
+		case isIdentifierByte(ch) && ch != '$' && !isDigit(ch):
+			start := i
+			for i < len(q) && isIdentifierByte(q[i]) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: wordToken, text: q[start:i], pos: start})
+		case ch == '"' || ch == '`' || ch == '[':
+			closing := ch
+			if ch == '[' {
+				closing = ']'
+			}
+			text, end, ok := readQuoted(q, i, closing)
+			if !ok {
+				return p.errorAt(i, "unterminated quoted identifier")
+			}
+			// identifiers are generated without escaping so a quote character could end the generated identifier
+			if strings.ContainsAny(text, "\"`[]") {
+				return p.errorAt(i, "unsupported quote character in identifier")
+			}
+			p.tokens = append(p.tokens, token{kind: quotedIdentToken, text: text, pos: i})
+			i = end
+		case ch == '\'':
+			text, end, ok := readQuoted(q, i, '\'')
+			if !ok {
+				return p.errorAt(i, "unterminated string")
+			}
+			p.tokens = append(p.tokens, token{kind: stringToken, text: text, pos: i})
+			i = end
+		case isDigit(ch) || (ch == '.' && i+1 < len(q) && isDigit(q[i+1])):
+			start := i
+			for i < len(q) && (isDigit(q[i]) || q[i] == '.') {
+				i++
+			}
+			if i < len(q) && (q[i] == 'e' || q[i] == 'E') {
+				i++
+				if i < len(q) && (q[i] == '+' || q[i] == '-') {
+					i++
+				}
+				for i < len(q) && isDigit(q[i]) {
+					i++
+				}
+			}
+			p.tokens = append(p.tokens, token{kind: numberToken, text: q[start:i], pos: start})
+		case ch == '?':
+			p.tokens = append(p.tokens, token{kind: placeholderToken, text: "?", pos: i})
+			i++
+		case ch == '$' && i+1 < len(q) && isDigit(q[i+1]):
+			start := i
+			for i++; i < len(q) && isDigit(q[i]); i++ {
+			}
+			p.tokens = append(p.tokens, token{kind: placeholderToken, text: q[start:i], pos: start})
+		default:
+			symbol := ""
+			for _, s := range []string{"<=", ">=", "<>", "!=", "||", "::", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", ";"} {
+				if strings.HasPrefix(q[i:], s) {
+					symbol = s
+					break
+				}
+			}
+			if symbol == "" {
+				return p.errorAt(i, "unexpected character %q", ch)
+			}
+			p.tokens = append(p.tokens, token{kind: symbolToken, text: symbol, pos: i})
+			i += len(symbol)
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: eofToken, pos: len(q)})
+	return nil
+}
+
+// reads a quoted string or identifier starting at start, doubled closing quotes are unescaped
+func readQuoted(q string, start int, closing byte) (text string, end int, ok bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(q); i++ {
+		if q[i] != closing {
+			b.WriteByte(q[i])
+			continue
+		}
+		if i+1 < len(q) && q[i+1] == closing {
+			b.WriteByte(closing)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+func (p *selectParser) errorAt(pos int, msg string, args ...any) *ParseError {
+	line, col := 1, 1
+	for _, r := range p.query[:pos] {
+		if r == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return &ParseError{Msg: fmt.Sprintf(msg, args...), Offset: pos, Line: line, Column: col}
+}
+
+func (p *selectParser) unexpected(t token, expected string) *ParseError {
+	if t.kind == wordToken && unsupportedWords[strings.ToUpper(t.text)] {
+		return p.errorAt(t.pos, "unsupported %s", strings.ToUpper(t.text))
+	}
+	switch t.kind {
+	case eofToken:
+		return p.errorAt(t.pos, "unexpected end of query, expected %s", expected)
+	case stringToken:
+		return p.errorAt(t.pos, "unexpected string '%s', expected %s", t.text, expected)
+	}
+	return p.errorAt(t.pos, "unexpected %s, expected %s", t.text, expected)
+}
+
+func (p *selectParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *selectParser) peekAt(offset int) token {
+	if p.next+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.next+offset]
+}
+
+func (p *selectParser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != eofToken {
+		p.next++
+	}
+	return t
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.kind == wordToken && strings.EqualFold(t.text, keyword)
+}
+
+func isSymbol(t token, symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+// consumes the keywords if the next tokens match them
+func (p *selectParser) acceptKeyword(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !isKeyword(p.peekAt(i), keyword) {
+			return false
+		}
+	}
+	p.next += len(keywords)
+	return true
+}
+
+func (p *selectParser) acceptSymbol(symbol string) bool {
+	if isSymbol(p.peek(), symbol) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *selectParser) expectKeyword(keywords ...string) error {
+	for _, keyword := range keywords {
+		if t := p.peek(); !isKeyword(t, keyword) {
+			return p.unexpected(t, keyword)
+		}
+		p.next++
+	}
+	return nil
+}
+
+func (p *selectParser) expectSymbol(symbol string) error {
+	if t := p.peek(); !isSymbol(t, symbol) {
+		return p.unexpected(t, symbol)
+	}
+	p.next++
+	return nil
+}
+
+// returns true if the next token is an identifier, i.e. a quoted identifier or a word that is not reserved
+func (p *selectParser) peekIdentifier() bool {
+	t := p.peek()
+	return t.kind == quotedIdentToken || (t.kind == wordToken && !reservedWords[strings.ToUpper(t.text)])
+}
+
+func (p *selectParser) identifier() (string, error) {
+	if !p.peekIdentifier() {
+		return "", p.unexpected(p.peek(), "identifier")
+	}
+	return p.identifierText(p.advance()), nil
+}
+
+// Returns the name of an identifier token, unquoted identifiers are folded to lower case if the dialect does
+func (p *selectParser) identifierText(t token) string {
+	if p.lowercase && t.kind == wordToken {
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+// parses an optional [AS] alias
+func (p *selectParser) alias() (string, error) {
+	if p.acceptKeyword("AS") {
+		return p.identifier()
+	}
+	if p.peekIdentifier() {
+		return p.identifierText(p.advance()), nil
+	}
+	return "", nil
+}
+
+func (p *selectParser) startsQuery() bool {
+	return isKeyword(p.peek(), "SELECT") || isKeyword(p.peek(), "WITH")
+}
+
+// nolint:gocyclo // not complex just long
+func (p *selectParser) parseQuery() (exp.SelectClauses, error) {
+	c := exp.NewSelectClauses()
+	if p.acceptKeyword("WITH") {
+		recursive := p.acceptKeyword("RECURSIVE")
+		for {
+			cte, err := p.parseCommonTable(recursive)
+			if err != nil {
+				return nil, err
+			}
+			c = c.CommonTablesAppend(cte)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	c, err := p.parseSelectCore(c)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var ct exp.CompoundType
+		switch {
+		case p.acceptKeyword("UNION", "ALL"):
+			ct = exp.UnionAllCompoundType
+		case p.acceptKeyword("UNION"):
+			ct = exp.UnionCompoundType
+		case p.acceptKeyword("INTERSECT", "ALL"):
+			ct = exp.IntersectAllCompoundType
+		case p.acceptKeyword("INTERSECT"):
+			ct = exp.IntersectCompoundType
+		default:
+			ct = -1
+		}
+		if ct < 0 {
+			break
+		}
+		var rhs exp.SelectClauses
+		if p.acceptSymbol("(") {
+			if rhs, err = p.parseQuery(); err != nil {
+				return nil, err
+			}
+			if err = p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		} else if rhs, err = p.parseSelectCore(exp.NewSelectClauses()); err != nil {
+			return nil, err
+		}
+		c = c.CompoundsAppend(exp.NewCompoundExpression(ct, p.base.copy(rhs)))
+	}
+	if p.acceptKeyword("ORDER", "BY") {
+		order, err := p.parseOrder()
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetOrder(order...)
+	}
+	return p.parseLimit(c)
+}
+
+// parses name [(col, ...)] AS [[NOT] MATERIALIZED] (query)
+func (p *selectParser) parseCommonTable(recursive bool) (exp.CommonTableExpression, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	var cols []string
+	if p.acceptSymbol("(") {
+		for {
+			col, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, col)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	materialization := exp.DefaultMaterializationCTE
+	if p.acceptKeyword("MATERIALIZED") {
+		materialization = exp.MaterializedCTE
+	} else if p.acceptKeyword("NOT", "MATERIALIZED") {
+		materialization = exp.NotMaterializedCTE
+	}
+	sub, err := p.parseSubQuery()
+	if err != nil {
+		return nil, err
+	}
+	cte := exp.NewNamedCommonTableExpression(exp.NewIdentifierExpression("", "", name), sub)
+	if recursive {
+		cte = cte.Recursive()
+	}
+	if len(cols) > 0 {
+		cte = cte.Columns(cols...)
+	}
+	switch materialization {
+	case exp.MaterializedCTE:
+		cte = cte.Materialized()
+	case exp.NotMaterializedCTE:
+		cte = cte.NotMaterialized()
+	}
+	return cte, nil
+}
+
+// parses (query)
+func (p *selectParser) parseSubQuery() (*SelectDataset, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	c, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return p.base.copy(c), nil
+}
+
+// parses SELECT ... [FROM ...] [WHERE ...] [GROUP BY ...] [HAVING ...]
+// nolint:gocyclo // not complex just long
+func (p *selectParser) parseSelectCore(c exp.SelectClauses) (exp.SelectClauses, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("DISTINCT") {
+		on := exp.NewColumnListExpression()
+		if p.acceptKeyword("ON") {
+			if err := p.expectSymbol("("); err != nil {
+				return nil, err
+			}
+			cols, err := p.parseExpressions()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			for i, col := range cols {
+				cols[i] = toExpression(col)
+			}
+			on = exp.NewColumnListExpression(cols...)
+		}
+		c = c.SetDistinct(on)
+	} else {
+		p.acceptKeyword("ALL")
+	}
+	// a lone * is the default select of the clauses
+	star := isSymbol(p.peek(), "*") && !isSymbol(p.peekAt(1), ",")
+	cols, err := p.parseSelectList()
+	if err != nil {
+		return nil, err
+	}
+	if !star {
+		c = c.SetSelect(exp.NewColumnListExpression(cols...))
+	}
+	if p.acceptKeyword("FROM") {
+		var from []any
+		for {
+			table, err := p.parseTable()
+			if err != nil {
+				return nil, err
+			}
+			from = append(from, table)
+			for {
+				j, err := p.parseJoin()
+				if err != nil {
+					return nil, err
+				}
+				if j == nil {
+					break
+				}
+				c = c.JoinsAppend(j)
+			}
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		c = c.SetFrom(exp.NewColumnListExpression(from...))
+	}
+	if p.acceptKeyword("WHERE") {
+		where, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		c = c.WhereAppend(where)
+	}
+	if p.acceptKeyword("GROUP", "BY") {
+		groupBy, err := p.parseExpressions()
+		if err != nil {
+			return nil, err
+		}
+		for i, g := range groupBy {
+			groupBy[i] = toExpression(positionalReference(g))
+		}
+		c = c.SetGroupBy(exp.NewColumnListExpression(groupBy...))
+	}
+	if p.acceptKeyword("HAVING") {
+		having, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		c = c.HavingAppend(having)
+	}
+	return c, nil
+}
+
+func (p *selectParser) parseSelectList() ([]any, error) {
+	var cols []any
+	for {
+		if p.acceptSymbol("*") {
+			cols = append(cols, exp.Star())
+		} else {
+			col, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			alias, err := p.alias()
+			if err != nil {
+				return nil, err
+			}
+			if alias != "" {
+				col = exp.NewAliasExpression(toExpression(col), alias)
+			}
+			cols = append(cols, toExpression(col))
+		}
+		if !p.acceptSymbol(",") {
+			return cols, nil
+		}
+	}
+}
+
+// parses a table, [schema.]table or (query), with an optional alias
+func (p *selectParser) parseTable() (exp.Expression, error) {
+	if isSymbol(p.peek(), "(") {
+		if !isKeyword(p.peekAt(1), "SELECT") && !isKeyword(p.peekAt(1), "WITH") {
+			return nil, p.unexpected(p.peekAt(1), "sub query")
+		}
+		sub, err := p.parseSubQuery()
+		if err != nil {
+			return nil, err
+		}
+		alias, err := p.alias()
+		if err != nil {
+			return nil, err
+		}
+		if alias != "" {
+			return sub.As(alias), nil
+		}
+		return sub, nil
+	}
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	table := exp.NewIdentifierExpression("", name, nil)
+	if p.acceptSymbol(".") {
+		if name, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		table = table.Schema(table.GetTable()).Table(name)
+	}
+	if isSymbol(p.peek(), "(") {
+		return nil, p.errorAt(p.peek().pos, "unsupported table function")
+	}
+	alias, err := p.alias()
+	if err != nil {
+		return nil, err
+	}
+	if alias != "" {
+		return table.As(alias), nil
+	}
+	return table, nil
+}
+
+// parses a join, returns nil if the next token does not start a join
+// nolint:gocyclo // not complex just long
+func (p *selectParser) parseJoin() (exp.JoinExpression, error) {
+	var jt exp.JoinType
+	switch {
+	case p.acceptKeyword("JOIN"), p.acceptKeyword("INNER", "JOIN"):
+		jt = exp.InnerJoinType
+	case p.acceptKeyword("LEFT", "OUTER", "JOIN"):
+		jt = exp.LeftOuterJoinType
+	case p.acceptKeyword("LEFT", "JOIN"):
+		jt = exp.LeftJoinType
+	case p.acceptKeyword("RIGHT", "OUTER", "JOIN"):
+		jt = exp.RightOuterJoinType
+	case p.acceptKeyword("RIGHT", "JOIN"):
+		jt = exp.RightJoinType
+	case p.acceptKeyword("FULL", "OUTER", "JOIN"):
+		jt = exp.FullOuterJoinType
+	case p.acceptKeyword("FULL", "JOIN"):
+		jt = exp.FullJoinType
+	case p.acceptKeyword("CROSS", "JOIN"):
+		jt = exp.CrossJoinType
+	case p.acceptKeyword("NATURAL", "JOIN"):
+		jt = exp.NaturalJoinType
+	case p.acceptKeyword("NATURAL", "LEFT", "JOIN"):
+		jt = exp.NaturalLeftJoinType
+	case p.acceptKeyword("NATURAL", "RIGHT", "JOIN"):
+		jt = exp.NaturalRightJoinType
+	case p.acceptKeyword("NATURAL", "FULL", "JOIN"):
+		jt = exp.NaturalFullJoinType
+	default:
+		return nil, nil
+	}
+	table, err := p.parseTable()
+	if err != nil {
+		return nil, err
+	}
+	if !exp.ConditionedJoinTypes[jt] {
+		return exp.NewUnConditionedJoinExpression(jt, table), nil
+	}
+	if p.acceptKeyword("USING") {
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		var cols []any
+		for {
+			col, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, exp.NewIdentifierExpression("", "", col))
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return exp.NewConditionedJoinExpression(jt, table, exp.NewJoinUsingCondition(cols...)), nil
+	}
+	if err := p.expectKeyword("ON"); err != nil {
+		return nil, p.unexpected(p.peek(), "ON or USING")
+	}
+	on, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewConditionedJoinExpression(jt, table, exp.NewJoinOnCondition(on)), nil
+}
+
+func (p *selectParser) parseOrder() ([]exp.OrderedExpression, error) {
+	var order []exp.OrderedExpression
+	for {
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		direction := exp.AscDir
+		if p.acceptKeyword("DESC") {
+			direction = exp.DescSortDir
+		} else {
+			p.acceptKeyword("ASC")
+		}
+		nulls := exp.NoNullsSortType
+		switch {
+		case p.acceptKeyword("NULLS", "FIRST"):
+			nulls = exp.NullsFirstSortType
+		case p.acceptKeyword("NULLS", "LAST"):
+			nulls = exp.NullsLastSortType
+		}
+		order = append(order, exp.NewOrderedExpression(toExpression(positionalReference(e)), direction, nulls))
+		if !p.acceptSymbol(",") {
+			return order, nil
+		}
+	}
+}
+
+// parses [LIMIT count | LIMIT offset, count | LIMIT ALL] [OFFSET offset [ROWS]]
+func (p *selectParser) parseLimit(c exp.SelectClauses) (exp.SelectClauses, error) {
+	if p.acceptKeyword("LIMIT") {
+		if !p.acceptKeyword("ALL") {
+			t := p.peek()
+			limit, err := p.parseLimitValue()
+			if err != nil {
+				return nil, err
+			}
+			if p.acceptSymbol(",") {
+				offset, err := p.offsetValue(t, limit)
+				if err != nil {
+					return nil, err
+				}
+				c = c.SetOffset(offset)
+				if limit, err = p.parseLimitValue(); err != nil {
+					return nil, err
+				}
+			}
+			c = c.SetLimit(limit)
+		}
+	}
+	if p.acceptKeyword("OFFSET") {
+		t := p.peek()
+		val, err := p.parseLimitValue()
+		if err != nil {
+			return nil, err
+		}
+		offset, err := p.offsetValue(t, val)
+		if err != nil {
+			return nil, err
+		}
+		c = c.SetOffset(offset)
+		if !p.acceptKeyword("ROWS") {
+			p.acceptKeyword("ROW")
+		}
+	}
+	return c, nil
+}
+
+func (p *selectParser) parseLimitValue() (any, error) {
+	t := p.peek()
+	if t.kind != numberToken && t.kind != placeholderToken {
+		return nil, p.unexpected(t, "number")
+	}
+	return p.parsePrimary()
+}
+
+func (p *selectParser) offsetValue(t token, val any) (uint, error) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() >= 0 {
+			return uint(rv.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint()), nil
+	}
+	return 0, p.errorAt(t.pos, "offset must be a non-negative integer")
+}
+
+func (p *selectParser) parseExpressions() ([]any, error) {
+	var es []any
+	for {
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+		if !p.acceptSymbol(",") {
+			return es, nil
+		}
+	}
+}
+
+func (p *selectParser) parseCondition() (exp.Expression, error) {
+	e, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return toExpression(e), nil
+}
+
+// parses an expression, returns an exp.Expression or a value e.g. 1 or 'a'
+func (p *selectParser) parseExpression() (any, error) {
+	return p.parseLogical("OR", exp.OrType, p.parseAnd)
+}
+
+func (p *selectParser) parseAnd() (any, error) {
+	return p.parseLogical("AND", exp.AndType, p.parseNot)
+}
+
+func (p *selectParser) parseLogical(keyword string, t exp.ExpressionListType, operand func() (any, error)) (any, error) {
+	e, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if !isKeyword(p.peek(), keyword) {
+		return e, nil
+	}
+	es := []exp.Expression{toExpression(e)}
+	for p.acceptKeyword(keyword) {
+		if e, err = operand(); err != nil {
+			return nil, err
+		}
+		es = append(es, toExpression(e))
+	}
+	return exp.NewExpressionList(t, es...), nil
+}
+
+func (p *selectParser) parseNot() (any, error) {
+	if p.acceptKeyword("NOT") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewLiteralExpression("NOT ?", toExpression(e)), nil
+	}
+	return p.parsePredicate()
+}
+
+// parses comparisons, IS, IN, BETWEEN and LIKE
+// nolint:gocyclo // not complex just long
+func (p *selectParser) parsePredicate() (any, error) {
+	lhs, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if op, ok := comparisonOperations[t.text]; ok && t.kind == symbolToken {
+		p.advance()
+		rhs, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewBooleanExpression(op, toExpression(lhs), rhs), nil
+	}
+	if p.acceptKeyword("IS") {
+		op := exp.IsOp
+		if p.acceptKeyword("NOT") {
+			op = exp.IsNotOp
+		}
+		var rhs any
+		switch {
+		case p.acceptKeyword("NULL"):
+		case p.acceptKeyword("TRUE"):
+			rhs = true
+		case p.acceptKeyword("FALSE"):
+			rhs = false
+		default:
+			return nil, p.unexpected(p.peek(), "NULL, TRUE or FALSE")
+		}
+		return exp.NewBooleanExpression(op, toExpression(lhs), rhs), nil
+	}
+	not := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("IN"):
+		rhs, err := p.parseInValues()
+		if err != nil {
+			return nil, err
+		}
+		op := exp.InOp
+		if not {
+			op = exp.NotInOp
+		}
+		return exp.NewBooleanExpression(op, toExpression(lhs), rhs), nil
+	case p.acceptKeyword("BETWEEN"):
+		start, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		end, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		op := exp.BetweenOp
+		if not {
+			op = exp.NotBetweenOp
+		}
+		return exp.NewRangeExpression(op, toExpression(lhs), exp.NewRangeVal(start, end)), nil
+	case isKeyword(p.peek(), "LIKE"), isKeyword(p.peek(), "ILIKE"):
+		op := exp.LikeOp
+		if p.acceptKeyword("ILIKE") {
+			op = exp.ILikeOp
+		} else {
+			p.acceptKeyword("LIKE")
+		}
+		if not && op == exp.LikeOp {
+			op = exp.NotLikeOp
+		} else if not {
+			op = exp.NotILikeOp
+		}
+		rhs, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewBooleanExpression(op, toExpression(lhs), rhs), nil
+	case not:
+		return nil, p.unexpected(p.peek(), "IN, BETWEEN, LIKE or ILIKE")
+	}
+	return lhs, nil
+}
+
+// parses the (sub query) or (value, ...) of IN
+func (p *selectParser) parseInValues() (any, error) {
+	if isSymbol(p.peek(), "(") && (isKeyword(p.peekAt(1), "SELECT") || isKeyword(p.peekAt(1), "WITH")) {
+		return p.parseSubQuery()
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	vals, err := p.parseExpressions()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	// IN (?) with a slice argument expands to the values of the slice
+	if len(vals) == 1 {
+		if rv := reflect.ValueOf(vals[0]); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			return vals[0], nil
+		}
+	}
+	return vals, nil
+}
+
+func (p *selectParser) parseAdditive() (any, error) {
+	return p.parseArithmetic([]string{"+", "-", "||"}, p.parseMultiplicative)
+}
+
+func (p *selectParser) parseMultiplicative() (any, error) {
+	return p.parseArithmetic([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+// arithmetic and concatenation are kept as literals of their operands
+func (p *selectParser) parseArithmetic(operators []string, operand func() (any, error)) (any, error) {
+	lhs, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		matched := false
+		for _, op := range operators {
+			matched = matched || isSymbol(t, op)
+		}
+		if !matched {
+			return lhs, nil
+		}
+		p.advance()
+		rhs, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		lhs = exp.NewLiteralExpression("? "+t.text+" ?", lhs, rhs)
+	}
+}
+
+func (p *selectParser) parseUnary() (any, error) {
+	if p.acceptSymbol("+") {
+		return p.parseUnary()
+	}
+	if isSymbol(p.peek(), "-") {
+		p.advance()
+		if p.peek().kind == numberToken {
+			n, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			if i, ok := n.(int64); ok {
+				return -i, nil
+			}
+			return -n.(float64), nil
+		}
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return exp.NewLiteralExpression("-?", e), nil
+	}
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptSymbol("::") {
+		start := p.peek().pos
+		if _, err := p.identifier(); err != nil {
+			return nil, err
+		}
+		end, err := p.skipTypeModifiers()
+		if err != nil {
+			return nil, err
+		}
+		e = exp.NewCastExpression(toExpression(e), p.query[start:end])
+	}
+	return e, nil
+}
+
+// skips the (n[, m]) modifiers of a type, returns the end of the type
+func (p *selectParser) skipTypeModifiers() (int, error) {
+	end := p.tokens[p.next-1].pos + len(p.tokens[p.next-1].text)
+	if !p.acceptSymbol("(") {
+		return end, nil
+	}
+	for {
+		if t := p.advance(); t.kind != numberToken {
+			return 0, p.unexpected(t, "number")
+		}
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	closing := p.peek()
+	if err := p.expectSymbol(")"); err != nil {
+		return 0, err
+	}
+	return closing.pos + 1, nil
+}
+
+// Returns the value of a number token, an int64 or a float64 that are bound like any other value. Numbers neither
+// can represent exactly are rejected so their value does not change.
+func (p *selectParser) number(t token) (any, error) {
+	if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+		return i, nil
+	}
+	if !numberRegexp.MatchString(t.text) {
+		return nil, p.errorAt(t.pos, "invalid number %s", t.text)
+	}
+	f, err := strconv.ParseFloat(t.text, 64)
+	if err == nil {
+		// the shortest representation of f must have the same value as the text, e.g. 1.50 or 1e3
+		v, _ := new(big.Rat).SetString(t.text)
+		shortest, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+		if v.Cmp(shortest) == 0 {
+			return f, nil
+		}
+	}
+	return nil, p.errorAt(t.pos, "number %s can not be represented exactly", t.text)
+}
+
+// nolint:gocyclo // not complex just long
+func (p *selectParser) parsePrimary() (any, error) {
+	t := p.peek()
+	switch t.kind {
+	case numberToken:
+		p.advance()
+		return p.number(t)
+	case stringToken:
+		p.advance()
+		return t.text, nil
+	case placeholderToken:
+		p.advance()
+		return p.argument(t)
+	case symbolToken:
+		if t.text != "(" {
+			return nil, p.unexpected(t, "expression")
+		}
+		if isKeyword(p.peekAt(1), "SELECT") || isKeyword(p.peekAt(1), "WITH") {
+			return p.parseSubQuery()
+		}
+		p.advance()
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if isSymbol(p.peek(), ",") {
+			return nil, p.errorAt(p.peek().pos, "unsupported row value")
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case quotedIdentToken:
+		return p.parseIdentifier()
+	case wordToken:
+		switch strings.ToUpper(t.text) {
+		case "NULL":
+			p.advance()
+			return nil, nil
+		case "TRUE":
+			p.advance()
+			return true, nil
+		case "FALSE":
+			p.advance()
+			return false, nil
+		case "CASE":
+			return p.parseCase()
+		case "CAST":
+			return p.parseCast()
+		case "EXISTS":
+			p.advance()
+			sub, err := p.parseSubQuery()
+			if err != nil {
+				return nil, err
+			}
+			return exp.NewLiteralExpression("EXISTS ?", sub), nil
+		}
+		if reservedWords[strings.ToUpper(t.text)] {
+			return nil, p.unexpected(t, "expression")
+		}
+		if isSymbol(p.peekAt(1), "(") {
+			return p.parseFunction()
+		}
+		return p.parseIdentifier()
+	}
+	return nil, p.unexpected(t, "expression")
+}
+
+// returns the argument of a ? or $n placeholder
+func (p *selectParser) argument(t token) (any, error) {
+	i := p.argIndex
+	if t.text == "?" {
+		p.argIndex++
+	} else {
+		n, err := strconv.Atoi(t.text[1:])
+		if err != nil || n < 1 {
+			return nil, p.errorAt(t.pos, "invalid placeholder %s", t.text)
+		}
+		i = n - 1
+	}
+	if i >= len(p.args) {
+		return nil, p.errorAt(t.pos, "missing argument for placeholder %s", t.text)
+	}
+	if i >= p.bound {
+		p.bound = i + 1
+	}
+	return p.args[i], nil
+}
+
+// parses col, table.col, schema.table.col, table.* or schema.table.*
+func (p *selectParser) parseIdentifier() (any, error) {
+	parts := []string{p.identifierText(p.advance())}
+	for p.acceptSymbol(".") {
+		if p.acceptSymbol("*") {
+			return exp.NewIdentifierExpression("", "", nil).Table(strings.Join(parts, ".")).All(), nil
+		}
+		part, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	switch len(parts) {
+	case 1:
+		return exp.NewIdentifierExpression("", "", parts[0]), nil
+	case 2:
+		return exp.NewIdentifierExpression("", parts[0], parts[1]), nil
+	case 3:
+		return exp.NewIdentifierExpression(parts[0], parts[1], parts[2]), nil
+	}
+	return nil, p.errorAt(p.tokens[p.next-1].pos, "too many parts in identifier %s", strings.Join(parts, "."))
+}
+
+// parses name(args), name(*) and name(DISTINCT arg)
+func (p *selectParser) parseFunction() (any, error) {
+	name := p.advance().text
+	p.advance()
+	var args []any
+	switch {
+	case p.acceptSymbol("*"):
+		args = append(args, exp.Star())
+	case isSymbol(p.peek(), ")"):
+	case p.acceptKeyword("DISTINCT"):
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, exp.NewSQLFunctionExpression("DISTINCT", arg))
+	default:
+		var err error
+		if args, err = p.parseExpressions(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); isKeyword(t, "OVER") || isKeyword(t, "FILTER") {
+		return nil, p.unexpected(t, "")
+	}
+	return exp.NewSQLFunctionExpression(name, args...), nil
+}
+
+// parses CASE [value] WHEN condition THEN result ... [ELSE result] END
+func (p *selectParser) parseCase() (any, error) {
+	p.advance()
+	c := exp.NewCaseExpression()
+	if !isKeyword(p.peek(), "WHEN") {
+		val, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		c = c.Value(val)
+	}
+	if !isKeyword(p.peek(), "WHEN") {
+		return nil, p.unexpected(p.peek(), "WHEN")
+	}
+	for p.acceptKeyword("WHEN") {
+		condition, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		result, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		c = c.When(condition, result)
+	}
+	if p.acceptKeyword("ELSE") {
+		result, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		c = c.Else(result)
+	}
+	if err := p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parses CAST(expression AS type)
+func (p *selectParser) parseCast() (any, error) {
+	p.advance()
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	e, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	start := p.peek().pos
+	if _, err := p.identifier(); err != nil {
+		return nil, err
+	}
+	// multi word types e.g. DOUBLE PRECISION
+	for p.peekIdentifier() {
+		p.advance()
+	}
+	end, err := p.skipTypeModifiers()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return exp.NewCastExpression(toExpression(e), p.query[start:end]), nil
+}
+
+// wraps values that are not expressions e.g. the 1 of 1 = 1
+func toExpression(val any) exp.Expression {
+	if e, ok := val.(exp.Expression); ok {
+		return e
+	}
+	return exp.NewLiteralExpression("?", val)
+}
+
+// keeps the 1 of ORDER BY 1 and GROUP BY 1 as a column position rather than a value
+func positionalReference(val any) any {
+	if i, ok := val.(int64); ok {
+		return exp.NewLiteralExpression(strconv.FormatInt(i, 10))
+	}
+	return val
+}
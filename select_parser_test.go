@@ -0,0 +1,175 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/Tooooommy/builder/v9"
+	_ "github.com/Tooooommy/builder/v9/dialect/mysql"
+	_ "github.com/Tooooommy/builder/v9/dialect/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type selectParserSuite struct {
+	suite.Suite
+}
+
+func (sps *selectParserSuite) assertParsed(query string, expected string, args ...any) *builder.SelectDataset {
+	ds, err := builder.ParseSelect(query, args...)
+	sps.Require().NoError(err)
+	sql, _, err := ds.ToSQL()
+	sps.Require().NoError(err)
+	sps.Equal(expected, sql)
+	return ds
+}
+
+func (sps *selectParserSuite) assertParseError(query, expected string, args ...any) *builder.ParseError {
+	_, err := builder.ParseSelect(query, args...)
+	sps.Require().Error(err)
+	sps.EqualError(err, expected)
+	var pe *builder.ParseError
+	sps.Require().ErrorAs(err, &pe)
+	return pe
+}
+
+func (sps *selectParserSuite) TestParseSelect() {
+	sps.assertParsed(`SELECT * FROM items`, `SELECT * FROM "items"`)
+	sps.assertParsed(`select id, "name" AS n, i.price p FROM public.items AS i;`,
+		`SELECT "id", "name" AS "n", "i"."price" AS "p" FROM "public"."items" AS "i"`)
+	sps.assertParsed(`SELECT DISTINCT name FROM items`, `SELECT DISTINCT "name" FROM "items"`)
+	sps.assertParsed(`SELECT i.*, COUNT(*) AS c, COUNT(DISTINCT name) FROM items i`,
+		`SELECT "i".*, COUNT(*) AS "c", COUNT(DISTINCT("name")) FROM "items" AS "i"`)
+	sps.assertParsed(`SELECT 1`, `SELECT 1`)
+}
+
+func (sps *selectParserSuite) TestParseSelect_where() {
+	sps.assertParsed(
+		`SELECT * FROM items WHERE a = 1 AND b <> 'x' AND (c > 1.5 OR d IS NULL) AND e IS NOT TRUE`,
+		`SELECT * FROM "items" WHERE (("a" = 1) AND ("b" != 'x') AND (("c" > 1.5) OR ("d" IS NULL)) AND ("e" IS NOT TRUE))`,
+	)
+	// numbers are bound like any other value
+	ds := sps.assertParsed(
+		`SELECT * FROM items WHERE a = 9223372036854775807 AND b = 1.50 AND c = 1e3`,
+		`SELECT * FROM "items" WHERE (("a" = 9223372036854775807) AND ("b" = 1.5) AND ("c" = 1000))`,
+	)
+	_, args, err := ds.Prepared(true).ToSQL()
+	sps.NoError(err)
+	sps.Equal([]any{int64(9223372036854775807), 1.5, float64(1000)}, args)
+	sps.assertParsed(
+		`SELECT * FROM items WHERE id IN (1, 2, 3) AND id NOT IN (SELECT item_id FROM deleted)`,
+		`SELECT * FROM "items" WHERE (("id" IN (1, 2, 3)) AND ("id" NOT IN (SELECT "item_id" FROM "deleted")))`,
+	)
+	sps.assertParsed(
+		`SELECT * FROM items WHERE price BETWEEN 1 AND 10 AND name NOT LIKE 'B%' AND name ILIKE 'b%'`,
+		`SELECT * FROM "items" WHERE (("price" BETWEEN 1 AND 10) AND ("name" NOT LIKE 'B%') AND ("name" ILIKE 'b%'))`,
+	)
+	sps.assertParsed(
+		`SELECT * FROM items WHERE NOT EXISTS (SELECT 1 FROM orders WHERE orders.item_id = items.id) AND price * 2 > -5`,
+		`SELECT * FROM "items" WHERE (NOT EXISTS (SELECT 1 FROM "orders" WHERE ("orders"."item_id" = "items"."id")) `+
+			`AND ("price" * 2 > -5))`,
+	)
+	sps.assertParsed(
+		`SELECT CASE WHEN price > 10 THEN 'high' ELSE 'low' END AS level, CAST(price AS DECIMAL(10, 2)), id::text FROM items`,
+		`SELECT CASE  WHEN ("price" > 10) THEN 'high' ELSE 'low' END AS "level", CAST("price" AS DECIMAL(10, 2)), `+
+			`CAST("id" AS text) FROM "items"`,
+	)
+}
+
+func (sps *selectParserSuite) TestParseSelect_joins() {
+	sps.assertParsed(
+		`SELECT u.id FROM users u JOIN accounts a ON a.user_id = u.id LEFT OUTER JOIN profiles USING (user_id) CROSS JOIN tags`,
+		`SELECT "u"."id" FROM "users" AS "u" INNER JOIN "accounts" AS "a" ON ("a"."user_id" = "u"."id") `+
+			`LEFT OUTER JOIN "profiles" USING ("user_id") CROSS JOIN "tags"`,
+	)
+	sps.assertParsed(
+		`SELECT * FROM (SELECT id FROM users) AS u, accounts`,
+		`SELECT * FROM (SELECT "id" FROM "users") AS "u", "accounts"`,
+	)
+}
+
+func (sps *selectParserSuite) TestParseSelect_groupOrderLimit() {
+	sps.assertParsed(
+		`SELECT name, SUM(price) FROM items GROUP BY name HAVING SUM(price) > 100 ORDER BY 2 DESC NULLS LAST, name LIMIT 10 OFFSET 20`,
+		`SELECT "name", SUM("price") FROM "items" GROUP BY "name" HAVING (SUM("price") > 100) `+
+			`ORDER BY 2 DESC NULLS LAST, "name" ASC LIMIT 10 OFFSET 20`,
+	)
+	sps.assertParsed(`SELECT * FROM items LIMIT 20, 10`, `SELECT * FROM "items" LIMIT 10 OFFSET 20`)
+}
+
+func (sps *selectParserSuite) TestParseSelect_compoundsAndCTEs() {
+	sps.assertParsed(
+		`WITH RECURSIVE recent(id) AS (SELECT id FROM users WHERE created > '2024-01-01') `+
+			`SELECT id FROM recent UNION ALL SELECT id FROM admins`,
+		`WITH RECURSIVE "recent"("id") AS (SELECT "id" FROM "users" WHERE ("created" > '2024-01-01')) `+
+			`SELECT "id" FROM "recent" UNION ALL (SELECT "id" FROM "admins")`,
+	)
+	sps.assertParsed(
+		`WITH a AS MATERIALIZED (SELECT 1), b AS NOT MATERIALIZED (SELECT 2) SELECT * FROM a, b`,
+		`WITH "a" AS MATERIALIZED (SELECT 1), "b" AS NOT MATERIALIZED (SELECT 2) SELECT * FROM "a", "b"`,
+	)
+}
+
+func (sps *selectParserSuite) TestParseSelect_placeholders() {
+	ds := sps.assertParsed(
+		`SELECT * FROM items WHERE status = ? AND id IN (?) LIMIT ?`,
+		`SELECT * FROM "items" WHERE (("status" = 'active') AND ("id" IN (1, 2))) LIMIT 10`,
+		"active", []int{1, 2}, 10,
+	)
+	sql, args, err := ds.Prepared(true).ToSQL()
+	sps.NoError(err)
+	sps.Equal(`SELECT * FROM "items" WHERE (("status" = ?) AND ("id" IN (?, ?))) LIMIT ?`, sql)
+	sps.Equal([]any{"active", int64(1), int64(2), int64(10)}, args)
+
+	sps.assertParsed(`SELECT * FROM items WHERE a = $2 AND b = $1`, `SELECT * FROM "items" WHERE (("a" = 'x') AND ("b" = 1))`, 1, "x")
+}
+
+func (sps *selectParserSuite) TestParseSelect_modify() {
+	ds, err := builder.Dialect("mysql").ParseSelect("SELECT `id`, `name` FROM `users` WHERE `active` ORDER BY `id` LIMIT 50")
+	sps.NoError(err)
+	sql, _, err := ds.Where(builder.C("tenant_id").Eq(10)).Limit(100).ToSQL()
+	sps.NoError(err)
+	sps.Equal("SELECT `id`, `name` FROM `users` WHERE (`active` AND (`tenant_id` = 10)) ORDER BY `id` ASC LIMIT 100", sql)
+}
+
+func (sps *selectParserSuite) TestParseSelect_identifierCase() {
+	// unquoted identifiers keep their case unless the dialect folds them, quoted identifiers always keep their case
+	sps.assertParsed(`SELECT UserId, "Name" FROM Users`, `SELECT "UserId", "Name" FROM "Users"`)
+
+	ds, err := builder.Dialect("postgres").ParseSelect(`SELECT u.UserId, "Name" AS N FROM Public.Users AS U`)
+	sps.NoError(err)
+	sql, _, err := ds.ToSQL()
+	sps.NoError(err)
+	sps.Equal(`SELECT "u"."userid", "Name" AS "n" FROM "public"."users" AS "u"`, sql)
+}
+
+func (sps *selectParserSuite) TestParseSelect_errors() {
+	pe := sps.assertParseError("SELECT id\nFROM items\nWHERE a = 1 EXCEPT SELECT 1", "builder: unsupported EXCEPT at line 3, column 13")
+	sps.Equal(33, pe.Offset)
+
+	sps.assertParseError(`SELECT ROW_NUMBER() OVER (ORDER BY id) FROM items`, "builder: unsupported OVER at line 1, column 21")
+	sps.assertParseError(`SELECT * FROM items FOR UPDATE`, "builder: unsupported FOR at line 1, column 21")
+	sps.assertParseError(`SELECT * FROM items WHERE`, "builder: unexpected end of query, expected expression at line 1, column 26")
+	sps.assertParseError(`SELECT * FROM items WHERE (a, b) = (1, 2)`, "builder: unsupported row value at line 1, column 29")
+	sps.assertParseError(`SELECT * FROM items WHERE a = 'b`, "builder: unterminated string at line 1, column 31")
+	sps.assertParseError(`SELECT * FROM items WHERE a = 1.2.3`, "builder: invalid number 1.2.3 at line 1, column 31")
+	sps.assertParseError(
+		`SELECT * FROM t WHERE "a""b" = 1`, "builder: unsupported quote character in identifier at line 1, column 23",
+	)
+	sps.assertParseError(
+		"SELECT * FROM t WHERE `a\"b` = 1", "builder: unsupported quote character in identifier at line 1, column 23",
+	)
+	sps.assertParseError(
+		`SELECT * FROM t WHERE a = 9223372036854775808`,
+		"builder: number 9223372036854775808 can not be represented exactly at line 1, column 27",
+	)
+	sps.assertParseError(
+		`SELECT * FROM t WHERE a = 0.1000000000000000000001`,
+		"builder: number 0.1000000000000000000001 can not be represented exactly at line 1, column 27",
+	)
+	sps.assertParseError(`UPDATE items SET a = 1`, "builder: unexpected UPDATE, expected SELECT at line 1, column 1")
+	sps.assertParseError(`SELECT * FROM items WHERE a = ?`, "builder: missing argument for placeholder ? at line 1, column 31")
+	sps.assertParseError(`SELECT * FROM items WHERE a = ?`, "builder: 2 arguments provided for 1 placeholders at line 1, column 32", 1, 2)
+}
+
+func TestSelectParserSuite(t *testing.T) {
+	suite.Run(t, new(selectParserSuite))
+}
@@ -1,8 +1,10 @@
 package builder
 
 import (
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -20,6 +22,12 @@ type (
 		ToInsertSQL(b sb.SQLBuilder, clauses exp.InsertClauses)
 		ToDeleteSQL(b sb.SQLBuilder, clauses exp.DeleteClauses)
 		ToTruncateSQL(b sb.SQLBuilder, clauses exp.TruncateClauses)
+		ToSavepointSQL(b sb.SQLBuilder, name string)
+		ToReleaseSavepointSQL(b sb.SQLBuilder, name string)
+		ToRollbackToSavepointSQL(b sb.SQLBuilder, name string)
+		ToSetTransactionSQL(b sb.SQLBuilder, opts exp.TransactionOptions)
+		ToStatementTimeoutSQL(b sb.SQLBuilder, timeout time.Duration)
+		ToResetStatementTimeoutSQL(b sb.SQLBuilder)
 	}
 	// The default adapter. This class should be used when building a new adapter. When creating a new adapter you can
 	// either override methods, or more typically update default values.
@@ -32,6 +40,7 @@ type (
 		insertGen      sqlgen.InsertSQLGenerator
 		deleteGen      sqlgen.DeleteSQLGenerator
 		truncateGen    sqlgen.TruncateSQLGenerator
+		txGen          sqlgen.TransactionSQLGenerator
 	}
 )
 
@@ -58,6 +67,18 @@ func DeregisterDialect(name string) {
 	delete(dialects, strings.ToLower(name))
 }
 
+// Returns the sorted names of the registered dialects
+func Dialects() []string {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	names := make([]string, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func GetDialect(name string) SQLDialect {
 	name = strings.ToLower(name)
 	if d, ok := dialects[name]; ok {
@@ -75,6 +96,7 @@ func newDialect(dialect string, do *SQLDialectOptions) SQLDialect {
 		insertGen:      sqlgen.NewInsertSQLGenerator(dialect, do),
 		deleteGen:      sqlgen.NewDeleteSQLGenerator(dialect, do),
 		truncateGen:    sqlgen.NewTruncateSQLGenerator(dialect, do),
+		txGen:          sqlgen.NewTransactionSQLGenerator(dialect, do),
 	}
 }
 
@@ -82,6 +104,10 @@ func (d *sqlDialect) Dialect() string {
 	return d.dialect
 }
 
+func (d *sqlDialect) options() *SQLDialectOptions {
+	return d.dialectOptions
+}
+
 func (d *sqlDialect) ToSelectSQL(b sb.SQLBuilder, clauses exp.SelectClauses) {
 	d.selectGen.Generate(b, clauses)
 }
@@ -101,3 +127,27 @@ func (d *sqlDialect) ToDeleteSQL(b sb.SQLBuilder, clauses exp.DeleteClauses) {
 func (d *sqlDialect) ToTruncateSQL(b sb.SQLBuilder, clauses exp.TruncateClauses) {
 	d.truncateGen.Generate(b, clauses)
 }
+
+func (d *sqlDialect) ToSavepointSQL(b sb.SQLBuilder, name string) {
+	d.txGen.SavepointSQL(b, name)
+}
+
+func (d *sqlDialect) ToReleaseSavepointSQL(b sb.SQLBuilder, name string) {
+	d.txGen.ReleaseSavepointSQL(b, name)
+}
+
+func (d *sqlDialect) ToRollbackToSavepointSQL(b sb.SQLBuilder, name string) {
+	d.txGen.RollbackToSavepointSQL(b, name)
+}
+
+func (d *sqlDialect) ToSetTransactionSQL(b sb.SQLBuilder, opts exp.TransactionOptions) {
+	d.txGen.SetTransactionSQL(b, opts)
+}
+
+func (d *sqlDialect) ToStatementTimeoutSQL(b sb.SQLBuilder, timeout time.Duration) {
+	d.txGen.StatementTimeoutSQL(b, timeout)
+}
+
+func (d *sqlDialect) ToResetStatementTimeoutSQL(b sb.SQLBuilder) {
+	d.txGen.ResetStatementTimeoutSQL(b)
+}
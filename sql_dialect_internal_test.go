@@ -2,6 +2,7 @@ package builder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -86,6 +87,49 @@ func (dts *dialectTestSuite) TestToTruncateSQL() {
 	tm.AssertExpectations(dts.T())
 }
 
+func (dts *dialectTestSuite) TestToSavepointSQL() {
+	opts := DefaultDialectOptions()
+	tm := new(mocks.TransactionSQLGenerator)
+	d := sqlDialect{dialect: "test", dialectOptions: opts, txGen: tm}
+
+	b := sb.NewSQLBuilder(false)
+	tm.On("SavepointSQL", b, "sp_1").Return(nil).Once()
+	tm.On("ReleaseSavepointSQL", b, "sp_1").Return(nil).Once()
+	tm.On("RollbackToSavepointSQL", b, "sp_1").Return(nil).Once()
+
+	d.ToSavepointSQL(b, "sp_1")
+	d.ToReleaseSavepointSQL(b, "sp_1")
+	d.ToRollbackToSavepointSQL(b, "sp_1")
+	tm.AssertExpectations(dts.T())
+}
+
+func (dts *dialectTestSuite) TestToSetTransactionSQL() {
+	opts := DefaultDialectOptions()
+	tm := new(mocks.TransactionSQLGenerator)
+	d := sqlDialect{dialect: "test", dialectOptions: opts, txGen: tm}
+
+	b := sb.NewSQLBuilder(false)
+	txOpts := exp.TransactionOptions{ReadOnly: true}
+	tm.On("SetTransactionSQL", b, txOpts).Return(nil).Once()
+
+	d.ToSetTransactionSQL(b, txOpts)
+	tm.AssertExpectations(dts.T())
+}
+
+func (dts *dialectTestSuite) TestToStatementTimeoutSQL() {
+	opts := DefaultDialectOptions()
+	tm := new(mocks.TransactionSQLGenerator)
+	d := sqlDialect{dialect: "test", dialectOptions: opts, txGen: tm}
+
+	b := sb.NewSQLBuilder(false)
+	tm.On("StatementTimeoutSQL", b, time.Second).Return(nil).Once()
+	tm.On("ResetStatementTimeoutSQL", b).Return(nil).Once()
+
+	d.ToStatementTimeoutSQL(b, time.Second)
+	d.ToResetStatementTimeoutSQL(b)
+	tm.AssertExpectations(dts.T())
+}
+
 func TestSQLDialect(t *testing.T) {
 	suite.Run(t, new(dialectTestSuite))
 }
@@ -1,6 +1,9 @@
 package sqlgen
 
 import (
+	"bytes"
+	"strings"
+
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -16,10 +19,26 @@ func ErrRecursiveCTENotSupported(dialect string) error {
 	return errors.New("dialect does not support CTE WITH RECURSIVE clause [dialect=%s]", dialect)
 }
 
+func ErrCTEMaterializedNotSupported(dialect string) error {
+	return errors.New("dialect does not support CTE MATERIALIZED hints [dialect=%s]", dialect)
+}
+
+func ErrCTESearchCycleNotSupported(dialect string) error {
+	return errors.New("dialect does not support CTE SEARCH and CYCLE clauses [dialect=%s]", dialect)
+}
+
 func ErrReturnNotSupported(dialect string) error {
 	return errors.New("dialect does not support RETURNING clause [dialect=%s]", dialect)
 }
 
+func ErrOptimizerHintsNotSupported(dialect string) error {
+	return errors.New("dialect does not support optimizer hints [dialect=%s]", dialect)
+}
+
+func ErrInvalidOptimizerHint(hint string) error {
+	return errors.New("optimizer hints can not contain comment delimiters got %q", hint)
+}
+
 func ErrNotSupportedFragment(sqlType string, f SQLFragmentType) error {
 	return errors.New("unsupported %s SQL fragment %s", sqlType, f)
 }
@@ -37,6 +56,7 @@ type (
 		OrderWithOffsetFetchSQL(b sb.SQLBuilder, order exp.ColumnListExpression, offset uint, limit any)
 		LimitSQL(b sb.SQLBuilder, limit any)
 		UpdateExpressionSQL(b sb.SQLBuilder, updates ...exp.UpdateExpression)
+		OptimizerHintsSQL(b sb.SQLBuilder, hints []string)
 	}
 	commonSQLGenerator struct {
 		dialect        string
@@ -86,6 +106,40 @@ func (csg *commonSQLGenerator) SourcesSQL(b sb.SQLBuilder, from exp.ColumnListEx
 	csg.esg.Generate(b, from)
 }
 
+// Generates an optimizer hints comment for an SQL statement (e.g. /*+ BKA(t1) NO_ICP(t2) */)
+func (csg *commonSQLGenerator) OptimizerHintsSQL(b sb.SQLBuilder, hints []string) {
+	if len(hints) == 0 {
+		return
+	}
+	if !csg.dialectOptions.SupportsOptimizerHints {
+		b.SetError(ErrOptimizerHintsNotSupported(csg.dialect))
+		return
+	}
+	for _, hint := range hints {
+		if !csg.isValidOptimizerHint(hint) {
+			b.SetError(ErrInvalidOptimizerHint(hint))
+			return
+		}
+	}
+	b.Write(csg.dialectOptions.OptimizerHintsStartFragment)
+	for i, hint := range hints {
+		if i > 0 {
+			b.WriteRunes(csg.dialectOptions.SpaceRune)
+		}
+		b.WriteStrings(hint)
+	}
+	b.Write(csg.dialectOptions.OptimizerHintsEndFragment)
+}
+
+// Returns false if the hint could end the hints comment (e.g. "*/") or open a nested one
+func (csg *commonSQLGenerator) isValidOptimizerHint(hint string) bool {
+	if strings.Contains(hint, "*/") || strings.Contains(hint, "/*") {
+		return false
+	}
+	end := bytes.TrimSpace(csg.dialectOptions.OptimizerHintsEndFragment)
+	return len(end) == 0 || !strings.Contains(hint, string(end))
+}
+
 // Generates the WHERE clause for an SQL statement
 func (csg *commonSQLGenerator) WhereSQL(b sb.SQLBuilder, where exp.ExpressionList) {
 	if where != nil && !where.IsEmpty() {
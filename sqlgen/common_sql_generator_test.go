@@ -311,6 +311,53 @@ func (csgs *commonSQLGeneratorSuite) TestLimitSQL() {
 	)
 }
 
+func (csgs *commonSQLGeneratorSuite) TestOptimizerHintsSQL() {
+	hintsGen := func(csgs sqlgen.CommonSQLGenerator, hints ...string) func(sb.SQLBuilder) {
+		return func(sb sb.SQLBuilder) {
+			csgs.OptimizerHintsSQL(sb, hints)
+		}
+	}
+
+	csg := sqlgen.NewCommonSQLGenerator("test", sqlgen.DefaultDialectOptions())
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	csgSupported := sqlgen.NewCommonSQLGenerator("test", opts)
+
+	opts = sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	opts.OptimizerHintsStartFragment = []byte("/*+")
+	opts.OptimizerHintsEndFragment = []byte("*/")
+	csgCustom := sqlgen.NewCommonSQLGenerator("test", opts)
+
+	csgs.assertCases(
+		commonSQLTestCase{gen: hintsGen(csg), sql: ``},
+		commonSQLTestCase{gen: hintsGen(csg, "NO_ICP(t)"), err: "builder: dialect does not support optimizer hints [dialect=test]"},
+
+		commonSQLTestCase{gen: hintsGen(csgSupported), sql: ``},
+		commonSQLTestCase{gen: hintsGen(csgSupported, "NO_ICP(t)"), sql: `/*+ NO_ICP(t) */`},
+		commonSQLTestCase{gen: hintsGen(csgSupported, "NO_ICP(t)", "BKA(t)"), sql: `/*+ NO_ICP(t) BKA(t) */`},
+		commonSQLTestCase{
+			gen: hintsGen(csgSupported, "NO_ICP(t)", "BKA(t)"), sql: `/*+ NO_ICP(t) BKA(t) */`, isPrepared: true, args: emptyArgs,
+		},
+
+		commonSQLTestCase{gen: hintsGen(csgCustom, "NO_ICP(t)", "BKA(t)"), sql: `/*+NO_ICP(t) BKA(t)*/`},
+
+		commonSQLTestCase{
+			gen: hintsGen(csgSupported, "BKA(t) */ DROP TABLE t; /*"),
+			err: `builder: optimizer hints can not contain comment delimiters got "BKA(t) */ DROP TABLE t; /*"`,
+		},
+		commonSQLTestCase{
+			gen: hintsGen(csgSupported, "NO_ICP(t)", "*/1=1"),
+			err: `builder: optimizer hints can not contain comment delimiters got "*/1=1"`,
+		},
+		commonSQLTestCase{
+			gen: hintsGen(csgSupported, "BKA(t /* nested"),
+			err: `builder: optimizer hints can not contain comment delimiters got "BKA(t /* nested"`,
+		},
+	)
+}
+
 func (csgs *commonSQLGeneratorSuite) TestUpdateExpressionSQL() {
 	updateGen := func(csgs sqlgen.CommonSQLGenerator, ues ...exp.UpdateExpression) func(sb.SQLBuilder) {
 		return func(sb sb.SQLBuilder) {
@@ -42,6 +42,7 @@ func (dsg *deleteSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.DeleteClaus
 		case DeleteBeginSQLFragment:
 			dsg.DeleteBeginSQL(
 				b, exp.NewColumnListExpression(clauses.From()), !(clauses.HasLimit() || clauses.HasOrder()),
+				clauses.OptimizerHints()...,
 			)
 		case FromSQLFragment:
 			dsg.FromSQL(b, exp.NewColumnListExpression(clauses.From()))
@@ -64,8 +65,14 @@ func (dsg *deleteSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.DeleteClaus
 }
 
 // Adds the correct fragment to being an DELETE statement
-func (dsg *deleteSQLGenerator) DeleteBeginSQL(b sb.SQLBuilder, from exp.ColumnListExpression, multiTable bool) {
+func (dsg *deleteSQLGenerator) DeleteBeginSQL(
+	b sb.SQLBuilder, from exp.ColumnListExpression, multiTable bool, optimizerHints ...string,
+) {
 	b.Write(dsg.DialectOptions().DeleteClause)
+	if len(optimizerHints) > 0 {
+		b.WriteRunes(dsg.DialectOptions().SpaceRune)
+		dsg.OptimizerHintsSQL(b, optimizerHints)
+	}
 	if multiTable && dsg.DialectOptions().SupportsDeleteTableHint {
 		dsg.SourcesSQL(b, from)
 	}
@@ -205,6 +205,33 @@ func (dsgs *deleteSQLGeneratorSuite) TestGenerate_withLimit() {
 	)
 }
 
+func (dsgs *deleteSQLGeneratorSuite) TestGenerate_withOptimizerHints() {
+	dc := exp.NewDeleteClauses().
+		SetFrom(exp.NewIdentifierExpression("", "test", "")).
+		OptimizerHintsAppend("NO_ICP(test)", "BKA(test)")
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	dsgs.assertCases(
+		sqlgen.NewDeleteSQLGenerator("test", opts),
+		deleteTestCase{clause: dc, sql: `DELETE /*+ NO_ICP(test) BKA(test) */ FROM "test"`},
+		deleteTestCase{clause: dc, sql: `DELETE /*+ NO_ICP(test) BKA(test) */ FROM "test"`, isPrepared: true},
+	)
+
+	opts.SupportsDeleteTableHint = true
+	dsgs.assertCases(
+		sqlgen.NewDeleteSQLGenerator("test", opts),
+		deleteTestCase{clause: dc, sql: `DELETE /*+ NO_ICP(test) BKA(test) */ "test" FROM "test"`},
+	)
+
+	expectedErr := "builder: dialect does not support optimizer hints [dialect=test]"
+	dsgs.assertCases(
+		sqlgen.NewDeleteSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		deleteTestCase{clause: dc, err: expectedErr},
+		deleteTestCase{clause: dc, err: expectedErr, isPrepared: true},
+	)
+}
+
 func (dsgs *deleteSQLGeneratorSuite) TestGenerate_withReturning() {
 	opts := sqlgen.DefaultDialectOptions()
 	opts.SupportsReturn = true
@@ -3,7 +3,9 @@ package sqlgen
 import (
 	"database/sql/driver"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -34,11 +36,25 @@ var (
 	TrueLiteral     = exp.NewLiteralExpression("TRUE")
 	FalseLiteral    = exp.NewLiteralExpression("FALSE")
 
+	// the placeholder of values and the marker of lists of values in fingerprints, see sb.NewFingerprintSQLBuilder
+	fingerprintPlaceholder = []byte("?")
+	fingerprintListMarker  = []byte("(...)")
+
 	ErrEmptyIdentifier = errors.New(
 		`a empty identifier was encountered, please specify a "schema", "table" or "column"`,
 	)
 	ErrUnexpectedNamedWindow = errors.New(`unexpected named window function`)
 	ErrEmptyCaseWhens        = errors.New(`when conditions not found for case statement`)
+	ErrEmptyValuesList       = errors.New(`a VALUES list must contain at least one row`)
+
+	// table hints are keywords with an optional list of identifiers or numbers (e.g. NOLOCK or INDEX(idx_a))
+	tableHintRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([A-Za-z0-9_, ]*\))?$`)
+
+	// functions taking or returning arrays, they are rejected by dialects that do not support arrays
+	arrayFunctions = map[string]bool{
+		"array_length": true,
+		"unnest":       true,
+	}
 )
 
 func errUnsupportedExpressionType(e exp.Expression) error {
@@ -65,6 +81,34 @@ func errLateralNotSupported(dialect string) error {
 	return errors.New("dialect does not support lateral expressions [dialect=%s]", dialect)
 }
 
+func errTableFunctionsNotSupported(dialect string) error {
+	return errors.New("dialect does not support table functions [dialect=%s]", dialect)
+}
+
+func errWithOrdinalityNotSupported(dialect string) error {
+	return errors.New("dialect does not support WITH ORDINALITY [dialect=%s]", dialect)
+}
+
+func errColumnDefinitionsNotSupported(dialect string) error {
+	return errors.New("dialect does not support table function column definitions [dialect=%s]", dialect)
+}
+
+func errArraysNotSupported(dialect string) error {
+	return errors.New("dialect does not support arrays [dialect=%s]", dialect)
+}
+
+func errIndexHintsNotSupported(dialect string) error {
+	return errors.New("dialect does not support index hints [dialect=%s]", dialect)
+}
+
+func errTableHintsNotSupported(dialect string) error {
+	return errors.New("dialect does not support table hints [dialect=%s]", dialect)
+}
+
+func errInvalidTableHint(hint string) error {
+	return errors.New("invalid table hint %q", hint)
+}
+
 func NewExpressionSQLGenerator(dialect string, do *SQLDialectOptions) ExpressionSQLGenerator {
 	return &expressionSQLGenerator{dialect: dialect, dialectOptions: do}
 }
@@ -170,6 +214,8 @@ func (esg *expressionSQLGenerator) expressionSQL(b sb.SQLBuilder, expression exp
 		esg.identifierExpressionSQL(b, e)
 	case exp.LateralExpression:
 		esg.lateralExpressionSQL(b, e)
+	case exp.TableHintExpression:
+		esg.tableHintExpressionSQL(b, e)
 	case exp.AliasedExpression:
 		esg.aliasedExpressionSQL(b, e)
 	case exp.BooleanExpression:
@@ -190,6 +236,12 @@ func (esg *expressionSQLGenerator) expressionSQL(b sb.SQLBuilder, expression exp
 		esg.windowExpressionSQL(b, e)
 	case exp.CastExpression:
 		esg.castExpressionSQL(b, e)
+	case exp.ValuesListExpression:
+		esg.valuesListExpressionSQL(b, e)
+	case exp.TableFunctionExpression:
+		esg.tableFunctionExpressionSQL(b, e)
+	case exp.ArrayExpression:
+		esg.arrayExpressionSQL(b, e)
 	case exp.AppendableExpression:
 		esg.appendableExpressionSQL(b, e)
 	case exp.CommonTableExpression:
@@ -209,6 +261,10 @@ func (esg *expressionSQLGenerator) expressionSQL(b sb.SQLBuilder, expression exp
 
 // Generates a placeholder (e.g. ?, $1)
 func (esg *expressionSQLGenerator) placeHolderSQL(b sb.SQLBuilder, i any) {
+	if b.IsFingerprint() {
+		b.Write(fingerprintPlaceholder).WriteArg(i)
+		return
+	}
 	b.Write(esg.dialectOptions.PlaceHolderFragment)
 	if esg.dialectOptions.IncludePlaceholderNum {
 		b.WriteStrings(strconv.FormatInt(int64(b.CurrentArgPosition()), 10))
@@ -227,6 +283,168 @@ func (esg *expressionSQLGenerator) appendableExpressionSQL(b sb.SQLBuilder, a ex
 	}
 }
 
+// Generates the sql for a table function, generate_series(1, 3) WITH ORDINALITY AS "s"("n", "i")
+func (esg *expressionSQLGenerator) tableFunctionExpressionSQL(b sb.SQLBuilder, tf exp.TableFunctionExpression) {
+	if !esg.dialectOptions.SupportsTableFunctions {
+		b.SetError(errTableFunctionsNotSupported(esg.dialect))
+		return
+	}
+	if tf.IsWithOrdinality() && !esg.dialectOptions.SupportsWithOrdinality {
+		b.SetError(errWithOrdinalityNotSupported(esg.dialect))
+		return
+	}
+	if tf.HasColumnDefinitions() && !esg.dialectOptions.SupportsTableFunctionColumnDefinitions {
+		b.SetError(errColumnDefinitionsNotSupported(esg.dialect))
+		return
+	}
+	if !esg.supportsFunction(tf.Name()) {
+		b.SetError(errArraysNotSupported(esg.dialect))
+		return
+	}
+	b.WriteStrings(tf.Name())
+	esg.Generate(b, tf.Args())
+	if tf.IsWithOrdinality() {
+		b.Write(esg.dialectOptions.WithOrdinalityFragment)
+	}
+	cols := tf.Columns()
+	if tf.GetAs() == nil && len(cols) == 0 {
+		return
+	}
+	b.Write(esg.dialectOptions.AsFragment)
+	if tf.GetAs() != nil {
+		esg.Generate(b, tf.GetAs())
+	}
+	if len(cols) == 0 {
+		return
+	}
+	b.WriteRunes(esg.dialectOptions.LeftParenRune)
+	for i, col := range cols {
+		if i > 0 {
+			b.WriteRunes(esg.dialectOptions.CommaRune, esg.dialectOptions.SpaceRune)
+		}
+		esg.Generate(b, exp.NewIdentifierExpression("", "", col.Name()))
+		if col.Type() != "" {
+			b.WriteRunes(esg.dialectOptions.SpaceRune).WriteStrings(col.Type())
+		}
+	}
+	b.WriteRunes(esg.dialectOptions.RightParenRune)
+}
+
+// Generates the sql for an array, ARRAY[1, 2, 3]. Prepared arrays are passed as a single argument unless they contain
+// expressions
+func (esg *expressionSQLGenerator) arrayExpressionSQL(b sb.SQLBuilder, a exp.ArrayExpression) {
+	if !esg.dialectOptions.SupportsArrays {
+		b.SetError(errArraysNotSupported(esg.dialect))
+		return
+	}
+	elems := a.Elements()
+	if elems == nil {
+		b.SetError(errors.New("array values must be a slice or array got %T", a.Values()))
+		return
+	}
+	if (b.IsPrepared() || b.IsFingerprint()) && !a.HasExpressions() {
+		esg.placeHolderSQL(b, a)
+		return
+	}
+	if len(elems) == 0 {
+		b.Write(esg.dialectOptions.EmptyArrayFragment)
+		return
+	}
+	b.Write(esg.dialectOptions.ArrayFragment).WriteRunes(esg.dialectOptions.LeftBracketRune)
+	for i, elem := range elems {
+		if i > 0 {
+			b.WriteRunes(esg.dialectOptions.CommaRune, esg.dialectOptions.SpaceRune)
+		}
+		if _, ok := elem.([]byte); !ok && elem != nil && util.IsSlice(reflect.Indirect(reflect.ValueOf(elem)).Kind()) {
+			// nested slices are multidimensional arrays
+			elem = exp.NewArrayExpression(elem)
+		}
+		esg.Generate(b, elem)
+	}
+	b.WriteRunes(esg.dialectOptions.RightBracketRune)
+}
+
+// returns false for array functions when the dialect does not support arrays
+func (esg *expressionSQLGenerator) supportsFunction(name string) bool {
+	return esg.dialectOptions.SupportsArrays || !arrayFunctions[strings.ToLower(name)]
+}
+
+// Generates the sql for a VALUES list used as a table source, (VALUES (1, 'a'), (2, 'b')) AS "v"("id", "name")
+func (esg *expressionSQLGenerator) valuesListExpressionSQL(b sb.SQLBuilder, vl exp.ValuesListExpression) {
+	b.WriteRunes(esg.dialectOptions.LeftParenRune)
+	valuesListSQL(b, esg, esg.dialectOptions, vl)
+	b.WriteRunes(esg.dialectOptions.RightParenRune)
+	if vl.GetAs() == nil {
+		return
+	}
+	b.Write(esg.dialectOptions.AsFragment)
+	esg.Generate(b, vl.GetAs())
+	if vl.HasCols() && !esg.dialectOptions.UseUnionAllForValuesList {
+		// a UNION ALL names the columns with aliases in its first SELECT
+		b.WriteRunes(esg.dialectOptions.LeftParenRune)
+		esg.Generate(b, vl.Cols())
+		b.WriteRunes(esg.dialectOptions.RightParenRune)
+	}
+}
+
+// Generates the rows of a VALUES list, VALUES (1, 'a'), (2, 'b'), or SELECT 1 AS "id", 'a' AS "name" UNION ALL
+// SELECT 2, 'b' if the dialect uses UseUnionAllForValuesList
+func valuesListSQL(b sb.SQLBuilder, esg ExpressionSQLGenerator, opts *SQLDialectOptions, vl exp.ValuesListExpression) {
+	rows := vl.Rows()
+	if len(rows) == 0 {
+		b.SetError(ErrEmptyValuesList)
+		return
+	}
+	rowLen := len(rows[0])
+	if vl.HasCols() && len(vl.Cols().Columns()) != rowLen {
+		b.SetError(errMisMatchedRowLength(len(vl.Cols().Columns()), rowLen))
+		return
+	}
+	if !opts.UseUnionAllForValuesList {
+		b.Write(opts.ValuesListFragment)
+	}
+	for i, row := range rows {
+		if len(row) != rowLen {
+			b.SetError(errMisMatchedRowLength(rowLen, len(row)))
+			return
+		}
+		if b.IsFingerprint() {
+			// any number of rows has the same fingerprint
+			if i == 0 {
+				if opts.UseUnionAllForValuesList {
+					b.Write(opts.SelectClause).WriteRunes(opts.SpaceRune)
+				} else {
+					b.Write(opts.ValuesListRowFragment)
+				}
+				b.Write(fingerprintListMarker)
+			}
+			continue
+		}
+		if opts.UseUnionAllForValuesList {
+			if i > 0 {
+				b.Write(opts.UnionAllFragment)
+			}
+			b.Write(opts.SelectClause).WriteRunes(opts.SpaceRune)
+			for j, val := range row {
+				if j > 0 {
+					b.WriteRunes(opts.CommaRune, opts.SpaceRune)
+				}
+				esg.Generate(b, val)
+				if i == 0 && vl.HasCols() {
+					b.Write(opts.AsFragment)
+					esg.Generate(b, vl.Cols().Columns()[j])
+				}
+			}
+			continue
+		}
+		if i > 0 {
+			b.WriteRunes(opts.CommaRune, opts.SpaceRune)
+		}
+		b.Write(opts.ValuesListRowFragment)
+		esg.Generate(b, row)
+	}
+}
+
 // Quotes an identifier (e.g. "col", "table"."col"
 func (esg *expressionSQLGenerator) identifierExpressionSQL(b sb.SQLBuilder, ident exp.IdentifierExpression) {
 	if ident.IsEmpty() {
@@ -277,6 +495,48 @@ func (esg *expressionSQLGenerator) lateralExpressionSQL(b sb.SQLBuilder, le exp.
 	esg.Generate(b, le.Table())
 }
 
+func (esg *expressionSQLGenerator) tableHintExpressionSQL(b sb.SQLBuilder, the exp.TableHintExpression) {
+	esg.Generate(b, the.Table())
+	for _, hint := range the.Hints() {
+		if b.Error() != nil {
+			return
+		}
+		if hint.Type() == exp.WithTableHint {
+			if !esg.dialectOptions.SupportsTableHints {
+				b.SetError(errTableHintsNotSupported(esg.dialect))
+				return
+			}
+			for _, v := range hint.Values() {
+				if !tableHintRegexp.MatchString(v) {
+					b.SetError(errInvalidTableHint(v))
+					return
+				}
+			}
+			b.Write(esg.dialectOptions.TableHintsFragment).WriteRunes(esg.dialectOptions.LeftParenRune)
+			for i, v := range hint.Values() {
+				if i > 0 {
+					b.WriteRunes(esg.dialectOptions.CommaRune, esg.dialectOptions.SpaceRune)
+				}
+				b.WriteStrings(v)
+			}
+			b.WriteRunes(esg.dialectOptions.RightParenRune)
+			continue
+		}
+		if !esg.dialectOptions.SupportsIndexHints {
+			b.SetError(errIndexHintsNotSupported(esg.dialect))
+			return
+		}
+		b.Write(esg.dialectOptions.IndexHintTypeLookup[hint.Type()]).WriteRunes(esg.dialectOptions.LeftParenRune)
+		for i, v := range hint.Values() {
+			if i > 0 {
+				b.WriteRunes(esg.dialectOptions.CommaRune, esg.dialectOptions.SpaceRune)
+			}
+			esg.Generate(b, exp.NewIdentifierExpression("", "", v))
+		}
+		b.WriteRunes(esg.dialectOptions.RightParenRune)
+	}
+}
+
 // Generates SQL NULL value
 func (esg *expressionSQLGenerator) literalNil(b sb.SQLBuilder) {
 	if b.IsPrepared() {
@@ -305,7 +565,7 @@ func (esg *expressionSQLGenerator) literalTime(b sb.SQLBuilder, t time.Time) {
 		esg.placeHolderSQL(b, t)
 		return
 	}
-	esg.Generate(b, t.In(timeLocation).Format(esg.dialectOptions.TimeFormat))
+	esg.Generate(b, t.In(timeLocationOf(b)).Format(esg.dialectOptions.TimeFormat))
 }
 
 // Generates SQL for a Float Value
@@ -390,6 +650,10 @@ func (esg *expressionSQLGenerator) booleanExpressionSQL(b sb.SQLBuilder, operato
 	esg.Generate(b, operator.LHS())
 	b.WriteRunes(esg.dialectOptions.SpaceRune)
 	operatorOp := operator.Op()
+	if isArrayOperation(operatorOp) && !esg.dialectOptions.SupportsArrays {
+		b.SetError(errArraysNotSupported(esg.dialect))
+		return
+	}
 	if val, ok := esg.dialectOptions.BooleanOperatorLookup[operatorOp]; ok {
 		b.Write(val)
 	} else {
@@ -414,11 +678,21 @@ func (esg *expressionSQLGenerator) booleanExpressionSQL(b sb.SQLBuilder, operato
 			rhs = exp.NewLiteralExpression(string(esg.dialectOptions.Null))
 		}
 	}
-	b.WriteRunes(esg.dialectOptions.SpaceRune)
+	if operatorOp != exp.EqAnyOp {
+		b.WriteRunes(esg.dialectOptions.SpaceRune)
+	}
 
 	if (operatorOp == exp.IsOp || operatorOp == exp.IsNotOp) && rhs == nil && !esg.dialectOptions.BooleanDataTypeSupported {
 		// e.g. for SQL server dialect which does not support "IS @p1" for "IS NULL"
 		b.Write(esg.dialectOptions.Null)
+	} else if (operatorOp == exp.InOp || operatorOp == exp.NotInOp) && b.IsFingerprint() && isValueList(rhs) {
+		// IN lists of any length have the same fingerprint
+		b.Write(fingerprintListMarker)
+	} else if _, ok := rhs.(exp.AppendableExpression); operatorOp == exp.EqAnyOp && !ok {
+		// = ANY(ARRAY[1, 2]), sub selects are already wrapped in parens
+		b.WriteRunes(esg.dialectOptions.LeftParenRune)
+		esg.Generate(b, rhs)
+		b.WriteRunes(esg.dialectOptions.RightParenRune)
 	} else {
 		esg.Generate(b, rhs)
 	}
@@ -426,6 +700,31 @@ func (esg *expressionSQLGenerator) booleanExpressionSQL(b sb.SQLBuilder, operato
 	b.WriteRunes(esg.dialectOptions.RightParenRune)
 }
 
+func isArrayOperation(op exp.BooleanOperation) bool {
+	switch op {
+	case exp.ArrayContainsOp, exp.ArrayContainedByOp, exp.ArrayOverlapOp, exp.EqAnyOp:
+		return true
+	}
+	return false
+}
+
+// returns true if val is a list of values, lists containing expressions (e.g. a sub select) are not lists of values
+func isValueList(val any) bool {
+	if _, ok := val.([]byte); ok || val == nil {
+		return false
+	}
+	v := reflect.Indirect(reflect.ValueOf(val))
+	if !util.IsSlice(v.Kind()) {
+		return false
+	}
+	for i := 0; i < v.Len(); i++ {
+		if _, ok := v.Index(i).Interface().(exp.Expression); ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Generates SQL for a BitwiseExpresion (e.g. I("a").BitwiseOr(2) - > "a" | 2)
 func (esg *expressionSQLGenerator) bitwiseExpressionSQL(b sb.SQLBuilder, operator exp.BitwiseExpression) {
 	b.WriteRunes(esg.dialectOptions.LeftParenRune)
@@ -559,6 +858,10 @@ func (esg *expressionSQLGenerator) literalExpressionSQL(b sb.SQLBuilder, literal
 //
 //	COUNT(I("a")) -> COUNT("a")
 func (esg *expressionSQLGenerator) sqlFunctionExpressionSQL(b sb.SQLBuilder, sqlFunc exp.SQLFunctionExpression) {
+	if !esg.supportsFunction(sqlFunc.Name()) {
+		b.SetError(errArraysNotSupported(esg.dialect))
+		return
+	}
 	b.WriteStrings(sqlFunc.Name())
 	esg.Generate(b, sqlFunc.Args())
 }
@@ -664,9 +967,60 @@ func (esg *expressionSQLGenerator) commonTablesSliceSQL(b sb.SQLBuilder, ctes []
 
 // Generates SQL for a CommonTableExpression
 func (esg *expressionSQLGenerator) commonTableExpressionSQL(b sb.SQLBuilder, cte exp.CommonTableExpression) {
-	esg.Generate(b, cte.Name())
+	if cte.Identifier() != nil {
+		esg.Generate(b, cte.Identifier())
+	} else {
+		esg.Generate(b, cte.Name())
+	}
+	if cte.HasCols() {
+		b.WriteRunes(esg.dialectOptions.LeftParenRune)
+		esg.Generate(b, cte.Cols())
+		b.WriteRunes(esg.dialectOptions.RightParenRune)
+	}
 	b.Write(esg.dialectOptions.AsFragment)
+	if cte.Materialization() != exp.DefaultMaterializationCTE {
+		if !esg.dialectOptions.SupportsCTEMaterialized {
+			b.SetError(ErrCTEMaterializedNotSupported(esg.dialect))
+			return
+		}
+		if cte.Materialization() == exp.MaterializedCTE {
+			b.Write(esg.dialectOptions.MaterializedFragment)
+		} else {
+			b.Write(esg.dialectOptions.NotMaterializedFragment)
+		}
+	}
 	esg.Generate(b, cte.SubQuery())
+	if cte.SearchClause() == nil && cte.CycleClause() == nil {
+		return
+	}
+	if !esg.dialectOptions.SupportsCTESearchCycle {
+		b.SetError(ErrCTESearchCycleNotSupported(esg.dialect))
+		return
+	}
+	if search := cte.SearchClause(); search != nil {
+		if search.Type() == exp.BreadthFirstSearch {
+			b.Write(esg.dialectOptions.SearchBreadthFirstFragment)
+		} else {
+			b.Write(esg.dialectOptions.SearchDepthFirstFragment)
+		}
+		esg.Generate(b, search.By())
+		b.Write(esg.dialectOptions.SetFragment)
+		esg.Generate(b, search.Set())
+	}
+	if cycle := cte.CycleClause(); cycle != nil {
+		b.Write(esg.dialectOptions.CycleFragment)
+		esg.Generate(b, cycle.Cols())
+		b.Write(esg.dialectOptions.SetFragment)
+		esg.Generate(b, cycle.Set())
+		if cycle.HasMark() {
+			b.Write(esg.dialectOptions.CycleToFragment)
+			esg.Generate(b, cycle.MarkValue())
+			b.Write(esg.dialectOptions.CycleDefaultFragment)
+			esg.Generate(b, cycle.MarkDefault())
+		}
+		b.Write(esg.dialectOptions.UsingFragment)
+		esg.Generate(b, cycle.Using())
+	}
 }
 
 // Generates SQL for a CompoundExpression
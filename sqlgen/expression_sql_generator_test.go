@@ -332,6 +332,33 @@ func (esgs *expressionSQLGeneratorSuite) TestGenerate_Slice() {
 	)
 }
 
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_Fingerprint() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.PlaceHolderFragment = []byte("$")
+	opts.IncludePlaceholderNum = true
+	esg := sqlgen.NewExpressionSQLGenerator("test", opts)
+	cases := []struct {
+		val any
+		sql string
+	}{
+		{val: exp.NewIdentifierExpression("", "", "a").Eq("b"), sql: `("a" = ?)`},
+		{val: exp.NewIdentifierExpression("", "", "a").In(1, 2, 3), sql: `("a" IN (...))`},
+		{val: exp.NewIdentifierExpression("", "", "a").In([]string{"a"}), sql: `("a" IN (...))`},
+		{val: exp.NewIdentifierExpression("", "", "a").IsNull(), sql: `("a" IS NULL)`},
+		{val: exp.NewIdentifierExpression("", "", "a").Between(exp.NewRangeVal(1, 10)), sql: `("a" BETWEEN ? AND ?)`},
+		{val: exp.NewLiteralExpression("? + ?", 1, exp.NewIdentifierExpression("", "", "b")), sql: `? + "b"`},
+		{val: exp.NewSQLFunctionExpression("COALESCE", exp.NewIdentifierExpression("", "", "a"), 0), sql: `COALESCE("a", ?)`},
+		{val: exp.NewValuesListExpression([][]any{{1, "a"}, {2, "b"}}).As("v"), sql: `(VALUES (...)) AS "v"`},
+	}
+	for _, c := range cases {
+		b := sb.NewFingerprintSQLBuilder()
+		esg.Generate(b, c.val)
+		sql, _, err := b.ToSQL()
+		esgs.NoError(err)
+		esgs.Equal(c.sql, sql)
+	}
+}
+
 type unknownExpression struct{}
 
 func (ue unknownExpression) Expression() exp.Expression {
@@ -1027,6 +1054,67 @@ func (esgs *expressionSQLGeneratorSuite) TestGenerate_CommonTableExpression() {
 	)
 }
 
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_NamedCommonTableExpression() {
+	ae := newTestAppendableExpression(`SELECT * FROM "b"`, emptyArgs, nil, nil)
+	cte := exp.NewNamedCommonTableExpression(exp.NewIdentifierExpression("", "a", ""), ae)
+	search := exp.NewCTESearch(
+		exp.DepthFirstSearch, exp.NewColumnListExpression("id"), exp.NewIdentifierExpression("", "", "ord"),
+	)
+	cycle := exp.NewCTECycle(
+		exp.NewColumnListExpression("id"),
+		exp.NewIdentifierExpression("", "", "is_cycle"),
+		exp.NewIdentifierExpression("", "", "path"),
+	)
+
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		expressionTestCase{val: cte, sql: `"a" AS (SELECT * FROM "b")`},
+		expressionTestCase{val: cte.Columns("x", "y"), sql: `"a"("x", "y") AS (SELECT * FROM "b")`},
+		expressionTestCase{val: cte.Materialized(), sql: `"a" AS MATERIALIZED (SELECT * FROM "b")`},
+		expressionTestCase{val: cte.NotMaterialized(), sql: `"a" AS NOT MATERIALIZED (SELECT * FROM "b")`},
+		expressionTestCase{val: cte.Search(search), sql: `"a" AS (SELECT * FROM "b") SEARCH DEPTH FIRST BY "id" SET "ord"`},
+		expressionTestCase{
+			val: cte.Search(exp.NewCTESearch(exp.BreadthFirstSearch, search.By(), search.Set())),
+			sql: `"a" AS (SELECT * FROM "b") SEARCH BREADTH FIRST BY "id" SET "ord"`,
+		},
+		expressionTestCase{val: cte.Cycle(cycle), sql: `"a" AS (SELECT * FROM "b") CYCLE "id" SET "is_cycle" USING "path"`},
+		expressionTestCase{
+			val: cte.Cycle(cycle.Mark("Y", "N")),
+			sql: `"a" AS (SELECT * FROM "b") CYCLE "id" SET "is_cycle" TO 'Y' DEFAULT 'N' USING "path"`,
+		},
+		expressionTestCase{
+			val:        cte.Cycle(cycle.Mark("Y", "N")),
+			sql:        `"a" AS (SELECT * FROM "b") CYCLE "id" SET "is_cycle" TO ? DEFAULT ? USING "path"`,
+			isPrepared: true,
+			args:       []any{"Y", "N"},
+		},
+		expressionTestCase{
+			val: []exp.CommonTableExpression{cte.Columns("id").Search(search)},
+			sql: `WITH RECURSIVE "a"("id") AS (SELECT * FROM "b") SEARCH DEPTH FIRST BY "id" SET "ord" `,
+		},
+	)
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsCTEMaterialized = false
+	opts.SupportsCTESearchCycle = false
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", opts),
+		expressionTestCase{val: cte.Columns("x"), sql: `"a"("x") AS (SELECT * FROM "b")`},
+		expressionTestCase{
+			val: cte.Materialized(),
+			err: "builder: dialect does not support CTE MATERIALIZED hints [dialect=test]",
+		},
+		expressionTestCase{
+			val: cte.Search(search),
+			err: "builder: dialect does not support CTE SEARCH and CYCLE clauses [dialect=test]",
+		},
+		expressionTestCase{
+			val: cte.Cycle(cycle),
+			err: "builder: dialect does not support CTE SEARCH and CYCLE clauses [dialect=test]",
+		},
+	)
+}
+
 func (esgs *expressionSQLGeneratorSuite) TestGenerate_CompoundExpression() {
 	ae := newTestAppendableExpression(`SELECT * FROM "b"`, emptyArgs, nil, nil)
 
@@ -1187,6 +1275,198 @@ func (esgs *expressionSQLGeneratorSuite) TestGenerate_LateralExpression() {
 	)
 }
 
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_TableFunctionExpression() {
+	tf := exp.NewTableFunctionExpression("generate_series", 1, 3)
+	ordinality := exp.NewTableFunctionExpression("unnest", exp.NewIdentifierExpression("", "", "tags")).
+		WithOrdinality().As("t", "tag", "n")
+	colDefs := exp.NewTableFunctionExpression("jsonb_to_recordset", exp.NewIdentifierExpression("", "", "doc")).
+		As("r").Column("a", "int").Column("b", "text")
+
+	do := sqlgen.DefaultDialectOptions()
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: tf, sql: `generate_series(1, 3)`},
+		expressionTestCase{val: tf.As("s"), sql: `generate_series(1, 3) AS "s"`},
+		expressionTestCase{val: tf.As("s", "n"), sql: `generate_series(1, 3) AS "s"("n")`},
+		expressionTestCase{val: tf.As("s", "n"), sql: `generate_series(?, ?) AS "s"("n")`, isPrepared: true, args: []any{int64(1), int64(3)}},
+		expressionTestCase{val: ordinality, sql: `unnest("tags") WITH ORDINALITY AS "t"("tag", "n")`},
+		expressionTestCase{val: colDefs, sql: `jsonb_to_recordset("doc") AS "r"("a" int, "b" text)`},
+		expressionTestCase{val: tf.Column("a", "int"), sql: `generate_series(1, 3) AS ("a" int)`},
+		expressionTestCase{
+			val: exp.NewLateralExpression(ordinality),
+			sql: `LATERAL unnest("tags") WITH ORDINALITY AS "t"("tag", "n")`,
+		},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.SupportsWithOrdinality = false
+	do.SupportsTableFunctionColumnDefinitions = false
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: tf.As("s", "n"), sql: `generate_series(1, 3) AS "s"("n")`},
+		expressionTestCase{val: ordinality, err: "builder: dialect does not support WITH ORDINALITY [dialect=test]"},
+		expressionTestCase{
+			val: colDefs,
+			err: "builder: dialect does not support table function column definitions [dialect=test]",
+		},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.SupportsTableFunctions = false
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: tf, err: "builder: dialect does not support table functions [dialect=test]"},
+	)
+}
+
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_ValuesListExpression() {
+	vl := exp.NewValuesListExpression([][]any{{1, "a"}, {2, "b"}})
+	aliased := vl.As("v", "id", "name")
+
+	do := sqlgen.DefaultDialectOptions()
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: vl, sql: `(VALUES (1, 'a'), (2, 'b'))`},
+		expressionTestCase{val: vl.As("v"), sql: `(VALUES (1, 'a'), (2, 'b')) AS "v"`},
+		expressionTestCase{val: aliased, sql: `(VALUES (1, 'a'), (2, 'b')) AS "v"("id", "name")`},
+		expressionTestCase{
+			val:        aliased,
+			sql:        `(VALUES (?, ?), (?, ?)) AS "v"("id", "name")`,
+			isPrepared: true,
+			args:       []any{int64(1), "a", int64(2), "b"},
+		},
+		expressionTestCase{
+			val: exp.NewValuesListExpression([][]any{{1}, {2, "b"}}),
+			err: "builder: rows with different value length expected 1 got 2",
+		},
+		expressionTestCase{
+			val: exp.NewValuesListExpression([][]any{{1}}).As("v", "id", "name"),
+			err: "builder: rows with different value length expected 2 got 1",
+		},
+		expressionTestCase{val: exp.NewValuesListExpression(nil), err: "builder: a VALUES list must contain at least one row"},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.ValuesListRowFragment = []byte("ROW")
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: aliased, sql: `(VALUES ROW(1, 'a'), ROW(2, 'b')) AS "v"("id", "name")`},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.UseUnionAllForValuesList = true
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: vl, sql: `(SELECT 1, 'a' UNION ALL SELECT 2, 'b')`},
+		expressionTestCase{val: aliased, sql: `(SELECT 1 AS "id", 'a' AS "name" UNION ALL SELECT 2, 'b') AS "v"`},
+		expressionTestCase{
+			val:        aliased,
+			sql:        `(SELECT ? AS "id", ? AS "name" UNION ALL SELECT ?, ?) AS "v"`,
+			isPrepared: true,
+			args:       []any{int64(1), "a", int64(2), "b"},
+		},
+	)
+}
+
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_ArrayExpression() {
+	ints := exp.NewArrayExpression([]int{1, 2})
+	tags := exp.NewIdentifierExpression("", "", "tags")
+	id := exp.NewIdentifierExpression("", "", "id")
+
+	do := sqlgen.DefaultDialectOptions()
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: ints, sql: `ARRAY[1, 2]`},
+		expressionTestCase{val: ints, sql: `?`, isPrepared: true, args: []any{ints}},
+		expressionTestCase{val: exp.NewArrayExpression([]string{}), sql: `'{}'`},
+		expressionTestCase{val: exp.NewArrayExpression([][]int{{1, 2}, {3, 4}}), sql: `ARRAY[ARRAY[1, 2], ARRAY[3, 4]]`},
+		expressionTestCase{
+			val:        exp.NewArrayExpression([]any{id, 2}),
+			sql:        `ARRAY["id", ?]`,
+			isPrepared: true,
+			args:       []any{int64(2)},
+		},
+		expressionTestCase{val: exp.NewArrayExpression(1), err: "builder: array values must be a slice or array got int"},
+		expressionTestCase{val: tags.Contains([]string{"a", "b"}), sql: `("tags" @> ARRAY['a', 'b'])`},
+		expressionTestCase{val: tags.ContainedBy([]string{"a"}), sql: `("tags" <@ ARRAY['a'])`},
+		expressionTestCase{val: tags.Overlaps(tags), sql: `("tags" && "tags")`},
+		expressionTestCase{val: id.EqAny([]int{1, 2}), sql: `("id" = ANY(ARRAY[1, 2]))`},
+		expressionTestCase{val: id.EqAny([]int{1, 2}), sql: `("id" = ANY(?))`, isPrepared: true, args: []any{ints}},
+		expressionTestCase{val: id.EqAny(tags), sql: `("id" = ANY("tags"))`},
+		expressionTestCase{
+			val: exp.NewSQLFunctionExpression("array_length", tags, 1),
+			sql: `array_length("tags", 1)`,
+		},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.SupportsArrays = false
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: ints, err: "builder: dialect does not support arrays [dialect=test]"},
+		expressionTestCase{val: tags.Contains(tags), err: "builder: dialect does not support arrays [dialect=test]"},
+		expressionTestCase{val: id.EqAny(tags), err: "builder: dialect does not support arrays [dialect=test]"},
+		expressionTestCase{
+			val: exp.NewSQLFunctionExpression("ARRAY_LENGTH", tags, 1),
+			err: "builder: dialect does not support arrays [dialect=test]",
+		},
+		expressionTestCase{
+			val: exp.NewTableFunctionExpression("unnest", tags),
+			err: "builder: dialect does not support arrays [dialect=test]",
+		},
+	)
+}
+
+func (esgs *expressionSQLGeneratorSuite) TestGenerate_TableHintExpression() {
+	ident := exp.NewIdentifierExpression("", "test", "")
+	useIndex := ident.UseIndex("idx_a", "idx_b")
+	forceIgnore := ident.As("t").ForceIndex("idx_a").IgnoreIndex("idx_b")
+	withHints := ident.WithTableHints("NOLOCK", "INDEX(idx_a)")
+
+	do := sqlgen.DefaultDialectOptions()
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: useIndex, err: "builder: dialect does not support index hints [dialect=test]"},
+		expressionTestCase{val: withHints, err: "builder: dialect does not support table hints [dialect=test]"},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.SupportsIndexHints = true
+	do.SupportsTableHints = true
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: useIndex, sql: `"test" USE INDEX ("idx_a", "idx_b")`},
+		expressionTestCase{val: useIndex, sql: `"test" USE INDEX ("idx_a", "idx_b")`, isPrepared: true},
+		expressionTestCase{val: forceIgnore, sql: `"test" AS "t" FORCE INDEX ("idx_a") IGNORE INDEX ("idx_b")`},
+		expressionTestCase{val: withHints, sql: `"test" WITH (NOLOCK, INDEX(idx_a))`},
+		expressionTestCase{val: withHints, sql: `"test" WITH (NOLOCK, INDEX(idx_a))`, isPrepared: true},
+		expressionTestCase{
+			val: ident.WithTableHints("NOLOCK) DROP TABLE test --"),
+			err: `builder: invalid table hint "NOLOCK) DROP TABLE test --"`,
+		},
+		expressionTestCase{
+			val: ident.WithTableHints("INDEX(idx_a)) DROP TABLE test; --"),
+			err: `builder: invalid table hint "INDEX(idx_a)) DROP TABLE test; --"`,
+		},
+		expressionTestCase{val: ident.WithTableHints("NOLOCK", ""), err: `builder: invalid table hint ""`},
+	)
+
+	do = sqlgen.DefaultDialectOptions()
+	do.SupportsIndexHints = true
+	do.SupportsTableHints = true
+	do.IndexHintTypeLookup = map[exp.TableHintType][]byte{
+		exp.UseIndexHint:    []byte(" use index "),
+		exp.ForceIndexHint:  []byte(" force index "),
+		exp.IgnoreIndexHint: []byte(" ignore index "),
+	}
+	do.TableHintsFragment = []byte(" with ")
+	esgs.assertCases(
+		sqlgen.NewExpressionSQLGenerator("test", do),
+		expressionTestCase{val: forceIgnore, sql: `"test" AS "t" force index ("idx_a") ignore index ("idx_b")`},
+		expressionTestCase{val: withHints, sql: `"test" with (NOLOCK, INDEX(idx_a))`},
+	)
+}
+
 func (esgs *expressionSQLGeneratorSuite) TestGenerate_CaseExpression() {
 	ident := exp.NewIdentifierExpression("", "", "col")
 	valueCase := exp.NewCaseExpression().
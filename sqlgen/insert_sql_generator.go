@@ -83,7 +83,7 @@ func (isg *insertSQLGenerator) InsertBeginSQL(b sb.SQLBuilder, o exp.ConflictExp
 func (isg *insertSQLGenerator) InsertSQL(b sb.SQLBuilder, ic exp.InsertClauses) {
 	switch {
 	case ic.HasRows():
-		ie, err := exp.NewInsertExpression(ic.Rows()...)
+		ie, err := exp.NewInsertExpressionWithOptions(columnMapOptionsOf(b), ic.Rows()...)
 		if err != nil {
 			b.SetError(err)
 			return
@@ -126,6 +126,10 @@ func (isg *insertSQLGenerator) defaultValuesSQL(b sb.SQLBuilder) {
 
 func (isg *insertSQLGenerator) insertFromSQL(b sb.SQLBuilder, ae exp.AppendableExpression) {
 	b.WriteRunes(isg.DialectOptions().SpaceRune)
+	if vl, ok := ae.(exp.ValuesListExpression); ok {
+		valuesListSQL(b, isg.ExpressionSQLGenerator(), isg.DialectOptions(), vl)
+		return
+	}
 	ae.AppendSQL(b)
 }
 
@@ -146,6 +150,13 @@ func (isg *insertSQLGenerator) insertValuesSQL(b sb.SQLBuilder, values [][]any)
 			b.SetError(errMisMatchedRowLength(rowLen, len(row)))
 			return
 		}
+		if b.IsFingerprint() {
+			// any number of rows has the same fingerprint
+			if i == 0 {
+				b.Write(fingerprintListMarker)
+			}
+			continue
+		}
 		isg.ExpressionSQLGenerator().Generate(b, row)
 		if i < valueLen-1 {
 			b.WriteRunes(isg.DialectOptions().CommaRune, isg.DialectOptions().SpaceRune)
@@ -187,7 +198,7 @@ func (isg *insertSQLGenerator) onConflictDoUpdateSQL(b sb.SQLBuilder, o exp.Conf
 		b.SetError(ErrConflictUpdateValuesRequired)
 		return
 	}
-	ue, err := exp.NewUpdateExpressions(update)
+	ue, err := exp.NewUpdateExpressionsWithOptions(columnMapOptionsOf(b), update)
 	if err != nil {
 		b.SetError(err)
 		return
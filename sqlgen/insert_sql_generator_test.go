@@ -132,6 +132,26 @@ func (igs *insertSQLGeneratorSuite) TestGenerate_colsAndVals() {
 	)
 }
 
+func (igs *insertSQLGeneratorSuite) TestGenerate_fingerprint() {
+	ic := exp.NewInsertClauses().
+		SetInto(exp.NewIdentifierExpression("", "test", "")).
+		SetCols(exp.NewColumnListExpression("a", "b")).
+		SetVals([][]any{
+			{"a1", "b1"},
+			{"a2", "b2"},
+		})
+
+	b := sb.NewFingerprintSQLBuilder()
+	sqlgen.NewInsertSQLGenerator("test", sqlgen.DefaultDialectOptions()).Generate(b, ic)
+	sql, _, err := b.ToSQL()
+	igs.NoError(err)
+	igs.Equal(`INSERT INTO "test" ("a", "b") VALUES (...)`, sql)
+
+	b = sb.NewFingerprintSQLBuilder()
+	sqlgen.NewInsertSQLGenerator("test", sqlgen.DefaultDialectOptions()).Generate(b, ic.SetVals([][]any{{"a1"}, {"a2", "b2"}}))
+	igs.assertErrorSQL(b, `builder: rows with different value length expected 1 got 2`)
+}
+
 func (igs *insertSQLGeneratorSuite) TestGenerate_withNoInto() {
 	opts := sqlgen.DefaultDialectOptions()
 	opts.LeftParenRune = '{'
@@ -242,6 +262,31 @@ func (igs *insertSQLGeneratorSuite) TestGenerate_withFrom() {
 	)
 }
 
+func (igs *insertSQLGeneratorSuite) TestGenerate_withFromValuesList() {
+	ic := exp.NewInsertClauses().
+		SetInto(exp.NewIdentifierExpression("", "test", "")).
+		SetCols(exp.NewColumnListExpression("a", "b")).
+		SetFrom(exp.NewValuesListExpression([][]any{{1, "a"}, {2, "b"}}).As("v", "a", "b"))
+
+	igs.assertCases(
+		sqlgen.NewInsertSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		insertTestCase{clause: ic, sql: `INSERT INTO "test" ("a", "b") VALUES (1, 'a'), (2, 'b')`},
+		insertTestCase{
+			clause:     ic,
+			sql:        `INSERT INTO "test" ("a", "b") VALUES (?, ?), (?, ?)`,
+			isPrepared: true,
+			args:       []any{int64(1), "a", int64(2), "b"},
+		},
+	)
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.UseUnionAllForValuesList = true
+	igs.assertCases(
+		sqlgen.NewInsertSQLGenerator("test", opts),
+		insertTestCase{clause: ic, sql: `INSERT INTO "test" ("a", "b") SELECT 1 AS "a", 'a' AS "b" UNION ALL SELECT 2, 'b'`},
+	)
+}
+
 func (igs *insertSQLGeneratorSuite) TestGenerate_onConflict() {
 	opts := sqlgen.DefaultDialectOptions()
 	// make sure the fragments are used
@@ -1,6 +1,8 @@
 package sqlgen
 
 import (
+	"fmt"
+
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -33,6 +35,22 @@ func ErrDistinctOnNotSupported(dialect string) error {
 	return errors.New("dialect does not support DISTINCT ON clause [dialect=%s]", dialect)
 }
 
+func ErrRowLockingNotSupported(dialect string) error {
+	return errors.New("dialect does not support row locking [dialect=%s]", dialect)
+}
+
+func ErrLockStrengthNotSupported(dialect string, strength exp.LockStrength) error {
+	return errors.New("dialect does not support %s [dialect=%s]", strength, dialect)
+}
+
+func ErrLockWaitOptionNotSupported(dialect string, option exp.WaitOption) error {
+	return errors.New("dialect does not support %s [dialect=%s]", option, dialect)
+}
+
+func ErrLockOfNotSupported(dialect string) error {
+	return errors.New("dialect does not support OF in row locks [dialect=%s]", dialect)
+}
+
 func ErrWindowNotSupported(dialect string) error {
 	return errors.New("dialect does not support WINDOW clause [dialect=%s]", dialect)
 }
@@ -56,7 +74,7 @@ func (ssg *selectSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.SelectClaus
 		case SelectWithLimitSQLFragment:
 			ssg.SelectWithLimitSQL(b, clauses)
 		case FromSQLFragment:
-			ssg.FromSQL(b, clauses.From())
+			ssg.FromSQL(b, ssg.lockTableHints(b, clauses.From(), clauses.Lock()))
 		case JoinSQLFragment:
 			ssg.JoinSQL(b, clauses.Joins())
 		case WhereSQLFragment:
@@ -86,6 +104,13 @@ func (ssg *selectSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.SelectClaus
 }
 
 func (ssg *selectSQLGenerator) selectSQLCommon(b sb.SQLBuilder, clauses exp.SelectClauses) {
+	if hints := ssg.optimizerHints(clauses); len(hints) > 0 {
+		ssg.OptimizerHintsSQL(b, hints)
+		b.WriteRunes(ssg.DialectOptions().SpaceRune)
+		if b.Error() != nil {
+			return
+		}
+	}
 	dc := clauses.Distinct()
 	if dc != nil {
 		b.Write(ssg.DialectOptions().DistinctFragment)
@@ -110,6 +135,15 @@ func (ssg *selectSQLGenerator) selectSQLCommon(b sb.SQLBuilder, clauses exp.Sele
 	}
 }
 
+// Returns the optimizer hints of the SELECT including the execution time hint if a timeout is set
+func (ssg *selectSQLGenerator) optimizerHints(clauses exp.SelectClauses) []string {
+	hints := clauses.OptimizerHints()
+	if format := ssg.DialectOptions().MaxExecutionTimeHintFormat; format != "" && clauses.Timeout() > 0 {
+		hints = append(hints[:len(hints):len(hints)], fmt.Sprintf(format, timeoutMilliseconds(clauses.Timeout())))
+	}
+	return hints
+}
+
 // Adds the SELECT clause and columns to a sql statement
 func (ssg *selectSQLGenerator) SelectSQL(b sb.SQLBuilder, clauses exp.SelectClauses) {
 	b.Write(ssg.DialectOptions().SelectClause).WriteRunes(ssg.DialectOptions().SpaceRune)
@@ -181,46 +215,123 @@ func (ssg *selectSQLGenerator) CompoundsSQL(b sb.SQLBuilder, compounds []exp.Com
 
 // Generates the FOR (aka "locking") clause for an SQL statement
 func (ssg *selectSQLGenerator) ForSQL(b sb.SQLBuilder, lockingClause exp.Lock) {
-	if lockingClause == nil {
+	if lockingClause == nil || lockingClause.Strength() == exp.ForNolock {
 		return
 	}
-	switch lockingClause.Strength() {
-	case exp.ForNolock:
+	opts := ssg.DialectOptions()
+	if !opts.SupportsRowLocking {
+		b.SetError(ErrRowLockingNotSupported(ssg.Dialect()))
+		return
+	}
+	if opts.LockTableHintLookup != nil {
+		// the lock was added to the tables of the FROM clause, see lockTableHints
 		return
+	}
+	var strengthFragment []byte
+	switch lockingClause.Strength() {
 	case exp.ForUpdate:
-		b.Write(ssg.DialectOptions().ForUpdateFragment)
+		strengthFragment = opts.ForUpdateFragment
 	case exp.ForNoKeyUpdate:
-		b.Write(ssg.DialectOptions().ForNoKeyUpdateFragment)
+		strengthFragment = opts.ForNoKeyUpdateFragment
 	case exp.ForShare:
-		b.Write(ssg.DialectOptions().ForShareFragment)
+		strengthFragment = opts.ForShareFragment
 	case exp.ForKeyShare:
-		b.Write(ssg.DialectOptions().ForKeyShareFragment)
+		strengthFragment = opts.ForKeyShareFragment
 	}
+	if len(strengthFragment) == 0 {
+		b.SetError(ErrLockStrengthNotSupported(ssg.Dialect(), lockingClause.Strength()))
+		return
+	}
+	b.Write(strengthFragment)
 
 	of := lockingClause.Of()
 	if ofLen := len(of); ofLen > 0 {
-		if ofFragment := ssg.DialectOptions().OfFragment; len(ofFragment) > 0 {
-			b.Write(ofFragment)
-			for i, table := range of {
-				ssg.ExpressionSQLGenerator().Generate(b, table)
-				if i < ofLen-1 {
-					b.WriteRunes(ssg.DialectOptions().CommaRune, ssg.DialectOptions().SpaceRune)
-				}
+		if len(opts.OfFragment) == 0 {
+			b.SetError(ErrLockOfNotSupported(ssg.Dialect()))
+			return
+		}
+		b.Write(opts.OfFragment)
+		for i, table := range of {
+			ssg.ExpressionSQLGenerator().Generate(b, table)
+			if i < ofLen-1 {
+				b.WriteRunes(opts.CommaRune, opts.SpaceRune)
 			}
-			b.WriteRunes(ssg.DialectOptions().SpaceRune)
 		}
+		b.WriteRunes(opts.SpaceRune)
 	}
 
 	// the WAIT case is the default in Postgres, and is what you get if you don't specify NOWAIT or
 	// SKIP LOCKED. There's no special syntax for it in PG, so we don't do anything for it here
+	var waitFragment []byte
 	switch lockingClause.WaitOption() {
 	case exp.Wait:
 		return
 	case exp.NoWait:
-		b.Write(ssg.DialectOptions().NowaitFragment)
+		waitFragment = opts.NowaitFragment
 	case exp.SkipLocked:
-		b.Write(ssg.DialectOptions().SkipLockedFragment)
+		waitFragment = opts.SkipLockedFragment
+	}
+	if len(waitFragment) == 0 {
+		b.SetError(ErrLockWaitOptionNotSupported(ssg.Dialect(), lockingClause.WaitOption()))
+		return
+	}
+	b.Write(waitFragment)
+}
+
+// Translates the row lock into WITH table hints on the tables of the FROM clause, or only the tables in the OF list,
+// for dialects with a LockTableHintLookup (e.g. FROM "a" WITH (UPDLOCK, ROWLOCK, READPAST))
+func (ssg *selectSQLGenerator) lockTableHints(
+	b sb.SQLBuilder, from exp.ColumnListExpression, lockingClause exp.Lock,
+) exp.ColumnListExpression {
+	opts := ssg.DialectOptions()
+	if opts.LockTableHintLookup == nil || !opts.SupportsRowLocking || from == nil ||
+		lockingClause == nil || lockingClause.Strength() == exp.ForNolock {
+		return from
+	}
+	strengthHints, ok := opts.LockTableHintLookup[lockingClause.Strength()]
+	if !ok {
+		b.SetError(ErrLockStrengthNotSupported(ssg.Dialect(), lockingClause.Strength()))
+		return from
+	}
+	hints := append([]string(nil), strengthHints...)
+	if lockingClause.WaitOption() != exp.Wait {
+		waitHints, ok := opts.WaitOptionTableHintLookup[lockingClause.WaitOption()]
+		if !ok {
+			b.SetError(ErrLockWaitOptionNotSupported(ssg.Dialect(), lockingClause.WaitOption()))
+			return from
+		}
+		hints = append(hints, waitHints...)
+	}
+	of := make(map[string]bool, len(lockingClause.Of()))
+	for _, table := range lockingClause.Of() {
+		of[lockTableName(table)] = true
+	}
+	sources := make([]any, 0, len(from.Columns()))
+	for _, source := range from.Columns() {
+		if th, ok := source.(exp.TableHintable); ok && (len(of) == 0 || of[lockTableName(source)]) {
+			source = th.WithTableHints(hints...)
+		}
+		sources = append(sources, source)
+	}
+	return exp.NewColumnListExpression(sources...)
+}
+
+// Returns the name a FROM source is referenced by in the OF list of a row lock, the alias or the table name
+func lockTableName(source exp.Expression) string {
+	switch s := source.(type) {
+	case exp.AliasedExpression:
+		return lockTableName(s.GetAs())
+	case exp.TableHintExpression:
+		return lockTableName(s.Table())
+	case exp.IdentifierExpression:
+		if s.GetTable() != "" {
+			return s.GetTable()
+		}
+		if col, ok := s.GetCol().(string); ok {
+			return col
+		}
 	}
+	return ""
 }
 
 func (ssg *selectSQLGenerator) WindowSQL(b sb.SQLBuilder, windows []exp.WindowExpression) {
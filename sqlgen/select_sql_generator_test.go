@@ -2,6 +2,7 @@ package sqlgen_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Tooooommy/builder/v9"
 	"github.com/Tooooommy/builder/v9/exp"
@@ -192,6 +193,57 @@ func (ssgs *selectSQLGeneratorSuite) TestGenerate_withDistinct() {
 	)
 }
 
+func (ssgs *selectSQLGeneratorSuite) TestGenerate_withTimeout() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	opts.MaxExecutionTimeHintFormat = "MAX_EXECUTION_TIME(%d)"
+
+	sc := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression("test"))
+	scTimeout := sc.SetTimeout(1500 * time.Millisecond)
+	scSubMillisecond := sc.SetTimeout(time.Microsecond)
+	scDistinct := scTimeout.SetDistinct(exp.NewColumnListExpression())
+
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", opts),
+		selectTestCase{clause: sc, sql: `SELECT * FROM "test"`},
+		selectTestCase{clause: scTimeout, sql: `SELECT /*+ MAX_EXECUTION_TIME(1500) */ * FROM "test"`},
+		selectTestCase{clause: scTimeout, sql: `SELECT /*+ MAX_EXECUTION_TIME(1500) */ * FROM "test"`, isPrepared: true},
+		selectTestCase{clause: scSubMillisecond, sql: `SELECT /*+ MAX_EXECUTION_TIME(1) */ * FROM "test"`},
+		selectTestCase{clause: scDistinct, sql: `SELECT /*+ MAX_EXECUTION_TIME(1500) */ DISTINCT * FROM "test"`},
+	)
+
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		selectTestCase{clause: scTimeout, sql: `SELECT * FROM "test"`},
+	)
+}
+
+func (ssgs *selectSQLGeneratorSuite) TestGenerate_withOptimizerHints() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	opts.MaxExecutionTimeHintFormat = "MAX_EXECUTION_TIME(%d)"
+
+	sc := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression("test"))
+	scHints := sc.OptimizerHintsAppend("NO_ICP(test)", "BKA(test)")
+	scTimeout := scHints.SetTimeout(time.Second)
+	scDistinct := scHints.SetDistinct(exp.NewColumnListExpression())
+
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", opts),
+		selectTestCase{clause: scHints, sql: `SELECT /*+ NO_ICP(test) BKA(test) */ * FROM "test"`},
+		selectTestCase{clause: scHints, sql: `SELECT /*+ NO_ICP(test) BKA(test) */ * FROM "test"`, isPrepared: true},
+		selectTestCase{clause: scTimeout, sql: `SELECT /*+ NO_ICP(test) BKA(test) MAX_EXECUTION_TIME(1000) */ * FROM "test"`},
+		selectTestCase{clause: scDistinct, sql: `SELECT /*+ NO_ICP(test) BKA(test) */ DISTINCT * FROM "test"`},
+	)
+
+	expectedErr := "builder: dialect does not support optimizer hints [dialect=test]"
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		selectTestCase{clause: scHints, err: expectedErr},
+		selectTestCase{clause: scHints, err: expectedErr, isPrepared: true},
+	)
+}
+
 func (ssgs *selectSQLGeneratorSuite) TestGenerate_withFromSQL() {
 	opts := sqlgen.DefaultDialectOptions()
 	opts.FromFragment = []byte(" from")
@@ -520,6 +572,82 @@ func (ssgs *selectSQLGeneratorSuite) TestGenerate_withCompounds() {
 	)
 }
 
+func (ssgs *selectSQLGeneratorSuite) TestToSelectSQL_withUnsupportedFor() {
+	sc := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression("test"))
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsRowLocking = false
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", opts),
+		selectTestCase{clause: sc.SetLock(exp.NewLock(exp.ForNolock, exp.Wait)), sql: `SELECT * FROM "test"`},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForUpdate, exp.Wait)),
+			err:    "builder: dialect does not support row locking [dialect=test]",
+		},
+	)
+
+	opts = sqlgen.DefaultDialectOptions()
+	opts.ForShareFragment = []byte(" LOCK IN SHARE MODE ")
+	opts.ForNoKeyUpdateFragment = nil
+	opts.OfFragment = nil
+	opts.SkipLockedFragment = nil
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", opts),
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForShare, exp.NoWait)),
+			sql:    `SELECT * FROM "test" LOCK IN SHARE MODE NOWAIT`,
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForNoKeyUpdate, exp.Wait)),
+			err:    "builder: dialect does not support FOR NO KEY UPDATE [dialect=test]",
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForUpdate, exp.Wait, exp.NewIdentifierExpression("", "test", ""))),
+			err:    "builder: dialect does not support OF in row locks [dialect=test]",
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForUpdate, exp.SkipLocked)),
+			err:    "builder: dialect does not support SKIP LOCKED [dialect=test]",
+		},
+	)
+}
+
+func (ssgs *selectSQLGeneratorSuite) TestToSelectSQL_withForTableHints() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsTableHints = true
+	opts.LockTableHintLookup = map[exp.LockStrength][]string{
+		exp.ForUpdate: {"UPDLOCK", "ROWLOCK"},
+		exp.ForShare:  {"HOLDLOCK", "ROWLOCK"},
+	}
+	opts.WaitOptionTableHintLookup = map[exp.WaitOption][]string{
+		exp.SkipLocked: {"READPAST"},
+	}
+
+	sc := exp.NewSelectClauses().SetFrom(exp.NewColumnListExpression(
+		exp.NewIdentifierExpression("", "a", ""),
+		exp.NewIdentifierExpression("", "b", "").As("c"),
+	))
+	ssgs.assertCases(
+		sqlgen.NewSelectSQLGenerator("test", opts),
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForUpdate, exp.SkipLocked)),
+			sql:    `SELECT * FROM "a" WITH (UPDLOCK, ROWLOCK, READPAST), "b" AS "c" WITH (UPDLOCK, ROWLOCK, READPAST)`,
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForShare, exp.Wait, exp.NewIdentifierExpression("", "c", ""))),
+			sql:    `SELECT * FROM "a", "b" AS "c" WITH (HOLDLOCK, ROWLOCK)`,
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForKeyShare, exp.Wait)),
+			err:    "builder: dialect does not support FOR KEY SHARE [dialect=test]",
+		},
+		selectTestCase{
+			clause: sc.SetLock(exp.NewLock(exp.ForUpdate, exp.NoWait)),
+			err:    "builder: dialect does not support NOWAIT [dialect=test]",
+		},
+	)
+}
+
 func (ssgs *selectSQLGeneratorSuite) TestToSelectSQL_withFor() {
 	opts := sqlgen.DefaultDialectOptions()
 	opts.ForUpdateFragment = []byte(" for update ")
@@ -1,6 +1,7 @@
 package sqlgen
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -32,18 +33,55 @@ type (
 		SupportsWithCTE bool
 		// Set to true if the dialect supports recursive Common Table Expressions (DEFAULT=true)
 		SupportsWithCTERecursive bool
+		// Set to true if the dialect supports the MATERIALIZED and NOT MATERIALIZED hints of Common Table Expressions
+		// (DEFAULT=true)
+		SupportsCTEMaterialized bool
+		// Set to true if the dialect supports the SEARCH and CYCLE clauses of recursive Common Table Expressions
+		// (DEFAULT=true)
+		SupportsCTESearchCycle bool
 		// Set to true if multiple tables are supported in UPDATE statement. (DEFAULT=true)
 		SupportsMultipleUpdateTables bool
 		// Set to true if DISTINCT ON is supported (DEFAULT=true)
 		SupportsDistinctOn bool
 		// Set to true if LATERAL queries are supported (DEFAULT=true)
 		SupportsLateral bool
+		// Set to true if row locking clauses (e.g. FOR UPDATE) are supported (DEFAULT=true, sqlite3=false)
+		SupportsRowLocking bool
+		// Set to true if functions returning rows can be used as tables (DEFAULT=true, mysql=false)
+		SupportsTableFunctions bool
+		// Set to true if table functions support WITH ORDINALITY (DEFAULT=true, mysql=false)
+		SupportsWithOrdinality bool
+		// Set to true if table function aliases support column definition lists, AS "r"("a" int)
+		// (DEFAULT=true, mysql=false)
+		SupportsTableFunctionColumnDefinitions bool
+		// Set to true if array values, array operators (e.g. @>) and array functions (e.g. unnest) are supported
+		// (DEFAULT=true, mysql=false)
+		SupportsArrays bool
 		// Set to false if the dialect does not require expressions to be wrapped in parens (DEFAULT=true)
 		WrapCompoundsInParens bool
 
 		// Set to true if window function are supported in SELECT statement. (DEFAULT=true)
 		SupportsWindowFunction bool
 
+		// Set to true if the dialect supports savepoints within a transaction (DEFAULT=true)
+		SupportsSavepoint bool
+
+		// Set to true if the dialect supports USE INDEX, FORCE INDEX and IGNORE INDEX table hints (DEFAULT=false)
+		SupportsIndexHints bool
+		// Set to true if the dialect supports WITH (...) table hints, e.g. WITH (NOLOCK) (DEFAULT=false)
+		SupportsTableHints bool
+		// Set to true if the dialect supports /*+ ... */ optimizer hints after SELECT, UPDATE and DELETE
+		// (DEFAULT=false)
+		SupportsOptimizerHints bool
+
+		// Set to true to generate VALUES lists used as table sources as a UNION ALL of SELECTs, for dialects that do
+		// not support VALUES lists (DEFAULT=false, mysql=true)
+		UseUnionAllForValuesList bool
+		// Set to true if the dialect folds unquoted identifiers to lower case, the unquoted identifiers of queries
+		// parsed with ParseSelect are folded the same way because they are generated quoted (DEFAULT=false,
+		// postgres=true)
+		LowercaseUnquotedIdentifiers bool
+
 		// Set to true if the dialect requires join tables in UPDATE to be in a FROM clause (DEFAULT=true).
 		UseFromClauseForMultipleUpdateTables bool
 
@@ -66,6 +104,20 @@ type (
 		WithFragment []byte
 		// The RECURSIVE fragment to use when generating sql (after WITH). (DEFAULT=[]byte("RECURSIVE "))
 		RecursiveFragment []byte
+		// The MATERIALIZED fragment of a CTE (DEFAULT=[]byte("MATERIALIZED "))
+		MaterializedFragment []byte
+		// The NOT MATERIALIZED fragment of a CTE (DEFAULT=[]byte("NOT MATERIALIZED "))
+		NotMaterializedFragment []byte
+		// The SEARCH DEPTH FIRST fragment of a recursive CTE (DEFAULT=[]byte(" SEARCH DEPTH FIRST BY "))
+		SearchDepthFirstFragment []byte
+		// The SEARCH BREADTH FIRST fragment of a recursive CTE (DEFAULT=[]byte(" SEARCH BREADTH FIRST BY "))
+		SearchBreadthFirstFragment []byte
+		// The CYCLE fragment of a recursive CTE (DEFAULT=[]byte(" CYCLE "))
+		CycleFragment []byte
+		// The TO fragment of the mark column of a CYCLE clause (DEFAULT=[]byte(" TO "))
+		CycleToFragment []byte
+		// The DEFAULT fragment of the mark column of a CYCLE clause (DEFAULT=[]byte(" DEFAULT "))
+		CycleDefaultFragment []byte
 		// The CASCADE fragment to use when generating sql. (DEFAULT=[]byte(" CASCADE"))
 		CascadeFragment []byte
 		// The RESTRICT fragment to use when generating sql. (DEFAULT=[]byte(" RESTRICT"))
@@ -76,6 +128,10 @@ type (
 		// The SQL fragment to use when generating insert sql and listing columns using a VALUES clause
 		// (DEFAULT=[]byte(" VALUES "))
 		ValuesFragment []byte
+		// The SQL fragment to use when generating a VALUES list used as a table source (DEFAULT=[]byte("VALUES "))
+		ValuesListFragment []byte
+		// The SQL fragment written before every row of a VALUES list (DEFAULT=[]byte(""), mysql8=[]byte("ROW"))
+		ValuesListRowFragment []byte
 		// The SQL fragment to use when generating truncate sql and using the IDENTITY clause
 		// (DEFAULT=[]byte(" IDENTITY"))
 		IdentityFragment []byte
@@ -115,22 +171,56 @@ type (
 		OffsetFragment []byte
 		// The SQL FOR UPDATE fragment(DEFAULT=[]byte(" FOR UPDATE "))
 		ForUpdateFragment []byte
-		// The SQL FOR NO KEY UPDATE fragment(DEFAULT=[]byte(" FOR NO KEY UPDATE "))
+		// The SQL FOR NO KEY UPDATE fragment, set to nil if not supported (DEFAULT=[]byte(" FOR NO KEY UPDATE "), mysql=nil)
 		ForNoKeyUpdateFragment []byte
-		// The SQL FOR SHARE fragment(DEFAULT=[]byte(" FOR SHARE "))
+		// The SQL FOR SHARE fragment, set to nil if not supported
+		// (DEFAULT=[]byte(" FOR SHARE "), mysql=[]byte(" LOCK IN SHARE MODE "))
 		ForShareFragment []byte
-		// The SQL OF fragment(DEFAULT=[]byte("OF "))
+		// The SQL OF fragment, set to nil if not supported (DEFAULT=[]byte("OF "), mysql=nil)
 		OfFragment []byte
-		// The SQL FOR KEY SHARE fragment(DEFAULT=[]byte(" FOR KEY SHARE "))
+		// The SQL FOR KEY SHARE fragment, set to nil if not supported (DEFAULT=[]byte(" FOR KEY SHARE "), mysql=nil)
 		ForKeyShareFragment []byte
-		// The SQL NOWAIT fragment(DEFAULT=[]byte("NOWAIT"))
+		// The SQL NOWAIT fragment, set to nil if not supported (DEFAULT=[]byte("NOWAIT"), mysql=nil)
 		NowaitFragment []byte
-		// The SQL SKIP LOCKED fragment(DEFAULT=[]byte("SKIP LOCKED"))
+		// The SQL SKIP LOCKED fragment, set to nil if not supported (DEFAULT=[]byte("SKIP LOCKED"), mysql=nil)
 		SkipLockedFragment []byte
 		// The SQL AS fragment when aliasing an Expression(DEFAULT=[]byte(" AS "))
 		AsFragment []byte
 		// The SQL LATERAL fragment used for LATERAL joins
 		LateralFragment []byte
+		// The SQL fragment used for table functions numbering their rows (DEFAULT=[]byte(" WITH ORDINALITY"))
+		WithOrdinalityFragment []byte
+		// The SQL fragment used to construct an array, followed by its elements in brackets (DEFAULT=[]byte("ARRAY"))
+		ArrayFragment []byte
+		// The SQL fragment used for an interpolated empty array (DEFAULT=[]byte("'{}'"))
+		EmptyArrayFragment []byte
+		// The SQL fragment used to create a savepoint (DEFAULT=[]byte("SAVEPOINT "), sqlserver=[]byte("SAVE TRANSACTION "))
+		SavepointFragment []byte
+		// The SQL fragment used to release a savepoint. Set to an empty fragment if the dialect does not support releasing
+		// savepoints (DEFAULT=[]byte("RELEASE SAVEPOINT "), sqlserver=[]byte(""))
+		ReleaseSavepointFragment []byte
+		// The SQL fragment used to roll back to a savepoint
+		// (DEFAULT=[]byte("ROLLBACK TO SAVEPOINT "), sqlserver=[]byte("ROLLBACK TRANSACTION "))
+		RollbackToSavepointFragment []byte
+		// The SQL fragment used to set the characteristics of the current transaction. Set to an empty fragment if the
+		// dialect does not allow changing the characteristics once a transaction has started
+		// (DEFAULT=[]byte("SET TRANSACTION "), mysql=[]byte(""))
+		SetTransactionFragment []byte
+		// The SQL fragment used to set the isolation level of a transaction (DEFAULT=[]byte("ISOLATION LEVEL "))
+		IsolationLevelFragment []byte
+		// The SQL fragment used to start a read only transaction. Set to an empty fragment if the dialect does not
+		// support read only transactions (DEFAULT=[]byte("READ ONLY"))
+		ReadOnlyFragment []byte
+		// The SQL fragment used to start a deferrable transaction. Set to an empty fragment if the dialect does not
+		// support deferrable transactions (DEFAULT=[]byte("DEFERRABLE"), mysql=[]byte(""))
+		DeferrableFragment []byte
+		// The SQL fragment used to set the statement timeout, in milliseconds, for the rest of the current transaction.
+		// Set to an empty fragment if the dialect does not support it
+		// (DEFAULT=[]byte(""), postgres=[]byte("SET LOCAL statement_timeout = "))
+		StatementTimeoutFragment []byte
+		// The SQL statement used to reset the statement timeout set with StatementTimeoutFragment
+		// (DEFAULT=[]byte(""), postgres=[]byte("SET LOCAL statement_timeout = DEFAULT"))
+		ResetStatementTimeoutFragment []byte
 		// The quote rune to use when quoting identifiers(DEFAULT='"')
 		QuoteRune rune
 		// The NULL literal to use when interpolating nulls values (DEFAULT=[]byte("NULL"))
@@ -187,6 +277,10 @@ type (
 		LeftParenRune rune
 		// Right paren rune (DEFAULT=')')
 		RightParenRune rune
+		// Left bracket rune (DEFAULT='[')
+		LeftBracketRune rune
+		// Right bracket rune (DEFAULT=']')
+		RightBracketRune rune
 		// Star rune (DEFAULT='*')
 		StarRune rune
 		// Period rune (DEFAULT='.')
@@ -195,6 +289,28 @@ type (
 		IncludePlaceholderNum bool
 		// The time format to use when serializing time.Time (DEFAULT=time.RFC3339Nano)
 		TimeFormat string
+		// The format of the optimizer hint used to limit the execution time, in milliseconds, of a SELECT statement. It is
+		// added to the optimizer hints of the statement so SupportsOptimizerHints must be true. Set to an empty string if
+		// the dialect does not support it (DEFAULT="", mysql="MAX_EXECUTION_TIME(%d)")
+		MaxExecutionTimeHintFormat string
+		// The SQL fragment used to start optimizer hints (DEFAULT=[]byte("/*+ "))
+		OptimizerHintsStartFragment []byte
+		// The SQL fragment used to end optimizer hints (DEFAULT=[]byte(" */"))
+		OptimizerHintsEndFragment []byte
+		// The SQL fragment used before WITH table hints (DEFAULT=[]byte(" WITH "))
+		TableHintsFragment []byte
+		// A map used to translate row locks into WITH table hints on the tables of the FROM clause instead of a
+		// locking clause, a missing strength is not supported (DEFAULT=nil, sqlserver=map[exp.LockStrength][]string{
+		// 		exp.ForUpdate: {"UPDLOCK", "ROWLOCK"},
+		// 		exp.ForShare:  {"HOLDLOCK", "ROWLOCK"},
+		// 	})
+		LockTableHintLookup map[exp.LockStrength][]string
+		// A map used to translate the wait option of a row lock into table hints when LockTableHintLookup is set, a
+		// missing option other than exp.Wait is not supported (DEFAULT=nil, sqlserver=map[exp.WaitOption][]string{
+		// 		exp.NoWait:     {"NOWAIT"},
+		// 		exp.SkipLocked: {"READPAST"},
+		// 	})
+		WaitOptionTableHintLookup map[exp.WaitOption][]string
 		// A map used to look up BooleanOperations and their SQL equivalents
 		// (Default= map[exp.BooleanOperation][]byte{
 		// 		exp.EqOp:             []byte("="),
@@ -215,6 +331,10 @@ type (
 		// 		exp.RegexpNotLikeOp:  []byte("!~"),
 		// 		exp.RegexpILikeOp:    []byte("~*"),
 		// 		exp.RegexpNotILikeOp: []byte("!~*"),
+		// 		exp.ArrayContainsOp:    []byte("@>"),
+		// 		exp.ArrayContainedByOp: []byte("<@"),
+		// 		exp.ArrayOverlapOp:     []byte("&&"),
+		// 		exp.EqAnyOp:            []byte("= ANY"),
 		// })
 		BooleanOperatorLookup map[exp.BooleanOperation][]byte
 		// A map used to look up BitwiseOperations and their SQL equivalents
@@ -249,6 +369,21 @@ type (
 		// 		exp.CrossJoinType:        []byte(" CROSS JOIN "),
 		// 	})
 		JoinTypeLookup map[exp.JoinType][]byte
+		// A map used to look up index hints and their SQL equivalents
+		// (Default=map[exp.TableHintType][]byte{
+		// 		exp.UseIndexHint:    []byte(" USE INDEX "),
+		// 		exp.ForceIndexHint:  []byte(" FORCE INDEX "),
+		// 		exp.IgnoreIndexHint: []byte(" IGNORE INDEX "),
+		// 	})
+		IndexHintTypeLookup map[exp.TableHintType][]byte
+		// A map used to look up isolation levels and their SQL equivalents
+		// (Default=map[sql.IsolationLevel][]byte{
+		// 		sql.LevelReadUncommitted: []byte("READ UNCOMMITTED"),
+		// 		sql.LevelReadCommitted:   []byte("READ COMMITTED"),
+		// 		sql.LevelRepeatableRead:  []byte("REPEATABLE READ"),
+		// 		sql.LevelSerializable:    []byte("SERIALIZABLE"),
+		// 	})
+		IsolationLevelLookup map[sql.IsolationLevel][]byte
 		// Whether or not boolean data type is supported
 		BooleanDataTypeSupported bool
 		// Whether or not to use literal TRUE or FALSE for IS statements (e.g. IS TRUE or IS 0)
@@ -420,75 +555,110 @@ func DefaultDialectOptions() *SQLDialectOptions {
 		SupportsConflictTarget:      true,
 		SupportsWithCTE:             true,
 		SupportsWithCTERecursive:    true,
+		SupportsCTEMaterialized:     true,
+		SupportsCTESearchCycle:      true,
 		SupportsDistinctOn:          true,
 		WrapCompoundsInParens:       true,
 		SupportsWindowFunction:      true,
 		SupportsLateral:             true,
+		SupportsRowLocking:          true,
+		SupportsTableFunctions:      true,
+		SupportsWithOrdinality:      true,
+		SupportsArrays:              true,
+		SupportsSavepoint:           true,
+		SupportsIndexHints:          false,
+		SupportsTableHints:          false,
+		SupportsOptimizerHints:      false,
+
+		SupportsTableFunctionColumnDefinitions: true,
 
 		SupportsMultipleUpdateTables:         true,
 		UseFromClauseForMultipleUpdateTables: true,
 
-		UpdateClause:              []byte("UPDATE"),
-		InsertClause:              []byte("INSERT INTO"),
-		InsertIgnoreClause:        []byte("INSERT IGNORE INTO"),
-		SelectClause:              []byte("SELECT"),
-		DeleteClause:              []byte("DELETE"),
-		TruncateClause:            []byte("TRUNCATE"),
-		WithFragment:              []byte("WITH "),
-		RecursiveFragment:         []byte("RECURSIVE "),
-		CascadeFragment:           []byte(" CASCADE"),
-		RestrictFragment:          []byte(" RESTRICT"),
-		DefaultValuesFragment:     []byte(" DEFAULT VALUES"),
-		ValuesFragment:            []byte(" VALUES "),
-		IdentityFragment:          []byte(" IDENTITY"),
-		SetFragment:               []byte(" SET "),
-		DistinctFragment:          []byte("DISTINCT"),
-		ReturningFragment:         []byte(" RETURNING "),
-		FromFragment:              []byte(" FROM"),
-		UsingFragment:             []byte(" USING "),
-		OnFragment:                []byte(" ON "),
-		WhereFragment:             []byte(" WHERE "),
-		GroupByFragment:           []byte(" GROUP BY "),
-		HavingFragment:            []byte(" HAVING "),
-		WindowFragment:            []byte(" WINDOW "),
-		WindowPartitionByFragment: []byte("PARTITION BY "),
-		WindowOrderByFragment:     []byte("ORDER BY "),
-		WindowOverFragment:        []byte(" OVER "),
-		OrderByFragment:           []byte(" ORDER BY "),
-		FetchFragment:             []byte(" "),
-		LimitFragment:             []byte(" LIMIT "),
-		OffsetFragment:            []byte(" OFFSET "),
-		ForUpdateFragment:         []byte(" FOR UPDATE "),
-		ForNoKeyUpdateFragment:    []byte(" FOR NO KEY UPDATE "),
-		ForShareFragment:          []byte(" FOR SHARE "),
-		ForKeyShareFragment:       []byte(" FOR KEY SHARE "),
-		OfFragment:                []byte("OF "),
-		NowaitFragment:            []byte("NOWAIT"),
-		SkipLockedFragment:        []byte("SKIP LOCKED"),
-		LateralFragment:           []byte("LATERAL "),
-		AsFragment:                []byte(" AS "),
-		AscFragment:               []byte(" ASC"),
-		DescFragment:              []byte(" DESC"),
-		NullsFirstFragment:        []byte(" NULLS FIRST"),
-		NullsLastFragment:         []byte(" NULLS LAST"),
-		AndFragment:               []byte(" AND "),
-		OrFragment:                []byte(" OR "),
-		UnionFragment:             []byte(" UNION "),
-		UnionAllFragment:          []byte(" UNION ALL "),
-		IntersectFragment:         []byte(" INTERSECT "),
-		IntersectAllFragment:      []byte(" INTERSECT ALL "),
-		ConflictFragment:          []byte(" ON CONFLICT"),
-		ConflictDoUpdateFragment:  []byte(" DO UPDATE SET "),
-		ConflictDoNothingFragment: []byte(" DO NOTHING"),
-		CastFragment:              []byte("CAST"),
-		CaseFragment:              []byte("CASE "),
-		WhenFragment:              []byte(" WHEN "),
-		ThenFragment:              []byte(" THEN "),
-		ElseFragment:              []byte(" ELSE "),
-		EndFragment:               []byte(" END"),
-		Null:                      []byte("NULL"),
-		True:                      []byte("TRUE"),
-		False:                     []byte("FALSE"),
+		UpdateClause:               []byte("UPDATE"),
+		InsertClause:               []byte("INSERT INTO"),
+		InsertIgnoreClause:         []byte("INSERT IGNORE INTO"),
+		SelectClause:               []byte("SELECT"),
+		DeleteClause:               []byte("DELETE"),
+		TruncateClause:             []byte("TRUNCATE"),
+		WithFragment:               []byte("WITH "),
+		RecursiveFragment:          []byte("RECURSIVE "),
+		MaterializedFragment:       []byte("MATERIALIZED "),
+		NotMaterializedFragment:    []byte("NOT MATERIALIZED "),
+		SearchDepthFirstFragment:   []byte(" SEARCH DEPTH FIRST BY "),
+		SearchBreadthFirstFragment: []byte(" SEARCH BREADTH FIRST BY "),
+		CycleFragment:              []byte(" CYCLE "),
+		CycleToFragment:            []byte(" TO "),
+		CycleDefaultFragment:       []byte(" DEFAULT "),
+		CascadeFragment:            []byte(" CASCADE"),
+		RestrictFragment:           []byte(" RESTRICT"),
+		DefaultValuesFragment:      []byte(" DEFAULT VALUES"),
+		ValuesFragment:             []byte(" VALUES "),
+		ValuesListFragment:         []byte("VALUES "),
+		ValuesListRowFragment:      []byte(""),
+		IdentityFragment:           []byte(" IDENTITY"),
+		SetFragment:                []byte(" SET "),
+		DistinctFragment:           []byte("DISTINCT"),
+		ReturningFragment:          []byte(" RETURNING "),
+		FromFragment:               []byte(" FROM"),
+		UsingFragment:              []byte(" USING "),
+		OnFragment:                 []byte(" ON "),
+		WhereFragment:              []byte(" WHERE "),
+		GroupByFragment:            []byte(" GROUP BY "),
+		HavingFragment:             []byte(" HAVING "),
+		WindowFragment:             []byte(" WINDOW "),
+		WindowPartitionByFragment:  []byte("PARTITION BY "),
+		WindowOrderByFragment:      []byte("ORDER BY "),
+		WindowOverFragment:         []byte(" OVER "),
+		OrderByFragment:            []byte(" ORDER BY "),
+		FetchFragment:              []byte(" "),
+		LimitFragment:              []byte(" LIMIT "),
+		OffsetFragment:             []byte(" OFFSET "),
+		ForUpdateFragment:          []byte(" FOR UPDATE "),
+		ForNoKeyUpdateFragment:     []byte(" FOR NO KEY UPDATE "),
+		ForShareFragment:           []byte(" FOR SHARE "),
+		ForKeyShareFragment:        []byte(" FOR KEY SHARE "),
+		OfFragment:                 []byte("OF "),
+		NowaitFragment:             []byte("NOWAIT"),
+		SkipLockedFragment:         []byte("SKIP LOCKED"),
+		LateralFragment:            []byte("LATERAL "),
+		WithOrdinalityFragment:     []byte(" WITH ORDINALITY"),
+		ArrayFragment:              []byte("ARRAY"),
+		EmptyArrayFragment:         []byte("'{}'"),
+		AsFragment:                 []byte(" AS "),
+		AscFragment:                []byte(" ASC"),
+		DescFragment:               []byte(" DESC"),
+		NullsFirstFragment:         []byte(" NULLS FIRST"),
+		NullsLastFragment:          []byte(" NULLS LAST"),
+		AndFragment:                []byte(" AND "),
+		OrFragment:                 []byte(" OR "),
+		UnionFragment:              []byte(" UNION "),
+		UnionAllFragment:           []byte(" UNION ALL "),
+		IntersectFragment:          []byte(" INTERSECT "),
+		IntersectAllFragment:       []byte(" INTERSECT ALL "),
+		ConflictFragment:           []byte(" ON CONFLICT"),
+		ConflictDoUpdateFragment:   []byte(" DO UPDATE SET "),
+		ConflictDoNothingFragment:  []byte(" DO NOTHING"),
+		CastFragment:               []byte("CAST"),
+		CaseFragment:               []byte("CASE "),
+		WhenFragment:               []byte(" WHEN "),
+		ThenFragment:               []byte(" THEN "),
+		ElseFragment:               []byte(" ELSE "),
+		EndFragment:                []byte(" END"),
+		Null:                       []byte("NULL"),
+		True:                       []byte("TRUE"),
+		False:                      []byte("FALSE"),
+
+		SavepointFragment:           []byte("SAVEPOINT "),
+		ReleaseSavepointFragment:    []byte("RELEASE SAVEPOINT "),
+		RollbackToSavepointFragment: []byte("ROLLBACK TO SAVEPOINT "),
+		SetTransactionFragment:      []byte("SET TRANSACTION "),
+		IsolationLevelFragment:      []byte("ISOLATION LEVEL "),
+		ReadOnlyFragment:            []byte("READ ONLY"),
+		DeferrableFragment:          []byte("DEFERRABLE"),
+		OptimizerHintsStartFragment: []byte("/*+ "),
+		OptimizerHintsEndFragment:   []byte(" */"),
+		TableHintsFragment:          []byte(" WITH "),
 
 		PlaceHolderFragment: []byte("?"),
 		QuoteRune:           '"',
@@ -498,29 +668,35 @@ func DefaultDialectOptions() *SQLDialectOptions {
 		SpaceRune:           ' ',
 		LeftParenRune:       '(',
 		RightParenRune:      ')',
+		LeftBracketRune:     '[',
+		RightBracketRune:    ']',
 		StarRune:            '*',
 		PeriodRune:          '.',
 		EmptyString:         "",
 
 		BooleanOperatorLookup: map[exp.BooleanOperation][]byte{
-			exp.EqOp:             []byte("="),
-			exp.NeqOp:            []byte("!="),
-			exp.GtOp:             []byte(">"),
-			exp.GteOp:            []byte(">="),
-			exp.LtOp:             []byte("<"),
-			exp.LteOp:            []byte("<="),
-			exp.InOp:             []byte("IN"),
-			exp.NotInOp:          []byte("NOT IN"),
-			exp.IsOp:             []byte("IS"),
-			exp.IsNotOp:          []byte("IS NOT"),
-			exp.LikeOp:           []byte("LIKE"),
-			exp.NotLikeOp:        []byte("NOT LIKE"),
-			exp.ILikeOp:          []byte("ILIKE"),
-			exp.NotILikeOp:       []byte("NOT ILIKE"),
-			exp.RegexpLikeOp:     []byte("~"),
-			exp.RegexpNotLikeOp:  []byte("!~"),
-			exp.RegexpILikeOp:    []byte("~*"),
-			exp.RegexpNotILikeOp: []byte("!~*"),
+			exp.EqOp:               []byte("="),
+			exp.NeqOp:              []byte("!="),
+			exp.GtOp:               []byte(">"),
+			exp.GteOp:              []byte(">="),
+			exp.LtOp:               []byte("<"),
+			exp.LteOp:              []byte("<="),
+			exp.InOp:               []byte("IN"),
+			exp.NotInOp:            []byte("NOT IN"),
+			exp.IsOp:               []byte("IS"),
+			exp.IsNotOp:            []byte("IS NOT"),
+			exp.LikeOp:             []byte("LIKE"),
+			exp.NotLikeOp:          []byte("NOT LIKE"),
+			exp.ILikeOp:            []byte("ILIKE"),
+			exp.NotILikeOp:         []byte("NOT ILIKE"),
+			exp.RegexpLikeOp:       []byte("~"),
+			exp.RegexpNotLikeOp:    []byte("!~"),
+			exp.RegexpILikeOp:      []byte("~*"),
+			exp.RegexpNotILikeOp:   []byte("!~*"),
+			exp.ArrayContainsOp:    []byte("@>"),
+			exp.ArrayContainedByOp: []byte("<@"),
+			exp.ArrayOverlapOp:     []byte("&&"),
+			exp.EqAnyOp:            []byte("= ANY"),
 		},
 		BitwiseOperatorLookup: map[exp.BitwiseOperation][]byte{
 			exp.BitwiseInversionOp:  []byte("~"),
@@ -548,6 +724,17 @@ func DefaultDialectOptions() *SQLDialectOptions {
 			exp.NaturalFullJoinType:  []byte(" NATURAL FULL JOIN "),
 			exp.CrossJoinType:        []byte(" CROSS JOIN "),
 		},
+		IndexHintTypeLookup: map[exp.TableHintType][]byte{
+			exp.UseIndexHint:    []byte(" USE INDEX "),
+			exp.ForceIndexHint:  []byte(" FORCE INDEX "),
+			exp.IgnoreIndexHint: []byte(" IGNORE INDEX "),
+		},
+		IsolationLevelLookup: map[sql.IsolationLevel][]byte{
+			sql.LevelReadUncommitted: []byte("READ UNCOMMITTED"),
+			sql.LevelReadCommitted:   []byte("READ COMMITTED"),
+			sql.LevelRepeatableRead:  []byte("REPEATABLE READ"),
+			sql.LevelSerializable:    []byte("SERIALIZABLE"),
+		},
 
 		TimeFormat: time.RFC3339Nano,
 
@@ -1,6 +1,11 @@
 package sqlgen
 
-import "time"
+import (
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+)
 
 var timeLocation = time.UTC
 
@@ -13,3 +18,19 @@ func SetTimeLocation(loc *time.Location) {
 func GetTimeLocation() *time.Location {
 	return timeLocation
 }
+
+// Returns the location time.Time values are serialized in by b, see sb.Settings
+func timeLocationOf(b sb.SQLBuilder) *time.Location {
+	if s := b.Settings(); s != nil && s.TimeLocation != nil {
+		return s.TimeLocation
+	}
+	return timeLocation
+}
+
+// Returns the options used to map structs to columns by b, nil for the package level settings
+func columnMapOptionsOf(b sb.SQLBuilder) *exp.ColumnMapOptions {
+	if s := b.Settings(); s != nil {
+		return s.ColumnMapOptions
+	}
+	return nil
+}
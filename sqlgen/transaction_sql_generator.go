@@ -0,0 +1,153 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+)
+
+type (
+	// An adapter interface to be used by a TxDatabase to generate transaction control SQL for a specific dialect.
+	// See DefaultAdapter for a concrete implementation and examples.
+	TransactionSQLGenerator interface {
+		Dialect() string
+		SavepointSQL(b sb.SQLBuilder, name string)
+		ReleaseSavepointSQL(b sb.SQLBuilder, name string)
+		RollbackToSavepointSQL(b sb.SQLBuilder, name string)
+		SetTransactionSQL(b sb.SQLBuilder, opts exp.TransactionOptions)
+		StatementTimeoutSQL(b sb.SQLBuilder, timeout time.Duration)
+		ResetStatementTimeoutSQL(b sb.SQLBuilder)
+	}
+	// The default adapter. This class should be used when building a new adapter. When creating a new adapter you can
+	// either override methods, or more typically update default values.
+	// See (github.com/Tooooommy/builder/dialect/postgres)
+	transactionSQLGenerator struct {
+		CommonSQLGenerator
+	}
+)
+
+var ErrEmptySavepointName = errors.New("a savepoint name is required")
+
+func ErrSavepointNotSupported(dialect string) error {
+	return errors.New("dialect does not support SAVEPOINT [dialect=%s]", dialect)
+}
+
+func ErrSetTransactionNotSupported(dialect string) error {
+	return errors.New("dialect does not support SET TRANSACTION [dialect=%s]", dialect)
+}
+
+func errUnsupportedIsolationLevel(dialect string, level sql.IsolationLevel) error {
+	return errors.New("dialect does not support isolation level %s [dialect=%s]", level, dialect)
+}
+
+func errReadOnlyTransactionNotSupported(dialect string) error {
+	return errors.New("dialect does not support READ ONLY transactions [dialect=%s]", dialect)
+}
+
+func errDeferrableTransactionNotSupported(dialect string) error {
+	return errors.New("dialect does not support DEFERRABLE transactions [dialect=%s]", dialect)
+}
+
+func NewTransactionSQLGenerator(dialect string, do *SQLDialectOptions) TransactionSQLGenerator {
+	return &transactionSQLGenerator{NewCommonSQLGenerator(dialect, do)}
+}
+
+// Generates the statement that creates a savepoint (e.g. SAVEPOINT "sp", SAVE TRANSACTION "sp")
+func (tsg *transactionSQLGenerator) SavepointSQL(b sb.SQLBuilder, name string) {
+	tsg.savepointStatementSQL(b, tsg.DialectOptions().SavepointFragment, name)
+}
+
+// Generates the statement that releases a savepoint (e.g. RELEASE SAVEPOINT "sp"). Dialects without a release
+// statement (e.g. SQL Server) set ReleaseSavepointFragment to an empty fragment in which case nothing is written.
+func (tsg *transactionSQLGenerator) ReleaseSavepointSQL(b sb.SQLBuilder, name string) {
+	if len(tsg.DialectOptions().ReleaseSavepointFragment) == 0 {
+		return
+	}
+	tsg.savepointStatementSQL(b, tsg.DialectOptions().ReleaseSavepointFragment, name)
+}
+
+// Generates the statement that rolls back to a savepoint (e.g. ROLLBACK TO SAVEPOINT "sp")
+func (tsg *transactionSQLGenerator) RollbackToSavepointSQL(b sb.SQLBuilder, name string) {
+	tsg.savepointStatementSQL(b, tsg.DialectOptions().RollbackToSavepointFragment, name)
+}
+
+func (tsg *transactionSQLGenerator) savepointStatementSQL(b sb.SQLBuilder, fragment []byte, name string) {
+	if !tsg.DialectOptions().SupportsSavepoint {
+		b.SetError(ErrSavepointNotSupported(tsg.Dialect()))
+		return
+	}
+	if name == tsg.DialectOptions().EmptyString {
+		b.SetError(ErrEmptySavepointName)
+		return
+	}
+	b.Write(fragment)
+	tsg.ExpressionSQLGenerator().Generate(b, exp.NewIdentifierExpression("", "", name))
+}
+
+// Generates the statement that sets the characteristics of the current transaction
+// (e.g. SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY). Nothing is written if opts is empty.
+func (tsg *transactionSQLGenerator) SetTransactionSQL(b sb.SQLBuilder, opts exp.TransactionOptions) {
+	if opts.IsEmpty() {
+		return
+	}
+	do := tsg.DialectOptions()
+	var modes [][]byte
+	if opts.Isolation != sql.LevelDefault {
+		level, ok := do.IsolationLevelLookup[opts.Isolation]
+		if !ok {
+			b.SetError(errUnsupportedIsolationLevel(tsg.Dialect(), opts.Isolation))
+			return
+		}
+		modes = append(modes, append(append([]byte{}, do.IsolationLevelFragment...), level...))
+	}
+	if opts.ReadOnly {
+		if len(do.ReadOnlyFragment) == 0 {
+			b.SetError(errReadOnlyTransactionNotSupported(tsg.Dialect()))
+			return
+		}
+		modes = append(modes, do.ReadOnlyFragment)
+	}
+	if opts.Deferrable {
+		if len(do.DeferrableFragment) == 0 {
+			b.SetError(errDeferrableTransactionNotSupported(tsg.Dialect()))
+			return
+		}
+		modes = append(modes, do.DeferrableFragment)
+	}
+	if len(do.SetTransactionFragment) == 0 {
+		b.SetError(ErrSetTransactionNotSupported(tsg.Dialect()))
+		return
+	}
+	b.Write(do.SetTransactionFragment)
+	for i, mode := range modes {
+		if i > 0 {
+			b.WriteRunes(do.CommaRune, do.SpaceRune)
+		}
+		b.Write(mode)
+	}
+}
+
+// Generates the statement that sets the statement timeout for the rest of the current transaction
+// (e.g. SET LOCAL statement_timeout = 1500). Nothing is written if the dialect does not support it.
+func (tsg *transactionSQLGenerator) StatementTimeoutSQL(b sb.SQLBuilder, timeout time.Duration) {
+	if len(tsg.DialectOptions().StatementTimeoutFragment) == 0 || timeout <= 0 {
+		return
+	}
+	b.Write(tsg.DialectOptions().StatementTimeoutFragment)
+	b.WriteStrings(strconv.FormatInt(timeoutMilliseconds(timeout), 10))
+}
+
+// Generates the statement that resets the statement timeout (e.g. SET LOCAL statement_timeout = DEFAULT). Nothing is
+// written if the dialect does not support it.
+func (tsg *transactionSQLGenerator) ResetStatementTimeoutSQL(b sb.SQLBuilder) {
+	b.Write(tsg.DialectOptions().ResetStatementTimeoutFragment)
+}
+
+// converts timeout to milliseconds rounding up so a timeout of less than a millisecond is not treated as no timeout
+func timeoutMilliseconds(timeout time.Duration) int64 {
+	return int64((timeout + time.Millisecond - 1) / time.Millisecond)
+}
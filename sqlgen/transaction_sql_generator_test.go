@@ -0,0 +1,209 @@
+package sqlgen_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Tooooommy/builder/v9/exp"
+	"github.com/Tooooommy/builder/v9/internal/sb"
+	"github.com/Tooooommy/builder/v9/sqlgen"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	savepointTestCase struct {
+		gen  func(b sb.SQLBuilder, name string)
+		name string
+		sql  string
+		err  string
+	}
+	setTransactionTestCase struct {
+		opts exp.TransactionOptions
+		sql  string
+		err  string
+	}
+	transactionSQLGeneratorSuite struct {
+		baseSQLGeneratorSuite
+	}
+)
+
+func (tsgs *transactionSQLGeneratorSuite) assertCases(testCases ...savepointTestCase) {
+	for _, tc := range testCases {
+		b := sb.NewSQLBuilder(false)
+		tc.gen(b, tc.name)
+		if len(tc.err) > 0 {
+			tsgs.assertErrorSQL(b, tc.err)
+		} else {
+			tsgs.assertNotPreparedSQL(b, tc.sql)
+		}
+	}
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestDialect() {
+	opts := sqlgen.DefaultDialectOptions()
+	d := sqlgen.NewTransactionSQLGenerator("test", opts)
+	tsgs.Equal("test", d.Dialect())
+
+	opts2 := sqlgen.DefaultDialectOptions()
+	d2 := sqlgen.NewTransactionSQLGenerator("test2", opts2)
+	tsgs.Equal("test2", d2.Dialect())
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestSavepointSQL() {
+	opts := sqlgen.DefaultDialectOptions()
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	opts2 := sqlgen.DefaultDialectOptions()
+	opts2.QuoteRune = '`'
+	tsg2 := sqlgen.NewTransactionSQLGenerator("test", opts2)
+
+	tsgs.assertCases(
+		savepointTestCase{gen: tsg.SavepointSQL, name: "sp_1", sql: `SAVEPOINT "sp_1"`},
+		savepointTestCase{gen: tsg.ReleaseSavepointSQL, name: "sp_1", sql: `RELEASE SAVEPOINT "sp_1"`},
+		savepointTestCase{gen: tsg.RollbackToSavepointSQL, name: "sp_1", sql: `ROLLBACK TO SAVEPOINT "sp_1"`},
+
+		savepointTestCase{gen: tsg2.SavepointSQL, name: "sp_1", sql: "SAVEPOINT `sp_1`"},
+		savepointTestCase{gen: tsg2.ReleaseSavepointSQL, name: "sp_1", sql: "RELEASE SAVEPOINT `sp_1`"},
+		savepointTestCase{gen: tsg2.RollbackToSavepointSQL, name: "sp_1", sql: "ROLLBACK TO SAVEPOINT `sp_1`"},
+
+		savepointTestCase{gen: tsg.SavepointSQL, name: "", err: "builder: a savepoint name is required"},
+	)
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestSavepointSQL_withoutRelease() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SavepointFragment = []byte("SAVE TRANSACTION ")
+	opts.ReleaseSavepointFragment = []byte("")
+	opts.RollbackToSavepointFragment = []byte("ROLLBACK TRANSACTION ")
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	tsgs.assertCases(
+		savepointTestCase{gen: tsg.SavepointSQL, name: "sp_1", sql: `SAVE TRANSACTION "sp_1"`},
+		savepointTestCase{gen: tsg.ReleaseSavepointSQL, name: "sp_1", sql: ``},
+		savepointTestCase{gen: tsg.RollbackToSavepointSQL, name: "sp_1", sql: `ROLLBACK TRANSACTION "sp_1"`},
+	)
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestSavepointSQL_notSupported() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsSavepoint = false
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	expectedErr := "builder: dialect does not support SAVEPOINT [dialect=test]"
+	tsgs.assertCases(
+		savepointTestCase{gen: tsg.SavepointSQL, name: "sp_1", err: expectedErr},
+		savepointTestCase{gen: tsg.ReleaseSavepointSQL, name: "sp_1", err: expectedErr},
+		savepointTestCase{gen: tsg.RollbackToSavepointSQL, name: "sp_1", err: expectedErr},
+	)
+}
+
+func (tsgs *transactionSQLGeneratorSuite) assertSetTransactionCases(
+	tsg sqlgen.TransactionSQLGenerator,
+	testCases ...setTransactionTestCase,
+) {
+	for _, tc := range testCases {
+		b := sb.NewSQLBuilder(false)
+		tsg.SetTransactionSQL(b, tc.opts)
+		if len(tc.err) > 0 {
+			tsgs.assertErrorSQL(b, tc.err)
+		} else {
+			tsgs.assertNotPreparedSQL(b, tc.sql)
+		}
+	}
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestSetTransactionSQL() {
+	opts := sqlgen.DefaultDialectOptions()
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	tsgs.assertSetTransactionCases(tsg,
+		setTransactionTestCase{opts: exp.TransactionOptions{}, sql: ``},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelReadUncommitted},
+			sql:  `SET TRANSACTION ISOLATION LEVEL READ UNCOMMITTED`,
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelReadCommitted},
+			sql:  `SET TRANSACTION ISOLATION LEVEL READ COMMITTED`,
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelRepeatableRead},
+			sql:  `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ`,
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelSerializable},
+			sql:  `SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`,
+		},
+		setTransactionTestCase{opts: exp.TransactionOptions{ReadOnly: true}, sql: `SET TRANSACTION READ ONLY`},
+		setTransactionTestCase{opts: exp.TransactionOptions{Deferrable: true}, sql: `SET TRANSACTION DEFERRABLE`},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true},
+			sql:  `SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY, DEFERRABLE`,
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelSnapshot},
+			err:  "builder: dialect does not support isolation level Snapshot [dialect=test]",
+		},
+	)
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestSetTransactionSQL_notSupported() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SetTransactionFragment = []byte("")
+	opts.ReadOnlyFragment = []byte("")
+	opts.DeferrableFragment = []byte("")
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	tsgs.assertSetTransactionCases(tsg,
+		setTransactionTestCase{opts: exp.TransactionOptions{}, sql: ``},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Isolation: sql.LevelSerializable},
+			err:  "builder: dialect does not support SET TRANSACTION [dialect=test]",
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{ReadOnly: true},
+			err:  "builder: dialect does not support READ ONLY transactions [dialect=test]",
+		},
+		setTransactionTestCase{
+			opts: exp.TransactionOptions{Deferrable: true},
+			err:  "builder: dialect does not support DEFERRABLE transactions [dialect=test]",
+		},
+	)
+}
+
+func (tsgs *transactionSQLGeneratorSuite) TestStatementTimeoutSQL() {
+	opts := sqlgen.DefaultDialectOptions()
+	opts.StatementTimeoutFragment = []byte("SET LOCAL statement_timeout = ")
+	opts.ResetStatementTimeoutFragment = []byte("SET LOCAL statement_timeout = DEFAULT")
+	tsg := sqlgen.NewTransactionSQLGenerator("test", opts)
+
+	b := sb.NewSQLBuilder(false)
+	tsg.StatementTimeoutSQL(b, 1500*time.Millisecond)
+	tsgs.assertNotPreparedSQL(b, `SET LOCAL statement_timeout = 1500`)
+
+	b = sb.NewSQLBuilder(true)
+	tsg.StatementTimeoutSQL(b, time.Microsecond)
+	tsgs.assertPreparedSQL(b, `SET LOCAL statement_timeout = 1`, nil)
+
+	b = sb.NewSQLBuilder(false)
+	tsg.StatementTimeoutSQL(b, 0)
+	tsgs.assertNotPreparedSQL(b, ``)
+
+	b = sb.NewSQLBuilder(false)
+	tsg.ResetStatementTimeoutSQL(b)
+	tsgs.assertNotPreparedSQL(b, `SET LOCAL statement_timeout = DEFAULT`)
+
+	tsg = sqlgen.NewTransactionSQLGenerator("test", sqlgen.DefaultDialectOptions())
+	b = sb.NewSQLBuilder(false)
+	tsg.StatementTimeoutSQL(b, time.Second)
+	tsgs.assertNotPreparedSQL(b, ``)
+
+	b = sb.NewSQLBuilder(false)
+	tsg.ResetStatementTimeoutSQL(b)
+	tsgs.assertNotPreparedSQL(b, ``)
+}
+
+func TestTransactionSQLGenerator(t *testing.T) {
+	suite.Run(t, new(transactionSQLGeneratorSuite))
+}
@@ -42,7 +42,7 @@ func (usg *updateSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.UpdateClaus
 	if !usg.DialectOptions().SupportsMultipleUpdateTables && clauses.HasFrom() {
 		b.SetError(errors.New("%s dialect does not support multiple tables in UPDATE", usg.Dialect()))
 	}
-	updates, err := exp.NewUpdateExpressions(clauses.SetValues())
+	updates, err := exp.NewUpdateExpressionsWithOptions(columnMapOptionsOf(b), clauses.SetValues())
 	if err != nil {
 		b.SetError(err)
 		return
@@ -56,6 +56,7 @@ func (usg *updateSQLGenerator) Generate(b sb.SQLBuilder, clauses exp.UpdateClaus
 			usg.ExpressionSQLGenerator().Generate(b, clauses.CommonTables())
 		case UpdateBeginSQLFragment:
 			usg.UpdateBeginSQL(b)
+			usg.optimizerHintsSQL(b, clauses.OptimizerHints())
 		case SourcesSQLFragment:
 			usg.updateTableSQL(b, clauses)
 		case UpdateSQLFragment:
@@ -85,6 +86,13 @@ func (usg *updateSQLGenerator) UpdateBeginSQL(b sb.SQLBuilder) {
 	b.Write(usg.DialectOptions().UpdateClause)
 }
 
+func (usg *updateSQLGenerator) optimizerHintsSQL(b sb.SQLBuilder, hints []string) {
+	if len(hints) > 0 {
+		b.WriteRunes(usg.DialectOptions().SpaceRune)
+		usg.OptimizerHintsSQL(b, hints)
+	}
+}
+
 // Adds column setters in an update SET clause
 func (usg *updateSQLGenerator) UpdateExpressionsSQL(b sb.SQLBuilder, updates ...exp.UpdateExpression) {
 	b.Write(usg.DialectOptions().SetFragment)
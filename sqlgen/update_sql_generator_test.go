@@ -220,6 +220,28 @@ func (usgs *updateSQLGeneratorSuite) TestGenerate_withLimit() {
 	)
 }
 
+func (usgs *updateSQLGeneratorSuite) TestGenerate_withOptimizerHints() {
+	uc := exp.NewUpdateClauses().
+		SetTable(exp.NewIdentifierExpression("", "test", "")).
+		SetSetValues(exp.Record{"a": "b"}).
+		OptimizerHintsAppend("NO_ICP(test)", "BKA(test)")
+
+	opts := sqlgen.DefaultDialectOptions()
+	opts.SupportsOptimizerHints = true
+	usgs.assertCases(
+		sqlgen.NewUpdateSQLGenerator("test", opts),
+		updateTestCase{clause: uc, sql: `UPDATE /*+ NO_ICP(test) BKA(test) */ "test" SET "a"='b'`},
+		updateTestCase{clause: uc, sql: `UPDATE /*+ NO_ICP(test) BKA(test) */ "test" SET "a"=?`, isPrepared: true, args: []any{"b"}},
+	)
+
+	expectedErr := "builder: dialect does not support optimizer hints [dialect=test]"
+	usgs.assertCases(
+		sqlgen.NewUpdateSQLGenerator("test", sqlgen.DefaultDialectOptions()),
+		updateTestCase{clause: uc, err: expectedErr},
+		updateTestCase{clause: uc, err: expectedErr, isPrepared: true},
+	)
+}
+
 func (usgs *updateSQLGeneratorSuite) TestGenerate_withCommonTables() {
 	tse := newTestAppendableExpression("select * from foo", emptyArgs, nil, nil)
 	uc := exp.NewUpdateClauses().
@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Tooooommy/builder/v9/exp"
+)
+
+type (
+	// Describes a statement executed through a Database, used to name and label spans and metrics
+	statement struct {
+		// the kind of the statement e.g. SELECT, INSERT, UPDATE, DELETE or TRUNCATE
+		operation string
+		// the primary table of the statement, empty if unknown
+		table string
+	}
+
+	statementContextKey struct{}
+)
+
+// Creates the statement of a dataset, table is the expression of the primary table of the dataset
+// (e.g. SelectClauses#From)
+func newStatement(operation string, table any) statement {
+	return statement{operation: operation, table: tableName(table)}
+}
+
+// Returns a copy of ctx that carries the statement
+func contextWithStatement(ctx context.Context, stmt statement) context.Context {
+	return context.WithValue(ctx, statementContextKey{}, stmt)
+}
+
+// Returns the statement stored in ctx by a dataset, for raw queries the operation is the first keyword of query.
+func statementFromContext(ctx context.Context, query string) statement {
+	if stmt, ok := ctx.Value(statementContextKey{}).(statement); ok {
+		return stmt
+	}
+	return statement{operation: sqlOperation(query)}
+}
+
+// Returns the first keyword of query in upper case, e.g. SELECT
+func sqlOperation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexFunc(query, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end >= 0 {
+		query = query[:end]
+	}
+	return strings.ToUpper(query)
+}
+
+// Returns the name of the first table referenced by the expression, empty if the expression is not a table reference
+// (e.g. a sub select)
+func tableName(table any) string {
+	switch t := table.(type) {
+	case string:
+		return t
+	case exp.TableHintExpression:
+		return tableName(t.Table())
+	case exp.AliasedExpression:
+		return tableName(t.Aliased())
+	case exp.IdentifierExpression:
+		if name := t.GetTable(); name != "" {
+			return name
+		}
+		if name, ok := t.GetCol().(string); ok && name != "*" {
+			return name
+		}
+	case exp.ColumnListExpression:
+		if !t.IsEmpty() {
+			return tableName(t.Columns()[0])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,190 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options used to configure the spans created by a Database. See Database#Tracer
+type TracingOptions struct {
+	// Used to sanitize the db.statement attribute, e.g. SanitizeSQL to remove literal values. When nil the statement
+	// is recorded as it was executed.
+	StatementSanitizer func(query string) string
+	// Set to true to omit the db.statement attribute
+	OmitStatement bool
+}
+
+// Attributes added to the spans created by a Database
+const (
+	dbSystemKey       = attribute.Key("db.system")
+	dbStatementKey    = attribute.Key("db.statement")
+	dbOperationKey    = attribute.Key("db.operation")
+	dbSQLTableKey     = attribute.Key("db.sql.table")
+	dbRowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+// Enables OpenTelemetry tracing. Every statement executed through this Database, including the statements of its
+// datasets, is wrapped in a client span with the db.system, db.statement, db.operation and db.sql.table attributes and
+// the number of rows affected by Exec statements. Transactions started with Transact and TransactCtx are wrapped in a
+// span, the statements executed with the context passed to the transaction function are its children.
+//
+// Passing a nil tracer disables tracing, only the first TracingOptions are used.
+//
+//	db.Tracer(otel.Tracer("user-api"), builder.TracingOptions{StatementSanitizer: builder.SanitizeSQL})
+func (d *Database) Tracer(tracer trace.Tracer, opts ...TracingOptions) {
+	d.tracer = tracer
+	d.tracing = TracingOptions{}
+	if len(opts) > 0 {
+		d.tracing = opts[0]
+	}
+}
+
+// starts the span of a statement, the span is named after the operation and table of the statement (e.g. SELECT user)
+func (d *Database) startStatementSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	stmt := statementFromContext(ctx, query)
+	attrs := []attribute.KeyValue{dbSystemKey.String(dbSystem(d.dialect))}
+	if !d.tracing.OmitStatement {
+		if d.tracing.StatementSanitizer != nil {
+			query = d.tracing.StatementSanitizer(query)
+		}
+		attrs = append(attrs, dbStatementKey.String(query))
+	}
+	name := stmt.operation
+	if stmt.operation != "" {
+		attrs = append(attrs, dbOperationKey.String(stmt.operation))
+	}
+	if stmt.table != "" {
+		attrs = append(attrs, dbSQLTableKey.String(stmt.table))
+		name = strings.TrimSpace(name + " " + stmt.table)
+	}
+	if name == "" {
+		name = d.dialect
+	}
+	return d.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// starts the span of a transaction
+func (d *Database) startTransactionSpan(ctx context.Context) (context.Context, trace.Span) {
+	return d.tracer.Start(
+		ctx,
+		"transaction",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystemKey.String(dbSystem(d.dialect))),
+	)
+}
+
+// ends the span of a statement, recording the rows affected by res
+func endStatementSpan(span trace.Span, res sql.Result, err error) {
+	if res != nil && err == nil {
+		if rows, rowsErr := res.RowsAffected(); rowsErr == nil {
+			span.SetAttributes(dbRowsAffectedKey.Int64(rows))
+		}
+	}
+	endSpan(span, err)
+}
+
+// ends the span recording err, not finding a row is not recorded as an error
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sqlx.ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Returns the db.system of a dialect
+func dbSystem(dialect string) string {
+	switch dialect {
+	case "postgres":
+		return "postgresql"
+	case "mysql", "mysql8":
+		return "mysql"
+	case "sqlite3":
+		return "sqlite"
+	case "sqlserver":
+		return "mssql"
+	}
+	return dialect
+}
+
+// Replaces the string and numeric literals of query with a ?, quoted identifiers, placeholders and comments are kept.
+// Can be used as TracingOptions#StatementSanitizer to keep values out of the traces of interpolated statements.
+//
+//	SanitizeSQL(`SELECT * FROM "user" WHERE ("name" = 'Bob') LIMIT 10`) -> SELECT * FROM "user" WHERE ("name" = ?) LIMIT ?
+func SanitizeSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'':
+			i = skipQuoted(query, i, '\'')
+			b.WriteByte('?')
+			continue
+		case c == '"' || c == '`' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			j := skipQuoted(query, i, end)
+			b.WriteString(query[i:j])
+			i = j
+			continue
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			j := strings.Index(query[i+2:], "*/")
+			if j < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+j+4])
+			i += j + 4
+			continue
+		case isDigit(c) && (i == 0 || !isIdentifierByte(query[i-1])):
+			j := i
+			for j < len(query) && (isDigit(query[j]) || query[j] == '.') {
+				j++
+			}
+			b.WriteByte('?')
+			i = j
+			continue
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// returns the index after the quoted string or identifier starting at start, doubled quotes and backslash escapes
+// are skipped
+func skipQuoted(query string, start int, end byte) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if end == '\'' {
+				i++
+			}
+		case end:
+			if i+1 < len(query) && query[i+1] == end {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
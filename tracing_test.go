@@ -0,0 +1,228 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Tooooommy/builder/v9"
+	"github.com/Tooooommy/builder/v9/internal/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	tracer   trace.Tracer
+}
+
+func (ts *tracingSuite) SetupTest() {
+	ts.recorder = tracetest.NewSpanRecorder()
+	ts.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(ts.recorder)).Tracer("builder-test")
+}
+
+func (ts *tracingSuite) newDB(dialect string) (*builder.Database, sqlmock.Sqlmock) {
+	mDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ts.Require().NoError(err)
+	return builder.New(dialect, sqlx.NewSqlConnFromDB(mDB)), mock
+}
+
+// returns the ended spans created by builder, go-zero creates its own spans for every statement
+func (ts *tracingSuite) spans() []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range ts.recorder.Ended() {
+		if span.InstrumentationScope().Name == "builder-test" {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func (ts *tracingSuite) attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func (ts *tracingSuite) TestTracer() {
+	db, mock := ts.newDB("postgres")
+	db.Tracer(ts.tracer)
+
+	mock.ExpectQuery(`SELECT * FROM "items" WHERE ("name" = 'Bob')`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "name"}).AddRow("111 Test Addr", "Bob"))
+	mock.ExpectExec(`UPDATE "items" AS "i" SET "name"='Sally'`).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	var items []testItem
+	ts.NoError(db.From("items").Where(builder.C("name").Eq("Bob")).QueryRowsCtx(context.Background(), &items))
+	_, err := db.Update(builder.T("items").As("i")).Set(builder.Record{"name": "Sally"}).AllowFullTable().Exec()
+	ts.NoError(err)
+	ts.NoError(mock.ExpectationsWereMet())
+
+	spans := ts.spans()
+	ts.Require().Len(spans, 2)
+
+	ts.Equal("SELECT items", spans[0].Name())
+	ts.Equal(trace.SpanKindClient, spans[0].SpanKind())
+	ts.Equal(map[attribute.Key]attribute.Value{
+		"db.system":    attribute.StringValue("postgresql"),
+		"db.statement": attribute.StringValue(`SELECT * FROM "items" WHERE ("name" = 'Bob')`),
+		"db.operation": attribute.StringValue("SELECT"),
+		"db.sql.table": attribute.StringValue("items"),
+	}, ts.attributes(spans[0]))
+	ts.Equal(codes.Unset, spans[0].Status().Code)
+
+	ts.Equal("UPDATE items", spans[1].Name())
+	ts.Equal(map[attribute.Key]attribute.Value{
+		"db.system":        attribute.StringValue("postgresql"),
+		"db.statement":     attribute.StringValue(`UPDATE "items" AS "i" SET "name"='Sally'`),
+		"db.operation":     attribute.StringValue("UPDATE"),
+		"db.sql.table":     attribute.StringValue("items"),
+		"db.rows_affected": attribute.Int64Value(2),
+	}, ts.attributes(spans[1]))
+}
+
+func (ts *tracingSuite) TestTracer_rawQuery() {
+	db, mock := ts.newDB("mysql")
+	db.Tracer(ts.tracer)
+
+	mock.ExpectExec("delete from items").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := db.ExecCtx(context.Background(), "delete from items")
+	ts.NoError(err)
+
+	spans := ts.spans()
+	ts.Require().Len(spans, 1)
+	ts.Equal("DELETE", spans[0].Name())
+	ts.Equal(map[attribute.Key]attribute.Value{
+		"db.system":        attribute.StringValue("mysql"),
+		"db.statement":     attribute.StringValue("delete from items"),
+		"db.operation":     attribute.StringValue("DELETE"),
+		"db.rows_affected": attribute.Int64Value(1),
+	}, ts.attributes(spans[0]))
+}
+
+func (ts *tracingSuite) TestTracer_error() {
+	db, mock := ts.newDB("mock")
+	db.Tracer(ts.tracer)
+
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnError(errors.New("mock error"))
+	mock.ExpectQuery(`SELECT * FROM "items" LIMIT 1`).WillReturnRows(sqlmock.NewRows([]string{"address", "name"}))
+
+	_, err := db.Delete("items").AllowFullTable().Exec()
+	ts.EqualError(err, "builder: mock error")
+
+	var item testItem
+	ts.ErrorIs(db.From("items").QueryRow(&item), sqlx.ErrNotFound)
+
+	spans := ts.spans()
+	ts.Require().Len(spans, 2)
+	ts.Equal(codes.Error, spans[0].Status().Code)
+	ts.Equal("builder: mock error", spans[0].Status().Description)
+	ts.Require().Len(spans[0].Events(), 1)
+	ts.Equal("exception", spans[0].Events()[0].Name)
+
+	// not finding a row is not an error
+	ts.Equal(codes.Unset, spans[1].Status().Code)
+	ts.Empty(spans[1].Events())
+}
+
+func (ts *tracingSuite) TestTracer_options() {
+	db, mock := ts.newDB("mock")
+	db.Tracer(ts.tracer, builder.TracingOptions{StatementSanitizer: builder.SanitizeSQL})
+
+	mock.ExpectExec(`DELETE FROM "items" WHERE ("id" = 10)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items" WHERE ("id" = 10)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items" WHERE ("id" = 10)`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ds := db.Delete("items").Where(builder.C("id").Eq(10))
+	_, err := ds.Exec()
+	ts.NoError(err)
+
+	db.Tracer(ts.tracer, builder.TracingOptions{OmitStatement: true})
+	_, err = ds.Exec()
+	ts.NoError(err)
+
+	db.Tracer(nil)
+	_, err = ds.Exec()
+	ts.NoError(err)
+
+	spans := ts.spans()
+	ts.Require().Len(spans, 2)
+	ts.Equal(attribute.StringValue(`DELETE FROM "items" WHERE ("id" = ?)`), ts.attributes(spans[0])["db.statement"])
+	ts.NotContains(ts.attributes(spans[1]), attribute.Key("db.statement"))
+}
+
+func (ts *tracingSuite) TestTracer_transaction() {
+	db, mock := ts.newDB("mock")
+	db.Tracer(ts.tracer)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "items" SET "name"='Bob'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "items"`).WillReturnError(errors.New("mock error"))
+	mock.ExpectRollback()
+
+	err := db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		if _, err := td.Update("items").Set(builder.Record{"name": "Bob"}).AllowFullTable().ExecCtx(ctx); err != nil {
+			return err
+		}
+		_, err := db.Delete("items").AllowFullTable().ExecCtx(ctx)
+		return err
+	})
+	ts.NoError(err)
+
+	err = db.TransactCtx(context.Background(), func(ctx context.Context, td *builder.TxDatabase) error {
+		_, err := td.Delete("items").AllowFullTable().ExecCtx(ctx)
+		return err
+	})
+	ts.EqualError(err, "builder: mock error")
+	ts.NoError(mock.ExpectationsWereMet())
+
+	spans := ts.spans()
+	ts.Require().Len(spans, 5)
+	ts.Equal("UPDATE items", spans[0].Name())
+	ts.Equal("DELETE items", spans[1].Name())
+	ts.Equal("transaction", spans[2].Name())
+	ts.Equal(codes.Unset, spans[2].Status().Code)
+	// go-zero adds a span for the transaction between the transaction span and the statement spans
+	ts.False(spans[2].Parent().IsValid())
+	ts.Equal(spans[2].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	ts.Equal(spans[2].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+
+	ts.Equal("DELETE items", spans[3].Name())
+	ts.Equal("transaction", spans[4].Name())
+	ts.Equal(codes.Error, spans[4].Status().Code)
+	ts.Equal(spans[4].SpanContext().TraceID(), spans[3].SpanContext().TraceID())
+	ts.NotEqual(spans[2].SpanContext().TraceID(), spans[4].SpanContext().TraceID())
+}
+
+func (ts *tracingSuite) TestSanitizeSQL() {
+	cases := []struct {
+		sql      string
+		expected string
+	}{
+		{sql: `SELECT * FROM "user" WHERE ("name" = 'Bob') LIMIT 10`, expected: `SELECT * FROM "user" WHERE ("name" = ?) LIMIT ?`},
+		{sql: "SELECT * FROM `t1` WHERE (`a` IN (1, 2.5, -3))", expected: "SELECT * FROM `t1` WHERE (`a` IN (?, ?, -?))"},
+		{sql: `SELECT * FROM "t" WHERE ("a" = 'it''s' AND "b" = 'a\'b')`, expected: `SELECT * FROM "t" WHERE ("a" = ? AND "b" = ?)`},
+		{sql: `SELECT "col1", "it's" FROM "t2" WHERE ("a" = $1)`, expected: `SELECT "col1", "it's" FROM "t2" WHERE ("a" = $1)`},
+		{sql: `SELECT /*+ MAX_EXECUTION_TIME(500) */ * FROM t WHERE a = ?`, expected: `SELECT /*+ MAX_EXECUTION_TIME(500) */ * FROM t WHERE a = ?`},
+		{sql: `SELECT [a b] FROM t WHERE x = 'unterminated`, expected: `SELECT [a b] FROM t WHERE x = ?`},
+	}
+	for _, c := range cases {
+		ts.Equal(c.expected, builder.SanitizeSQL(c.sql), c.sql)
+	}
+}
+
+func TestTracingSuite(t *testing.T) {
+	suite.Run(t, new(tracingSuite))
+}
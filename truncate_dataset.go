@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/sb"
@@ -15,25 +16,28 @@ type TruncateDataset struct {
 	isPrepared prepared
 	executor   sqlx.Session
 	err        error
+	// set to true to execute the statement through a Database that refuses TRUNCATE, see AllowFullTable
+	allowFullTable bool
 }
 
 // used internally by database to create a database with a specific adapter
-func newTruncateDataset(d string, executor sqlx.Session) *TruncateDataset {
+func newTruncateDataset(d string, executor sqlx.Session, s *settings) *TruncateDataset {
 	return &TruncateDataset{
-		clauses:  exp.NewTruncateClauses(),
-		dialect:  GetDialect(d),
-		executor: executor,
+		clauses:    exp.NewTruncateClauses(),
+		dialect:    s.dialect(d),
+		executor:   executor,
+		isPrepared: s.isPrepared(),
 	}
 }
 
 func Truncate(table ...any) *TruncateDataset {
-	return newTruncateDataset("default", nil).Table(table...)
+	return newTruncateDataset("default", nil, nil).Table(table...)
 }
 
 // Sets the adapter used to serialize values and create the SQL statement
 func (td *TruncateDataset) WithDialect(dl string) *TruncateDataset {
 	ds := td.copy(td.GetClauses())
-	ds.dialect = GetDialect(dl)
+	ds.dialect = inheritSettings(GetDialect(dl), ds.dialect)
 	return ds
 }
 
@@ -76,17 +80,34 @@ func (td *TruncateDataset) GetClauses() exp.TruncateClauses {
 	return td.clauses
 }
 
+// Sets a timeout for executing the statement of this dataset. The context passed to TruncateCtx etc. times out after
+// timeout, where supported by the dialect the timeout is also enforced by the database (e.g. SET LOCAL
+// statement_timeout inside of postgres transactions).
+//
+// A timeout less than or equal to zero removes the timeout.
+func (td *TruncateDataset) WithTimeout(timeout time.Duration) *TruncateDataset {
+	return td.copy(td.clauses.SetTimeout(timeout))
+}
+
 // used interally to copy the dataset
 func (td *TruncateDataset) copy(clauses exp.TruncateClauses) *TruncateDataset {
 	return &TruncateDataset{
-		dialect:    td.dialect,
-		clauses:    clauses,
-		isPrepared: td.isPrepared,
-		executor:   td.executor,
-		err:        td.err,
+		dialect:        td.dialect,
+		clauses:        clauses,
+		isPrepared:     td.isPrepared,
+		executor:       td.executor,
+		err:            td.err,
+		allowFullTable: td.allowFullTable,
 	}
 }
 
+// Allows the statement to be executed, TRUNCATE statements are refused by default. See Database#AllowFullTable
+func (td *TruncateDataset) AllowFullTable() *TruncateDataset {
+	ret := td.copy(td.clauses)
+	ret.allowFullTable = true
+	return ret
+}
+
 // Adds a FROM clause. This return a new dataset with the original sources replaced. See examples.
 // You can pass in the following.
 //
@@ -169,11 +190,28 @@ func (td *TruncateDataset) TruncateCtx(ctx context.Context) (sql.Result, error)
 	if td.executor == nil {
 		return nil, ErrExecutorNotFoundError
 	}
+	if !td.allowFullTable && refusesFullTable(td.executor) {
+		return nil, ErrTruncateNotAllowed
+	}
 	query, args, err := td.truncateSQLBuilder().ToSQL()
 	if err != nil {
 		return nil, err
 	}
-	return td.executor.ExecCtx(ctx, query, args...)
+	return td.session(ctx).ExecCtx(ctx, query, args...)
+}
+
+// Generates the fingerprint of the TRUNCATE statement, the values of the dataset are replaced with placeholders so
+// datasets with the same shape have the same fingerprint. See Fingerprint.
+//
+// Errors:
+//   - There is an error generating the SQL
+func (td *TruncateDataset) Fingerprint() (Fingerprint, error) {
+	if td.err != nil {
+		return Fingerprint{}, td.err
+	}
+	return fingerprint(func(b sb.SQLBuilder) {
+		td.dialect.ToTruncateSQL(b, td.clauses)
+	})
 }
 
 func (td *TruncateDataset) truncateSQLBuilder() sb.SQLBuilder {
@@ -184,3 +222,9 @@ func (td *TruncateDataset) truncateSQLBuilder() sb.SQLBuilder {
 	td.dialect.ToTruncateSQL(buf, td.clauses)
 	return buf
 }
+
+// returns the session to execute statements with, see datasetSession
+func (td *TruncateDataset) session(ctx context.Context) sqlx.Session {
+	stmt := newStatement("TRUNCATE", td.clauses.Table())
+	return datasetSession(ctx, td.dialect, td.executor, stmt, td.clauses.Timeout())
+}
@@ -2,6 +2,7 @@ package builder_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Tooooommy/builder/v9"
@@ -78,6 +79,14 @@ func (tds *truncateDatasetSuite) TestGetClauses() {
 	tds.Equal(ce, ds.GetClauses())
 }
 
+func (tds *truncateDatasetSuite) TestWithTimeout() {
+	ds := builder.Truncate("test")
+	ce := exp.NewTruncateClauses().SetTable(exp.NewColumnListExpression(builder.I("test")))
+	tds.Equal(ce.SetTimeout(time.Second), ds.WithTimeout(time.Second).GetClauses())
+	tds.Equal(ce, ds.WithTimeout(time.Second).WithTimeout(0).GetClauses())
+	tds.Equal(ce, ds.GetClauses())
+}
+
 func (tds *truncateDatasetSuite) TestTable() {
 	bd := builder.Truncate("test")
 	tds.assertCases(
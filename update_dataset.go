@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Tooooommy/builder/v9/exp"
 	"github.com/Tooooommy/builder/v9/internal/errors"
@@ -16,21 +17,24 @@ type UpdateDataset struct {
 	isPrepared prepared
 	executor   sqlx.Session
 	err        error
+	// set to true to execute the statement without a WHERE or LIMIT clause, see AllowFullTable
+	allowFullTable bool
 }
 
 var ErrUnsupportedUpdateTableType = errors.New("unsupported table type, a string or identifier expression is required")
 
 // used internally by database to create a database with a specific adapter
-func newUpdateDataset(d string, executor sqlx.Session) *UpdateDataset {
+func newUpdateDataset(d string, executor sqlx.Session, s *settings) *UpdateDataset {
 	return &UpdateDataset{
-		clauses:  exp.NewUpdateClauses(),
-		dialect:  GetDialect(d),
-		executor: executor,
+		clauses:    exp.NewUpdateClauses(),
+		dialect:    s.dialect(d),
+		executor:   executor,
+		isPrepared: s.isPrepared(),
 	}
 }
 
 func Update(table any) *UpdateDataset {
-	return newUpdateDataset("default", nil).Table(table)
+	return newUpdateDataset("default", nil, nil).Table(table)
 }
 
 // Set the parameter interpolation behavior. See examples
@@ -49,7 +53,7 @@ func (ud *UpdateDataset) IsPrepared() bool {
 // Sets the adapter used to serialize values and create the SQL statement
 func (ud *UpdateDataset) WithDialect(dl string) *UpdateDataset {
 	ds := ud.copy(ud.GetClauses())
-	ds.dialect = GetDialect(dl)
+	ds.dialect = inheritSettings(GetDialect(dl), ds.dialect)
 	return ds
 }
 
@@ -79,17 +83,43 @@ func (ud *UpdateDataset) GetClauses() exp.UpdateClauses {
 	return ud.clauses
 }
 
+// Sets a timeout for executing the statement of this dataset. The context passed to ExecCtx etc. times out after
+// timeout, where supported by the dialect the timeout is also enforced by the database (e.g. SET LOCAL
+// statement_timeout inside of postgres transactions).
+//
+// A timeout less than or equal to zero removes the timeout.
+func (ud *UpdateDataset) WithTimeout(timeout time.Duration) *UpdateDataset {
+	return ud.copy(ud.clauses.SetTimeout(timeout))
+}
+
+// Adds optimizer hints to the statement, the hints are rendered in a single comment after the UPDATE keyword. Calling
+// OptimizerHints multiple times appends the hints.
+//
+//	builder.Update("test").OptimizerHints("NO_ICP(test)") -> UPDATE /*+ NO_ICP(test) */ ...
+func (ud *UpdateDataset) OptimizerHints(hints ...string) *UpdateDataset {
+	return ud.copy(ud.clauses.OptimizerHintsAppend(hints...))
+}
+
 // used internally to copy the dataset
 func (ud *UpdateDataset) copy(clauses exp.UpdateClauses) *UpdateDataset {
 	return &UpdateDataset{
-		dialect:    ud.dialect,
-		clauses:    clauses,
-		isPrepared: ud.isPrepared,
-		executor:   ud.executor,
-		err:        ud.err,
+		dialect:        ud.dialect,
+		clauses:        clauses,
+		isPrepared:     ud.isPrepared,
+		executor:       ud.executor,
+		err:            ud.err,
+		allowFullTable: ud.allowFullTable,
 	}
 }
 
+// Allows the statement to be executed without a WHERE or LIMIT clause, updating every row of the table. See
+// Database#AllowFullTable
+func (ud *UpdateDataset) AllowFullTable() *UpdateDataset {
+	ret := ud.copy(ud.clauses)
+	ret.allowFullTable = true
+	return ret
+}
+
 // Creates a WITH clause for a common table expression (CTE).
 //
 // The name will be available to use in the UPDATE from in the associated query; and can optionally
@@ -112,6 +142,18 @@ func (ud *UpdateDataset) WithRecursive(name string, subquery exp.Expression) *Up
 	return ud.copy(ud.clauses.CommonTablesAppend(exp.NewCommonTableExpression(true, name, subquery)))
 }
 
+// Adds common table expressions (CTE) created with CTE to the WITH clause. Unlike With the column names,
+// materialization and the SEARCH and CYCLE clauses of the CTEs are typed.
+//
+//	WithCTE(builder.CTE("tree", sub).Columns("id", "parent_id").NotMaterialized())
+func (ud *UpdateDataset) WithCTE(ctes ...exp.CommonTableExpression) *UpdateDataset {
+	clauses := ud.clauses
+	for _, cte := range ctes {
+		clauses = clauses.CommonTablesAppend(cte)
+	}
+	return ud.copy(clauses)
+}
+
 // Sets the table to update.
 func (ud *UpdateDataset) Table(table any) *UpdateDataset {
 	switch t := table.(type) {
@@ -186,7 +228,8 @@ func (ud *UpdateDataset) ClearLimit() *UpdateDataset {
 
 // Adds a RETURNING clause to the dataset if the adapter supports it. See examples.
 func (ud *UpdateDataset) Returning(returning ...any) *UpdateDataset {
-	return ud.copy(ud.clauses.SetReturning(exp.NewColumnListExpression(returning...)))
+	cols := exp.NewColumnListExpressionWithOptions(columnMapOptionsOf(ud.dialect), returning...)
+	return ud.copy(ud.clauses.SetReturning(cols))
 }
 
 // Get any error that has been set or nil if no error has been set.
@@ -244,7 +287,7 @@ func (ud *UpdateDataset) ExecCtx(ctx context.Context) (sql.Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ud.executor.ExecCtx(ctx, query, args...)
+	return ud.session(ctx).ExecCtx(ctx, query, args...)
 }
 
 func (ud *UpdateDataset) QueryRow(v any) error {
@@ -256,7 +299,7 @@ func (ud *UpdateDataset) QueryRowCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return ud.executor.QueryRowCtx(ctx, v, query, args...)
+	return ud.session(ctx).QueryRowCtx(ctx, v, query, args...)
 }
 
 func (ud *UpdateDataset) QueryRowPartial(v any) error {
@@ -268,7 +311,7 @@ func (ud *UpdateDataset) QueryRowPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return ud.executor.QueryRowPartialCtx(ctx, v, query, args...)
+	return ud.session(ctx).QueryRowPartialCtx(ctx, v, query, args...)
 }
 
 func (ud *UpdateDataset) QueryRows(v any) error {
@@ -280,7 +323,7 @@ func (ud *UpdateDataset) QueryRowsCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return ud.executor.QueryRowsCtx(ctx, v, query, args...)
+	return ud.session(ctx).QueryRowsCtx(ctx, v, query, args...)
 }
 
 func (ud *UpdateDataset) QueryRowsPartial(v any) error {
@@ -292,16 +335,36 @@ func (ud *UpdateDataset) QueryRowsPartialCtx(ctx context.Context, v any) error {
 	if err != nil {
 		return err
 	}
-	return ud.executor.QueryRowsPartialCtx(ctx, v, query, args...)
+	return ud.session(ctx).QueryRowsPartialCtx(ctx, v, query, args...)
 }
 
 func (ud *UpdateDataset) buildSQL() (string, []any, error) {
 	if ud.executor == nil {
 		return "", nil, ErrExecutorNotFoundError
 	}
+	hasLimit := ud.clauses.HasLimit() && rendersLimit(ud.dialect, func(opts *SQLDialectOptions) bool {
+		return opts.SupportsLimitOnUpdate
+	})
+	if !ud.allowFullTable && refusesFullTable(ud.executor) && isFullTable(ud.clauses.Where(), hasLimit) {
+		return "", nil, ErrFullTableNotAllowed
+	}
 	return ud.updateSQLBuilder().ToSQL()
 }
 
+// Generates the fingerprint of the UPDATE statement, the values of the dataset are replaced with placeholders so
+// datasets with the same shape have the same fingerprint. See Fingerprint.
+//
+// Errors:
+//   - There is an error generating the SQL
+func (ud *UpdateDataset) Fingerprint() (Fingerprint, error) {
+	if ud.err != nil {
+		return Fingerprint{}, ud.err
+	}
+	return fingerprint(func(b sb.SQLBuilder) {
+		ud.dialect.ToUpdateSQL(b, ud.clauses)
+	})
+}
+
 func (ud *UpdateDataset) updateSQLBuilder() sb.SQLBuilder {
 	buf := sb.NewSQLBuilder(ud.isPrepared.Bool())
 	if ud.err != nil {
@@ -310,3 +373,9 @@ func (ud *UpdateDataset) updateSQLBuilder() sb.SQLBuilder {
 	ud.dialect.ToUpdateSQL(buf, ud.clauses)
 	return buf
 }
+
+// returns the session to execute statements with, see datasetSession
+func (ud *UpdateDataset) session(ctx context.Context) sqlx.Session {
+	stmt := newStatement("UPDATE", ud.clauses.Table())
+	return datasetSession(ctx, ud.dialect, ud.executor, stmt, ud.clauses.Timeout())
+}
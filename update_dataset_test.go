@@ -2,6 +2,7 @@ package builder_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Tooooommy/builder/v9"
@@ -85,6 +86,25 @@ func (uds *updateDatasetSuite) TestGetClauses() {
 	uds.Equal(ce, ds.GetClauses())
 }
 
+func (uds *updateDatasetSuite) TestWithTimeout() {
+	ds := builder.Update("test")
+	ce := exp.NewUpdateClauses().SetTable(builder.I("test"))
+	uds.Equal(ce.SetTimeout(time.Second), ds.WithTimeout(time.Second).GetClauses())
+	uds.Equal(ce, ds.WithTimeout(time.Second).WithTimeout(0).GetClauses())
+	uds.Equal(ce, ds.GetClauses())
+}
+
+func (uds *updateDatasetSuite) TestOptimizerHints() {
+	ds := builder.Update("test")
+	ce := exp.NewUpdateClauses().SetTable(builder.I("test"))
+	uds.Equal(ce.OptimizerHintsAppend("NO_ICP(test)"), ds.OptimizerHints("NO_ICP(test)").GetClauses())
+	uds.Equal(
+		ce.OptimizerHintsAppend("NO_ICP(test)", "BKA(test)"),
+		ds.OptimizerHints("NO_ICP(test)").OptimizerHints("BKA(test)").GetClauses(),
+	)
+	uds.Equal(ce, ds.GetClauses())
+}
+
 func (uds *updateDatasetSuite) TestWith() {
 	from := builder.Update("cte")
 	bd := builder.Update("items")
@@ -119,6 +139,23 @@ func (uds *updateDatasetSuite) TestWithRecursive() {
 	)
 }
 
+func (uds *updateDatasetSuite) TestWithCTE() {
+	cte := builder.CTE("test-cte", builder.From("cte")).NotMaterialized()
+	bd := builder.Update("items")
+	uds.assertCases(
+		updateTestCase{
+			ds: bd.WithCTE(cte),
+			clauses: exp.NewUpdateClauses().
+				SetTable(builder.C("items")).
+				CommonTablesAppend(cte),
+		},
+		updateTestCase{
+			ds:      bd,
+			clauses: exp.NewUpdateClauses().SetTable(builder.C("items")),
+		},
+	)
+}
+
 func (uds *updateDatasetSuite) TestTable() {
 	bd := builder.Update("items")
 	uds.assertCases(